@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	app "github.com/hasmcp/sser/internal/_app"
+	"github.com/hasmcp/sser/internal/servicer/config"
 	zlog "github.com/rs/zerolog/log"
 )
 
@@ -18,6 +20,13 @@ const (
 )
 
 func main() {
+	validateOnly := flag.Bool("validate-config", false, "parse and cross-check the configuration, print a report and exit")
+	flag.Parse()
+
+	if *validateOnly {
+		os.Exit(validateConfig())
+	}
+
 	startTime := time.Now().UTC()
 	app, err := app.New()
 
@@ -47,6 +56,17 @@ func main() {
 		syscall.SIGINT,  // interrupt: stopped by Ctrl + C
 	)
 
+	reloadCh := make(chan os.Signal, 1)
+	signal.Notify(reloadCh, syscall.SIGHUP) // reload: config re-read on `kill -HUP PID`
+	go func() {
+		for range reloadCh {
+			zlog.Info().Msg(logPrefix + "SIGHUP received; reloading configuration")
+			if err := app.Config.Reload(); err != nil {
+				zlog.Error().Err(err).Msg(logPrefix + "failed to reload configuration; keeping the previous one")
+			}
+		}
+	}()
+
 	go func() {
 		defer func() {
 			zlog.Info().Msg(logPrefix + "shutting down the app")
@@ -68,3 +88,21 @@ func main() {
 	app.Stop(ctx)
 	zlog.Info().Dur("latency", time.Since(stopTime)).Msg(logPrefix + "app stop latency")
 }
+
+// validateConfig loads and cross-checks the configuration without starting
+// any subsystem, printing a report suitable for CI and deploy pipelines;
+// returns the process exit code
+func validateConfig() int {
+	cfg, err := config.New()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load configuration: %v\n", err)
+		return 1
+	}
+
+	report := config.Validate(cfg)
+	fmt.Println(report.String())
+	if !report.OK() {
+		return 1
+	}
+	return 0
+}
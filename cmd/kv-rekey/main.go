@@ -0,0 +1,39 @@
+// Command kv-rekey re-encrypts every record in the KV store with the active
+// encryption key. Run it after rotating keys (or after enabling encryption on
+// a store that still holds plaintext records) while the api-server is stopped.
+package main
+
+import (
+	"context"
+
+	"github.com/hasmcp/sser/internal/recorder/kv"
+	"github.com/hasmcp/sser/internal/servicer/config"
+	"github.com/hasmcp/sser/internal/servicer/log"
+	zlog "github.com/rs/zerolog/log"
+)
+
+const logPrefix = "[kv-rekey] "
+
+func main() {
+	cfg, err := config.New()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg(logPrefix + "failed to load configuration")
+	}
+
+	if _, err := log.New(log.Params{Config: cfg}); err != nil {
+		zlog.Fatal().Err(err).Msg(logPrefix + "failed to init logging")
+	}
+
+	rec, err := kv.New(kv.Params{Config: cfg})
+	if err != nil {
+		zlog.Fatal().Err(err).Msg(logPrefix + "failed to open the kv store")
+	}
+	defer rec.Close()
+
+	cnt, err := kv.Rekey(context.Background(), rec)
+	if err != nil {
+		zlog.Fatal().Err(err).Int("rewritten", cnt).Msg(logPrefix + "rekey failed")
+	}
+
+	zlog.Info().Int("rewritten", cnt).Msg(logPrefix + "rekey completed")
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type benchOptions struct {
+	url         string
+	apiToken    string
+	subscribers int
+	publishers  int
+	events      int
+	payloadSize int
+	persist     bool
+	grace       time.Duration
+}
+
+// benchPayload is the message body published during a run; the sent timestamp
+// drives the end-to-end latency measurement.
+type benchPayload struct {
+	Seq     int    `json:"seq"`
+	SentAt  int64  `json:"sent_at"`
+	Padding string `json:"padding,omitempty"`
+}
+
+// benchRecorder collects per-delivery latencies across all subscribers.
+type benchRecorder struct {
+	mutex     sync.Mutex
+	latencies []time.Duration
+}
+
+func (r *benchRecorder) record(latency time.Duration) {
+	r.mutex.Lock()
+	r.latencies = append(r.latencies, latency)
+	r.mutex.Unlock()
+}
+
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	url := fs.String("url", "http://localhost:8889", "base URL of the sser server")
+	apiToken := fs.String("api-token", os.Getenv("SSER_API_ACCESS_TOKEN"), "API access token used to create the benchmark topic (defaults to $SSER_API_ACCESS_TOKEN)")
+	subscribers := fs.Int("subscribers", 10, "number of concurrent subscribers")
+	publishers := fs.Int("publishers", 2, "number of concurrent publishers")
+	events := fs.Int("events", 100, "events published per publisher")
+	payloadSize := fs.Int("payload-size", 256, "approximate payload size in bytes")
+	grace := fs.Duration("grace", 3*time.Second, "wait after the last publish for in-flight deliveries")
+	persist := fs.Bool("persist", false, "create a persistent topic so the run measures throughput with storage writes on the publish path")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: sser bench [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *apiToken == "" {
+		return fmt.Errorf("an API access token is required; pass -api-token or set SSER_API_ACCESS_TOKEN")
+	}
+	if *subscribers < 1 || *publishers < 1 || *events < 1 {
+		return fmt.Errorf("subscribers, publishers and events must all be >= 1")
+	}
+
+	return bench(benchOptions{
+		url:         strings.TrimSuffix(*url, "/"),
+		apiToken:    *apiToken,
+		subscribers: *subscribers,
+		publishers:  *publishers,
+		events:      *events,
+		payloadSize: *payloadSize,
+		persist:     *persist,
+		grace:       *grace,
+	})
+}
+
+func bench(opts benchOptions) error {
+	topicID, topicToken, err := benchCreateTopic(opts)
+	if err != nil {
+		return fmt.Errorf("failed to create benchmark topic: %w", err)
+	}
+	defer benchDeleteTopic(opts, topicID)
+
+	fmt.Printf("benchmarking topic %s: %d subscribers, %d publishers x %d events\n",
+		topicID, opts.subscribers, opts.publishers, opts.events)
+
+	recorder := &benchRecorder{}
+	stopCh := make(chan struct{})
+	subReady := sync.WaitGroup{}
+	subDone := sync.WaitGroup{}
+	for i := 0; i < opts.subscribers; i++ {
+		subReady.Add(1)
+		subDone.Add(1)
+		go func() {
+			defer subDone.Done()
+			if err := benchSubscribe(opts, topicID, topicToken, recorder, &subReady, stopCh); err != nil {
+				fmt.Fprintln(os.Stderr, logPrefix+"subscriber error: "+err.Error())
+			}
+		}()
+	}
+	subReady.Wait()
+
+	padding := strings.Repeat("x", opts.payloadSize)
+	start := time.Now()
+	pubDone := sync.WaitGroup{}
+	pubErrs := make(chan error, opts.publishers)
+	for p := 0; p < opts.publishers; p++ {
+		pubDone.Add(1)
+		go func(p int) {
+			defer pubDone.Done()
+			for i := 0; i < opts.events; i++ {
+				if err := benchPublish(opts, topicID, p*opts.events+i, padding); err != nil {
+					pubErrs <- err
+					return
+				}
+			}
+		}(p)
+	}
+	pubDone.Wait()
+	close(pubErrs)
+	for err := range pubErrs {
+		return fmt.Errorf("publish failed mid-run: %w", err)
+	}
+	publishElapsed := time.Since(start)
+
+	// let in-flight deliveries land before closing the streams
+	time.Sleep(opts.grace)
+	close(stopCh)
+	subDone.Wait()
+
+	benchReport(opts, recorder, publishElapsed)
+	return nil
+}
+
+func benchCreateTopic(opts benchOptions) (string, string, error) {
+	settings := "{}"
+	if opts.persist {
+		settings = `{"pubsub": {"persist": true}}`
+	}
+	req, err := http.NewRequest(http.MethodPost, opts.url+"/api/v1/pubsubs", bytes.NewBufferString(settings))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+opts.apiToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return "", "", fmt.Errorf("server returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		PubSub struct {
+			ID    string `json:"id"`
+			Token string `json:"token"`
+		} `json:"pubsub"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", "", err
+	}
+	return result.PubSub.ID, result.PubSub.Token, nil
+}
+
+func benchDeleteTopic(opts benchOptions, topicID string) {
+	req, err := http.NewRequest(http.MethodDelete, opts.url+"/api/v1/pubsubs/"+topicID, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+opts.apiToken)
+	if resp, err := http.DefaultClient.Do(req); err == nil {
+		resp.Body.Close()
+	}
+}
+
+func benchPublish(opts benchOptions, topicID string, seq int, padding string) error {
+	payload := benchPayload{
+		Seq:     seq,
+		SentAt:  time.Now().UnixNano(),
+		Padding: padding,
+	}
+	message, _ := json.Marshal(payload)
+	body, _ := json.Marshal(map[string]map[string]string{
+		"event": {"message": string(message)},
+	})
+
+	req, err := http.NewRequest(http.MethodPost, opts.url+"/api/v1/pubsubs/"+topicID+"/events", bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+opts.apiToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		raw, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("server returned %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// benchSubscribe streams the benchmark topic and records one latency sample
+// per delivered benchmark event until stopCh closes.
+func benchSubscribe(opts benchOptions, topicID, topicToken string, recorder *benchRecorder, ready *sync.WaitGroup, stopCh <-chan struct{}) error {
+	client := &http.Client{}
+	req, err := http.NewRequest(http.MethodGet, opts.url+"/api/v1/pubsubs/"+topicID+"/events", nil)
+	if err != nil {
+		ready.Done()
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+topicToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		ready.Done()
+		return err
+	}
+	ready.Done()
+	defer resp.Body.Close()
+
+	go func() {
+		<-stopCh
+		resp.Body.Close()
+	}()
+
+	var data bytes.Buffer
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if data.Len() > 0 {
+				benchRecordDelivery(data.Bytes(), recorder)
+			}
+			data.Reset()
+			continue
+		}
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data.WriteString(strings.TrimPrefix(value, " "))
+		}
+	}
+	select {
+	case <-stopCh:
+		return nil // closed by the run teardown
+	default:
+		return scanner.Err()
+	}
+}
+
+func benchRecordDelivery(data []byte, recorder *benchRecorder) {
+	// the envelope wraps the published message; the benchmark payload is
+	// embedded as a JSON string in its data field
+	var envelope struct {
+		Data string `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil || envelope.Data == "" {
+		return
+	}
+	var payload benchPayload
+	if err := json.Unmarshal([]byte(envelope.Data), &payload); err != nil || payload.SentAt == 0 {
+		return
+	}
+	recorder.record(time.Since(time.Unix(0, payload.SentAt)))
+}
+
+func benchReport(opts benchOptions, recorder *benchRecorder, publishElapsed time.Duration) {
+	published := opts.publishers * opts.events
+	expected := published * opts.subscribers
+
+	recorder.mutex.Lock()
+	latencies := recorder.latencies
+	recorder.mutex.Unlock()
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	received := len(latencies)
+	loss := float64(expected-received) / float64(expected) * 100
+
+	fmt.Printf("\npublished   %d events in %v (%.0f events/s)\n",
+		published, publishElapsed.Round(time.Millisecond), float64(published)/publishElapsed.Seconds())
+	fmt.Printf("delivered   %d of %d expected (loss: %.2f%%)\n", received, expected, loss)
+	if received == 0 {
+		return
+	}
+	fmt.Printf("latency     p50=%v p90=%v p99=%v max=%v\n",
+		percentile(latencies, 50).Round(time.Microsecond),
+		percentile(latencies, 90).Round(time.Microsecond),
+		percentile(latencies, 99).Round(time.Microsecond),
+		latencies[received-1].Round(time.Microsecond))
+}
+
+// percentile returns the p-th percentile of a sorted latency slice.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := (len(sorted)*p + 99) / 100
+	if idx > 0 {
+		idx--
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// runExport pulls the encrypted topic bundle off a server and writes it to a
+// file, ready to be replayed on another instance with the import command.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	url := fs.String("url", "http://localhost:8889", "base URL of the sser server")
+	token := fs.String("token", os.Getenv("SSER_API_ACCESS_TOKEN"), "API access token (defaults to $SSER_API_ACCESS_TOKEN)")
+	key := fs.String("key", os.Getenv("SSER_BUNDLE_KEY"), "hex-encoded AES key sealing the bundle (defaults to $SSER_BUNDLE_KEY)")
+	out := fs.String("out", "topics.bundle", "path to write the encrypted bundle to")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: sser export [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *token == "" {
+		return fmt.Errorf("an API access token is required; pass -token or set SSER_API_ACCESS_TOKEN")
+	}
+	if *key == "" {
+		return fmt.Errorf("a bundle key is required; pass -key or set SSER_BUNDLE_KEY")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, *url+"/api/v1/admin/export", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+*token)
+	req.Header.Set("X-Bundle-Key", *key)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("export failed with status %d: %s", res.StatusCode, string(body))
+	}
+
+	if err := os.WriteFile(*out, body, 0o600); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %d bytes to %s\n", len(body), *out)
+	return nil
+}
+
+// runImport replays a bundle produced by the export command on another
+// server; topics that already exist there are skipped.
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	url := fs.String("url", "http://localhost:8889", "base URL of the sser server")
+	token := fs.String("token", os.Getenv("SSER_API_ACCESS_TOKEN"), "API access token (defaults to $SSER_API_ACCESS_TOKEN)")
+	key := fs.String("key", os.Getenv("SSER_BUNDLE_KEY"), "hex-encoded AES key the bundle was sealed with (defaults to $SSER_BUNDLE_KEY)")
+	in := fs.String("in", "topics.bundle", "path of the encrypted bundle to replay")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: sser import [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *token == "" {
+		return fmt.Errorf("an API access token is required; pass -token or set SSER_API_ACCESS_TOKEN")
+	}
+	if *key == "" {
+		return fmt.Errorf("a bundle key is required; pass -key or set SSER_BUNDLE_KEY")
+	}
+
+	bundle, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer bundle.Close()
+
+	req, err := http.NewRequest(http.MethodPost, *url+"/api/v1/admin/import", bundle)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+*token)
+	req.Header.Set("X-Bundle-Key", *key)
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("import failed with status %d: %s", res.StatusCode, string(body))
+	}
+	fmt.Println(string(body))
+	return nil
+}
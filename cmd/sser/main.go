@@ -0,0 +1,66 @@
+// Command sser is the interactive command line companion for an sser server:
+// it tails topics with pretty-printed payloads and filters so debugging a
+// live stream doesn't require hand-crafted curl invocations.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const logPrefix = "[sser-cli] "
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "tail":
+		if err := runTail(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, logPrefix+err.Error())
+			os.Exit(1)
+		}
+	case "bench":
+		if err := runBench(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, logPrefix+err.Error())
+			os.Exit(1)
+		}
+	case "export":
+		if err := runExport(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, logPrefix+err.Error())
+			os.Exit(1)
+		}
+	case "import":
+		if err := runImport(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, logPrefix+err.Error())
+			os.Exit(1)
+		}
+	case "backup":
+		if err := runBackup(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, logPrefix+err.Error())
+			os.Exit(1)
+		}
+	case "help", "-h", "--help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, logPrefix+"unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprint(os.Stderr, `Usage: sser <command> [flags]
+
+Commands:
+  tail <topic-id>   stream a topic's events to the terminal
+  bench             load-test a server and report latency percentiles
+  export            save topic metadata and tokens to an encrypted bundle
+  import            replay an exported bundle on another instance
+  backup            stream a consistent snapshot of the server's KV store
+
+Run 'sser <command> -h' for command flags.
+`)
+}
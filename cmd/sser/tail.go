@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// ANSI escapes for pretty output; suppressed with --no-color or when stdout
+// is not a terminal.
+const (
+	colorReset  = "\x1b[0m"
+	colorDim    = "\x1b[2m"
+	colorCyan   = "\x1b[36m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorPurple = "\x1b[35m"
+)
+
+type tailOptions struct {
+	url     string
+	token   string
+	filters map[string]string
+	since   time.Duration
+	raw     bool
+	color   bool
+}
+
+// filterFlag collects repeated --filter key=value pairs.
+type filterFlag map[string]string
+
+func (f filterFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f filterFlag) Set(raw string) error {
+	parts := strings.SplitN(raw, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("filter must be key=value, got %q", raw)
+	}
+	f[parts[0]] = parts[1]
+	return nil
+}
+
+func runTail(args []string) error {
+	fs := flag.NewFlagSet("tail", flag.ExitOnError)
+	url := fs.String("url", "http://localhost:8889", "base URL of the sser server")
+	token := fs.String("token", os.Getenv("SSER_TOPIC_ACCESS_TOKEN"), "topic access token (defaults to $SSER_TOPIC_ACCESS_TOKEN)")
+	since := fs.Duration("since", 0, "only print events published within the given duration (e.g. 5m)")
+	raw := fs.Bool("raw", false, "write raw SSE frames without parsing or coloring")
+	noColor := fs.Bool("no-color", false, "disable colored output")
+	filters := filterFlag{}
+	fs.Var(filters, "filter", "only print events matching key=value; keys: type, id (repeatable)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: sser tail [flags] <topic-id>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one topic id")
+	}
+	if *token == "" {
+		return fmt.Errorf("a topic access token is required; pass -token or set SSER_TOPIC_ACCESS_TOKEN")
+	}
+
+	opts := tailOptions{
+		url:     strings.TrimSuffix(*url, "/"),
+		token:   *token,
+		filters: filters,
+		since:   *since,
+		raw:     *raw,
+		color:   !*noColor && term.IsTerminal(int(os.Stdout.Fd())),
+	}
+	return tail(fs.Arg(0), opts)
+}
+
+func tail(topicID string, opts tailOptions) error {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/pubsubs/%s/events", opts.url, topicID), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+opts.token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("server returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if opts.raw {
+		_, err := io.Copy(os.Stdout, resp.Body)
+		return err
+	}
+	return tailParsed(resp.Body, opts)
+}
+
+// tailParsed assembles SSE frames and prints one line (or pretty block) per
+// event, applying the type/id filters and the --since window.
+func tailParsed(body io.Reader, opts tailOptions) error {
+	var id, eventType string
+	var data bytes.Buffer
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if data.Len() > 0 || eventType != "" || id != "" {
+				printEvent(id, eventType, data.Bytes(), opts)
+				if eventType == "close" {
+					return nil
+				}
+			}
+			id, eventType = "", ""
+			data.Reset()
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // comment keepalive
+		}
+		name, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch name {
+		case "id":
+			id = value
+		case "event":
+			eventType = value
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		}
+	}
+	return scanner.Err()
+}
+
+func printEvent(id, eventType string, data []byte, opts tailOptions) {
+	// the envelope carries the published event type and timestamp; fall back
+	// to the SSE frame fields for non-enveloped payloads
+	var envelope struct {
+		Type        string          `json:"type"`
+		PublishedAt time.Time       `json:"published_at"`
+		Data        json.RawMessage `json:"data"`
+	}
+	payload := data
+	if err := json.Unmarshal(data, &envelope); err == nil && !envelope.PublishedAt.IsZero() {
+		if envelope.Type != "" && eventType == "" {
+			eventType = envelope.Type
+		}
+		if len(envelope.Data) > 0 {
+			payload = envelope.Data
+		}
+	}
+
+	if want, ok := opts.filters["type"]; ok && want != eventType {
+		return
+	}
+	if want, ok := opts.filters["id"]; ok && want != id {
+		return
+	}
+	if opts.since > 0 && !envelope.PublishedAt.IsZero() && time.Since(envelope.PublishedAt) > opts.since {
+		return
+	}
+
+	timestamp := time.Now().UTC()
+	if !envelope.PublishedAt.IsZero() {
+		timestamp = envelope.PublishedAt.UTC()
+	}
+
+	header := fmt.Sprintf("%s %s %s",
+		colorize(timestamp.Format(time.RFC3339), colorDim, opts.color),
+		colorize(orDefault(eventType, "message"), colorCyan, opts.color),
+		colorize(id, colorDim, opts.color),
+	)
+	fmt.Println(strings.TrimSpace(header) + " " + prettyJSON(payload, opts.color))
+}
+
+// prettyJSON indents and colors a JSON payload; non-JSON data passes through
+// untouched.
+func prettyJSON(data []byte, color bool) string {
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, data, "", "  "); err != nil {
+		return string(data)
+	}
+	if !color {
+		return indented.String()
+	}
+	return colorizeJSON(indented.String())
+}
+
+// colorizeJSON applies a minimal highlight: keys purple, strings green,
+// numbers and literals yellow.
+func colorizeJSON(s string) string {
+	var out strings.Builder
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == '"':
+			j := i + 1
+			for j < len(s) && (s[j] != '"' || s[j-1] == '\\') {
+				j++
+			}
+			token := s[i : j+1]
+			// a quote followed by a colon is an object key
+			if k := j + 1; k < len(s) && s[k] == ':' {
+				out.WriteString(colorPurple + token + colorReset)
+			} else {
+				out.WriteString(colorGreen + token + colorReset)
+			}
+			i = j + 1
+		case c >= '0' && c <= '9', c == '-':
+			j := i
+			for j < len(s) && strings.ContainsRune("0123456789.eE+-", rune(s[j])) {
+				j++
+			}
+			out.WriteString(colorYellow + s[i:j] + colorReset)
+			i = j
+		case c == 't' || c == 'f' || c == 'n':
+			j := i
+			for j < len(s) && s[j] >= 'a' && s[j] <= 'z' {
+				j++
+			}
+			out.WriteString(colorYellow + s[i:j] + colorReset)
+			i = j
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.String()
+}
+
+func colorize(s, color string, enabled bool) string {
+	if !enabled || s == "" {
+		return s
+	}
+	return color + s + colorReset
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -3,11 +3,15 @@ module github.com/hasmcp/sser
 go 1.25.1
 
 require (
+	github.com/eclipse/paho.mqtt.golang v1.5.1
+	github.com/gorilla/websocket v1.5.3
+	github.com/lib/pq v1.12.3
 	github.com/mustafaturan/monoflake v1.2.0
 	github.com/rs/zerolog v1.34.0
 	github.com/valyala/fasthttp v1.67.0
 	go.etcd.io/bbolt v1.4.3
 	golang.org/x/crypto v0.42.0
+	golang.org/x/term v0.45.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -18,6 +22,7 @@ require (
 	github.com/mattn/go-isatty v0.0.19 // indirect
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
 	golang.org/x/net v0.45.0 // indirect
-	golang.org/x/sys v0.36.0 // indirect
+	golang.org/x/sync v0.17.0 // indirect
+	golang.org/x/sys v0.47.0 // indirect
 	golang.org/x/text v0.29.0 // indirect
 )
@@ -6,6 +6,7 @@ import (
 
 	"github.com/hasmcp/sser/internal/controller/pubsub"
 	"github.com/hasmcp/sser/internal/handler/http"
+	"github.com/hasmcp/sser/internal/handler/mqtt"
 	"github.com/hasmcp/sser/internal/recorder/kv"
 	"github.com/hasmcp/sser/internal/servicer/config"
 	"github.com/hasmcp/sser/internal/servicer/idgen"
@@ -19,6 +20,7 @@ type (
 		Log    log.Servicer
 		Server server.Servicer
 		KV     kv.Recorder
+		MQTT   mqtt.Handler
 	}
 )
 
@@ -59,15 +61,26 @@ func New() (*App, error) {
 	}
 
 	httpHandler, err := http.New(http.Params{
+		Config: config,
 		PubSub: pubsub,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	mqttHandler, err := mqtt.New(mqtt.Params{
+		Config: config,
+		PubSub: pubsub,
+	})
+	if err != nil && !errors.Is(err, mqtt.ErrNotEnabled) {
+		return nil, err
+	}
+
 	server, err := server.New(server.Params{
-		Config:  config,
-		Handler: httpHandler.Handle,
+		Config:        config,
+		Handler:       httpHandler.Handle,
+		PublicHandler: httpHandler.HandlePublic,
+		AdminHandler:  httpHandler.HandleAdmin,
 	})
 	if err != nil {
 		return nil, err
@@ -78,10 +91,17 @@ func New() (*App, error) {
 		Log:    log,
 		Server: server,
 		KV:     kvrecorder,
+		MQTT:   mqttHandler,
 	}, nil
 }
 
 func (a *App) Start(ctx context.Context) error {
+	if a.MQTT != nil {
+		if err := a.MQTT.Start(); err != nil {
+			return err
+		}
+	}
+
 	err := a.Server.ListenAndServe()
 	if err != nil {
 		return err
@@ -95,6 +115,12 @@ func (a *App) Stop(ctx context.Context) error {
 		return err
 	}
 
+	if a.MQTT != nil {
+		if err := a.MQTT.Stop(); err != nil {
+			return err
+		}
+	}
+
 	if a.KV != nil {
 		if err := a.KV.Close(); err != nil {
 			return err
@@ -0,0 +1,23 @@
+package entity
+
+import "context"
+
+// ctxKey keeps context values private to this package so unrelated string
+// keys can't collide with them.
+type ctxKey string
+
+const ctxKeyRequestID ctxKey = "requestID"
+
+// WithRequestID attaches the request id handed out by the HTTP layer so
+// controller logs can be correlated with access logs.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKeyRequestID, id)
+}
+
+// RequestIDFromContext returns the attached request id, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(ctxKeyRequestID).(string); ok {
+		return id
+	}
+	return ""
+}
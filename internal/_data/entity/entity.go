@@ -1,21 +1,188 @@
 package entity
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type (
 	CreatePubSubRequest struct {
 		ApiAccessToken string
+		Name           string
 		Persist        bool
+		Presence       bool
+		TokenTTL       time.Duration
+		TTL            time.Duration
+		IdleTTL        time.Duration
+		TickFrequency  time.Duration
+		RetryHint      time.Duration
+		Profile        string
+		// RetentionBufferSize overrides the instance-wide replay ring size
+		// for this topic; 0 keeps the default
+		RetentionBufferSize int
+		// Token pins the topic access token instead of generating a random
+		// one, so clients can pre-share it out of band
+		Token string
 	}
 
 	CreatePubSubResponse struct {
-		ID    int64
-		Token []byte
+		ID             int64
+		Token          []byte
+		TokenExpiresAt time.Time
 	}
 
 	DeletePubSubRequest struct {
 		ApiAccessToken string
 		ID             int64
+		// RedirectTo optionally names a replacement topic; it rides along in
+		// the deletion event so subscribers can migrate instead of treating
+		// the close as an unexplained death.
+		RedirectTo string
+	}
+
+	ListPubSubsRequest struct {
+		ApiAccessToken string
+	}
+
+	// PubSubSummary describes one live topic for the admin listing.
+	PubSubSummary struct {
+		ID          int64
+		Name        string
+		Tenant      string
+		Static      bool
+		Persist     bool
+		Paused      bool
+		Profile     string
+		Subscribers int
+	}
+
+	ListPubSubsResponse struct {
+		PubSubs []PubSubSummary
+	}
+
+	// ExportTopicsRequest asks for a portable bundle of topic metadata and
+	// tokens; Key is the hex-encoded AES key sealing the bundle so
+	// credentials never travel in the clear.
+	ExportTopicsRequest struct {
+		ApiAccessToken string
+		Key            string
+	}
+
+	ExportTopicsResponse struct {
+		// Bundle is the AES-GCM sealed JSON snapshot.
+		Bundle []byte
+	}
+
+	// ImportTopicsRequest replays an exported bundle on this instance; Key
+	// must match the key used at export time.
+	ImportTopicsRequest struct {
+		ApiAccessToken string
+		Key            string
+		Bundle         []byte
+	}
+
+	ImportTopicsResponse struct {
+		Imported int
+		Skipped  int
+	}
+
+	// BackupStoreRequest asks for a consistent snapshot of the whole KV
+	// store; the snapshot crosses tenants, so only the instance access token
+	// is accepted.
+	BackupStoreRequest struct {
+		ApiAccessToken string
+	}
+
+	BackupStoreResponse struct {
+		// Data is the raw snapshot exactly as the storage backend wrote it.
+		Data []byte
+	}
+
+	GetUsageRequest struct {
+		ApiAccessToken string
+		// From and To bound the requested range; zero values default to the
+		// last 24 hours ending now
+		From time.Time
+		To   time.Time
+	}
+
+	GetUsageResponse struct {
+		Rows []UsageRow
+	}
+
+	// UsageRow is one tenant's billable activity for one rollup window.
+	UsageRow struct {
+		Tenant            string
+		PeriodStart       time.Time
+		Publishes         int64
+		Delivered         int64
+		BytesIn           int64
+		BytesOut          int64
+		ConnectionSeconds int64
+	}
+
+	PausePubSubRequest struct {
+		ApiAccessToken string
+		ID             int64
+	}
+
+	SetSchemaRequest struct {
+		ApiAccessToken string
+		ID             int64
+		// Schema is the raw JSON Schema document to attach to the topic
+		Schema []byte
+	}
+
+	GetSchemaRequest struct {
+		ApiAccessToken string
+		ID             int64
+	}
+
+	GetSchemaResponse struct {
+		Schema []byte
+	}
+
+	DeleteSchemaRequest struct {
+		ApiAccessToken string
+		ID             int64
+	}
+
+	SetScriptRequest struct {
+		ApiAccessToken string
+		ID             int64
+		// Script is the raw script document to attach to the topic
+		Script []byte
+	}
+
+	GetScriptRequest struct {
+		ApiAccessToken string
+		ID             int64
+	}
+
+	GetScriptResponse struct {
+		Script []byte
+	}
+
+	DeleteScriptRequest struct {
+		ApiAccessToken string
+		ID             int64
+	}
+
+	ResumePubSubRequest struct {
+		ApiAccessToken string
+		ID             int64
+	}
+
+	// UpdatePubSubRequest carries a partial settings change for a topic; nil
+	// fields keep their current values
+	UpdatePubSubRequest struct {
+		ApiAccessToken      string
+		ID                  int64
+		TickFrequency       *time.Duration
+		RetryHint           *time.Duration
+		RetentionBufferSize *int
+		MaxSubscribers      *int
+		Persist             *bool
 	}
 
 	PublishRequest struct {
@@ -23,22 +190,112 @@ type (
 		PubSubID       int64
 		EventID        string
 		EventType      string
-		Message        []byte
+		IdempotencyKey string
+		// Key groups events into a compacted per-key stream: the controller
+		// remembers the latest value per key so snapshot subscribers get the
+		// topic's current state before live updates.
+		Key      string
+		Encoding string
+		// ContentType is the media type of the payload as declared by the
+		// publisher; it travels in the envelope so mixed-use topics can tell
+		// JSON, text and binary events apart.
+		ContentType string
+		KeyID       string
+		Headers     map[string]string
+		Message     []byte
+		// WaitDelivered defers the response until every enqueued delivery
+		// was accepted by its subscriber's queue, or WaitTimeout elapsed
+		WaitDelivered bool
+		WaitTimeout   time.Duration
 	}
 
 	PublishResponse struct {
 		ID int64
+		// Subscribers is how many subscribers were connected to the topic
+		// (and its routed targets) when the event entered the fan-out;
+		// Enqueued is how many deliveries were queued to them and Dropped is
+		// how many subscribers were skipped by their event filters. Delivery
+		// itself stays asynchronous, so these are enqueue-time counts.
+		Subscribers int
+		Enqueued    int
+		Dropped     int
+		// Waited marks a synchronous publish; Delivered, Failed and Pending
+		// then break the enqueued deliveries down into ones accepted by the
+		// subscriber's queue, ones that timed out against it, and ones still
+		// unconfirmed when the wait timeout elapsed.
+		Waited    bool
+		Delivered int
+		Failed    int
+		Pending   int
+		// Duplicate marks a publish dropped because its client-supplied
+		// event id was seen recently on the topic; ID is then the id of the
+		// original event.
+		Duplicate bool
+		// Filtered marks a publish stopped by the topic's script; no event
+		// was created, so ID is zero.
+		Filtered bool
 	}
 
 	SubscribeRequest struct {
+		PubSubID    int64
+		Token       []byte
+		Types       []string
+		Attrs       map[string]string
+		Transform   string
+		LastEventID string
+		// ResumptionToken reattaches the client to its previous subscriber
+		// identity instead of joining as a brand-new subscriber
+		ResumptionToken string
+		// Snapshot asks for the latest value of every event key ahead of the
+		// live stream
+		Snapshot bool
+	}
+
+	SubscribeResponse struct {
+		ID                 int64
+		Events             chan *Event
+		TickFrequency      time.Duration
+		MaxStreamLifetime  time.Duration
+		ReconnectRetryHint time.Duration
+		Profile            string
+		KeepaliveStyle     string
+		// ResumptionToken is handed to the client (header and initial SSE
+		// event) for presenting on its next reconnect
+		ResumptionToken string
+		// RetentionAvailable tells the client whether Last-Event-ID replay
+		// and long-polling work on this topic
+		RetentionAvailable bool
+		// MarkFlush is called by the transport after each successful socket
+		// flush so subscriber liveness stays observable
+		MarkFlush func()
+	}
+
+	PollRequest struct {
 		PubSubID int64
 		Token    []byte
+		Cursor   int64
 	}
 
-	SubscribeResponse struct {
-		ID            int64
-		Events        chan *Event
-		TickFrequency time.Duration
+	PollResponse struct {
+		Events []*Event
+		Cursor int64
+	}
+
+	GetSubscribersRequest struct {
+		PubSubID int64
+		Token    []byte
+	}
+
+	GetSubscribersResponse struct {
+		Subscribers []SubscriberInfo
+	}
+
+	SubscriberInfo struct {
+		ID          int64
+		ConnectedAt time.Time
+		// LastFlushAt is when the subscriber's socket last accepted a write;
+		// stale values flag half-dead connections.
+		LastFlushAt time.Time
 	}
 
 	UnsubscribeRequest struct {
@@ -47,12 +304,63 @@ type (
 		Token    []byte
 	}
 
+	UpsertStaticPubSubRequest struct {
+		ApiAccessToken string
+		ID             int64
+		Name           string
+		Token          string
+	}
+
+	DeleteStaticPubSubRequest struct {
+		ApiAccessToken string
+		ID             int64
+	}
+
+	QueryArchiveRequest struct {
+		ApiAccessToken string
+		PubSubID       int64
+		From           time.Time
+		To             time.Time
+		Limit          int
+	}
+
+	QueryArchiveResponse struct {
+		Events []ArchivedEvent
+	}
+
+	ArchivedEvent struct {
+		ID          string
+		Type        string
+		Data        []byte
+		PublishedAt time.Time
+	}
+
 	GetMetricsRequest struct {
 		MetricsAccessToken string
+		// Since turns counters into deltas measured from the newest retained
+		// snapshot not after it; Window is the relative shorthand (deltas
+		// over the trailing window). Since wins when both are set.
+		Since  time.Time
+		Window time.Duration
+	}
+
+	GetTopicRatesRequest struct {
+		MetricsAccessToken string
+		PubSubID           int64
+	}
+
+	GetTopicRatesResponse struct {
+		PubSubID   int64
+		LastSecond int64
+		Last10Secs int64
+		LastMinute int64
 	}
 
 	GetMetricsResponse struct {
 		Metrics []Metric
+		// Since is the snapshot time counter deltas were measured from; zero
+		// for an absolute query
+		Since time.Time
 	}
 
 	Metric struct {
@@ -64,5 +372,114 @@ type (
 		ID   string
 		Type string
 		Data []byte
+		// PublishedAt is when the event entered the fan-out; it feeds the
+		// publish-to-flush latency histogram and is not serialized.
+		PublishedAt time.Time
 	}
+
+	// Envelope is the structured wire format carried in the data field of every
+	// published event, so consumers can tell when and in what order an event
+	// was created.
+	Envelope struct {
+		ID          string    `json:"id"`
+		Type        string    `json:"type,omitempty"`
+		PublishedAt time.Time `json:"published_at"`
+		Encoding    string    `json:"encoding,omitempty"`
+		// ContentType is the publisher-declared media type of Data, so
+		// consumers on mixed-use topics can pick the right decoder.
+		ContentType string `json:"content_type,omitempty"`
+		// Key is the event's compaction key; the newest event per key makes
+		// up the topic's snapshot state.
+		Key string `json:"key,omitempty"`
+		// KeyID names the key a publisher used for end-to-end encryption; the
+		// server never sees the plaintext and carries the id opaquely so
+		// subscribers can pick the right key to decrypt with.
+		KeyID   string            `json:"key_id,omitempty"`
+		Data    json.RawMessage   `json:"data"`
+		Headers map[string]string `json:"headers,omitempty"`
+	}
+
+	// CloseReason explains why the server is terminating an SSE stream.
+	CloseReason string
 )
+
+const (
+	// EventTypeClose marks the terminal event delivered right before the
+	// server closes a subscriber stream.
+	EventTypeClose = "close"
+
+	// EventTypeReconnect asks the client to re-subscribe; it is emitted when a
+	// stream hits its maximum lifetime so load is spread over fresh
+	// connections.
+	EventTypeReconnect = "reconnect"
+
+	// EventTypeReady is the handshake event opening every SSE stream; it
+	// carries the subscriber id, topic id, server time, tick frequency and
+	// retention availability.
+	EventTypeReady = "ready"
+
+	// EventTypeResume carries the resumption token as the stream's first
+	// event, so browser clients (which cannot read response headers off
+	// EventSource) can still reattach after a disconnect.
+	EventTypeResume = "resume"
+
+	// EventTypeDeleted announces the topic's deletion right before the
+	// terminal close event; its data optionally carries a redirect hint
+	// naming a replacement topic.
+	EventTypeDeleted = "deleted"
+
+	// EventTypePaused tells subscribers the topic stopped accepting publishes
+	// for maintenance; the stream itself stays open.
+	EventTypePaused = "paused"
+	// EventTypeResumed tells subscribers a paused topic accepts publishes
+	// again.
+	EventTypeResumed = "resumed"
+
+	// EncodingBase64 marks an envelope whose data field carries raw bytes
+	// base64-encoded, so binary payloads survive the text-only SSE framing.
+	EncodingBase64 = "base64"
+
+	// KeepaliveStyleComment emits standard SSE comment lines (`: ping`) as the
+	// heartbeat; the default.
+	KeepaliveStyleComment = "comment"
+	// KeepaliveStyleEvent emits the legacy tick data events for clients that
+	// came to depend on them.
+	KeepaliveStyleEvent = "event"
+
+	// ProfileLowLatency flushes as soon as the queue is drained; the default.
+	ProfileLowLatency = "low-latency"
+	// ProfileThroughput lingers briefly for more events before flushing,
+	// trading latency for fuller batches on bulk streams.
+	ProfileThroughput = "throughput"
+
+	CloseReasonDeleted        CloseReason = "deleted"
+	CloseReasonExpired        CloseReason = "expired"
+	CloseReasonRevoked        CloseReason = "revoked"
+	CloseReasonServerShutdown CloseReason = "server-shutdown"
+	CloseReasonIdleTimeout    CloseReason = "idle-timeout"
+	CloseReasonSuperseded     CloseReason = "superseded"
+)
+
+// CloseEvent builds the terminal event for the given reason.
+func CloseEvent(reason CloseReason) *Event {
+	return &Event{
+		Type: EventTypeClose,
+		Data: []byte(`{"reason": "` + string(reason) + `"}`),
+	}
+}
+
+// DeletedEvent builds the deletion announcement; redirect, when set, points
+// clients at a replacement topic to re-subscribe to.
+func DeletedEvent(redirect string) *Event {
+	data := []byte(`{}`)
+	if redirect != "" {
+		// the hint is caller-supplied, so it goes through the encoder rather
+		// than string concatenation
+		hint, _ := json.Marshal(redirect)
+		data = append(append([]byte(`{"redirect": `), hint...), '}')
+	}
+	return &Event{
+		Type: EventTypeDeleted,
+		Data: data,
+	}
+}
@@ -6,7 +6,10 @@ import (
 
 type (
 	Err struct {
-		Code    int
+		Code int
+		// Kind is the stable machine-readable code from the error catalog;
+		// when empty the mapper falls back to the status-derived generic kind
+		Kind    ErrorKind
 		Message string
 		Details map[string]any
 	}
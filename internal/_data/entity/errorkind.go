@@ -0,0 +1,96 @@
+package entity
+
+type (
+	// ErrorKind is a stable machine-readable error code carried alongside the
+	// HTTP status in error responses, so SDKs can branch on codes instead of
+	// matching message strings.
+	ErrorKind string
+
+	// ErrorCatalogEntry documents one kind for the error catalog endpoint.
+	ErrorCatalogEntry struct {
+		Kind        ErrorKind
+		Status      int
+		Description string
+	}
+)
+
+const (
+	// generic kinds derived from the HTTP status when no specific kind applies
+	ErrorKindBadRequest   ErrorKind = "bad_request"
+	ErrorKindUnauthorized ErrorKind = "unauthorized"
+	ErrorKindForbidden    ErrorKind = "forbidden"
+	ErrorKindNotFound     ErrorKind = "not_found"
+	ErrorKindConflict     ErrorKind = "conflict"
+	ErrorKindTooLarge     ErrorKind = "too_large"
+	ErrorKindRateLimited  ErrorKind = "rate_limited"
+	ErrorKindInternal     ErrorKind = "internal"
+	ErrorKindUnavailable  ErrorKind = "unavailable"
+
+	// specific kinds set where the server knows exactly what went wrong
+	ErrorKindPubSubNotFound     ErrorKind = "pubsub_not_found"
+	ErrorKindTokenMismatch      ErrorKind = "token_mismatch"
+	ErrorKindTokenExpired       ErrorKind = "token_expired"
+	ErrorKindQuotaExceeded      ErrorKind = "quota_exceeded"
+	ErrorKindNameTaken          ErrorKind = "name_taken"
+	ErrorKindTopicPaused        ErrorKind = "topic_paused"
+	ErrorKindMessageTooLarge    ErrorKind = "message_too_large"
+	ErrorKindValidationFailed   ErrorKind = "validation_failed"
+	ErrorKindSchemaViolation    ErrorKind = "schema_violation"
+	ErrorKindStorageUnavailable ErrorKind = "storage_unavailable"
+	ErrorKindStorageFailure     ErrorKind = "storage_failure"
+)
+
+// ErrorCatalog lists every kind the server emits with its usual status; it
+// backs the documentation endpoint and is the reference for SDK authors.
+func ErrorCatalog() []ErrorCatalogEntry {
+	return []ErrorCatalogEntry{
+		{ErrorKindBadRequest, ErrorCodeBadRequest, "the request payload or parameters are malformed"},
+		{ErrorKindUnauthorized, ErrorCodeUnauthorized, "a required token is missing or not recognized"},
+		{ErrorKindForbidden, ErrorCodeForbidden, "the token is valid but does not grant access to the resource"},
+		{ErrorKindNotFound, ErrorCodeNotFound, "the requested resource does not exist"},
+		{ErrorKindConflict, ErrorCodeConflict, "the request conflicts with the current state of the resource"},
+		{ErrorKindTooLarge, ErrorCodeRequestEntityTooLarge, "the request body exceeds a server limit"},
+		{ErrorKindRateLimited, ErrorCodeTooManyRequests, "the caller exceeded a rate limit; retry later"},
+		{ErrorKindInternal, ErrorCodeInternalServerError, "an unexpected server-side failure"},
+		{ErrorKindUnavailable, ErrorCodeServiceUnavailable, "a dependency the request needs is not available"},
+		{ErrorKindPubSubNotFound, ErrorCodeNotFound, "no pubsub exists with the given id or name"},
+		{ErrorKindTokenMismatch, ErrorCodeUnauthorized, "the presented token does not match the pubsub or API token"},
+		{ErrorKindTokenExpired, ErrorCodeUnauthorized, "the pubsub token has expired; fetch a fresh one"},
+		{ErrorKindQuotaExceeded, ErrorCodeUnprocessableEntity, "a topic or subscriber quota is exhausted"},
+		{ErrorKindNameTaken, ErrorCodeConflict, "the requested topic name is already registered"},
+		{ErrorKindTopicPaused, ErrorCodeConflict, "the topic is paused and rejects publishes until resumed"},
+		{ErrorKindMessageTooLarge, ErrorCodeRequestEntityTooLarge, "the event payload exceeds the pubsub's max message size"},
+		{ErrorKindValidationFailed, ErrorCodeUnprocessableEntity, "one or more request fields failed validation"},
+		{ErrorKindSchemaViolation, ErrorCodeUnprocessableEntity, "the event payload violates the topic's attached schema"},
+		{ErrorKindStorageUnavailable, ErrorCodeBadRequest, "persistence was requested but no store is configured"},
+		{ErrorKindStorageFailure, ErrorCodeInternalServerError, "the persistent store returned an error"},
+	}
+}
+
+// KindForCode maps an HTTP status to its generic kind, used as the fallback
+// for errors raised without an explicit kind.
+func KindForCode(code int) ErrorKind {
+	switch code {
+	case ErrorCodeBadRequest:
+		return ErrorKindBadRequest
+	case ErrorCodeUnauthorized:
+		return ErrorKindUnauthorized
+	case ErrorCodeForbidden:
+		return ErrorKindForbidden
+	case ErrorCodeNotFound:
+		return ErrorKindNotFound
+	case ErrorCodeConflict:
+		return ErrorKindConflict
+	case ErrorCodeRequestEntityTooLarge:
+		return ErrorKindTooLarge
+	case ErrorCodeTooManyRequests:
+		return ErrorKindRateLimited
+	case ErrorCodeServiceUnavailable:
+		return ErrorKindUnavailable
+	default:
+		if code >= 500 {
+			return ErrorKindInternal
+		}
+		return ErrorKindBadRequest
+	}
+}
@@ -2,10 +2,23 @@ package view
 
 type Err struct {
 	Code    int                    `json:"code,omitempty"`
+	Kind    string                 `json:"kind,omitempty"`
 	Message string                 `json:"message,omitempty"`
 	Details map[string]interface{} `json:"details,omitempty"`
 }
 
+// ErrorCatalogEntry documents one stable error kind for the catalog endpoint.
+type ErrorCatalogEntry struct {
+	Kind        string `json:"kind"`
+	Status      int    `json:"status"`
+	Description string `json:"description"`
+}
+
+// ErrorCatalogResponse is the body of GET /api/v1/errors.
+type ErrorCatalogResponse struct {
+	Errors []ErrorCatalogEntry `json:"errors"`
+}
+
 func (e *Err) Error() string {
 	return e.Message
 }
@@ -1,31 +1,176 @@
 package view
 
+import "encoding/json"
+
 type (
 	CreatePubSubRequest struct {
-		Persist bool `yaml:"persist"`
+		Name          string `json:"name"`
+		Persist       bool   `yaml:"persist"`
+		Presence      bool   `json:"presence"`
+		TokenTTL      string `json:"token_ttl"`
+		TTL           string `json:"ttl"`
+		IdleTTL       string `json:"idle_ttl"`
+		TickFrequency string `json:"tick_frequency"`
+		RetryHint     string `json:"retry_hint"`
+		Profile       string `json:"profile"`
+		Retention     int    `json:"retention_buffer_size"`
+		Token         string `json:"token"`
+	}
+
+	UpdatePubSubRequest struct {
+		TickFrequency       string `json:"tick_frequency,omitempty"`
+		RetryHint           string `json:"retry_hint,omitempty"`
+		RetentionBufferSize *int   `json:"retention_buffer_size,omitempty"`
+		MaxSubscribers      *int   `json:"max_subscribers,omitempty"`
+		Persist             *bool  `json:"persist,omitempty"`
 	}
 
 	CreatePubSubResponse struct {
-		ID    string `json:"id"`
-		Token string `json:"token"`
+		ID             string `json:"id"`
+		Token          string `json:"token"`
+		TokenExpiresAt string `json:"token_expires_at,omitempty"`
 	}
 
 	PublishRequest struct {
-		ID      string `json:"id,omitempty"`
-		Type    string `json:"type,omitempty"`
-		Message string `json:"message"`
+		ID   string `json:"id,omitempty"`
+		Type string `json:"type,omitempty"`
+		// Key is the optional compaction key; the server keeps the newest
+		// event per key for snapshot subscribers.
+		Key     string            `json:"key,omitempty"`
+		Headers map[string]string `json:"headers,omitempty"`
+		// ContentType declares the media type of Message for consumers on
+		// mixed-use topics; optional.
+		ContentType string `json:"content_type,omitempty"`
+		Message     string `json:"message"`
 	}
 
 	PublishResponse struct {
-		ID string `json:"id"`
+		ID          string          `json:"id"`
+		Subscribers int             `json:"subscribers"`
+		Enqueued    int             `json:"enqueued"`
+		Dropped     int             `json:"dropped"`
+		Filtered    bool            `json:"filtered,omitempty"`
+		Outcome     *PublishOutcome `json:"outcome,omitempty"`
+	}
+
+	// PublishOutcome is only present on synchronous publishes
+	// (?wait=delivered) and breaks the enqueued deliveries down by result.
+	PublishOutcome struct {
+		Delivered int `json:"delivered"`
+		Failed    int `json:"failed"`
+		Pending   int `json:"pending"`
 	}
 
 	SubscribeRequest struct {
 		Token string `json:"token"`
 	}
 
+	PollEvent struct {
+		ID   string          `json:"id,omitempty"`
+		Type string          `json:"type,omitempty"`
+		Data json.RawMessage `json:"data"`
+	}
+
+	PollResponse struct {
+		Events []PollEvent `json:"events"`
+		Cursor int64       `json:"cursor"`
+	}
+
+	// PollResponseV2 is the v2 history shape: events are the structured
+	// envelopes verbatim and the cursor is an opaque token.
+	PollResponseV2 struct {
+		Events []json.RawMessage `json:"events"`
+		Cursor string            `json:"cursor"`
+	}
+
+	Subscriber struct {
+		ID          string `json:"id"`
+		ConnectedAt string `json:"connected_at"`
+		LastFlushAt string `json:"last_flush_at,omitempty"`
+	}
+
+	GetSubscribersResponse struct {
+		Subscribers []Subscriber `json:"subscribers"`
+		Count       int          `json:"count"`
+	}
+
+	// ReadyEvent is the data payload of the stream's initial `ready` SSE
+	// event, letting clients configure themselves without out-of-band
+	// knowledge.
+	ReadyEvent struct {
+		SubscriberID  string `json:"subscriber_id"`
+		PubSubID      string `json:"pubsub_id"`
+		ServerTime    string `json:"server_time"`
+		TickFrequency string `json:"tick_frequency"`
+		Retention     bool   `json:"retention"`
+	}
+
+	PubSubSummary struct {
+		ID          string `json:"id"`
+		Name        string `json:"name,omitempty"`
+		Tenant      string `json:"tenant,omitempty"`
+		Static      bool   `json:"static"`
+		Persist     bool   `json:"persist"`
+		Paused      bool   `json:"paused"`
+		Profile     string `json:"profile,omitempty"`
+		Subscribers int    `json:"subscribers"`
+	}
+
+	ListPubSubsResponse struct {
+		PubSubs []PubSubSummary `json:"pubsubs"`
+		Count   int             `json:"count"`
+	}
+
+	// UsageRow is one tenant's billable activity for one rollup window.
+	UsageRow struct {
+		Tenant            string `json:"tenant"`
+		PeriodStart       string `json:"period_start"`
+		Publishes         int64  `json:"publishes"`
+		Delivered         int64  `json:"delivered"`
+		BytesIn           int64  `json:"bytes_in"`
+		BytesOut          int64  `json:"bytes_out"`
+		ConnectionSeconds int64  `json:"connection_seconds"`
+	}
+
+	UsageResponse struct {
+		Usage []UsageRow `json:"usage"`
+	}
+
+	ImportTopicsResponse struct {
+		Imported int `json:"imported"`
+		Skipped  int `json:"skipped"`
+	}
+
+	ArchivedEvent struct {
+		ID          string          `json:"id"`
+		Type        string          `json:"type,omitempty"`
+		Data        json.RawMessage `json:"data"`
+		PublishedAt string          `json:"published_at"`
+	}
+
+	QueryArchiveResponse struct {
+		Events []ArchivedEvent `json:"events"`
+		Count  int             `json:"count"`
+	}
+
 	GetMetricsResponse struct {
 		Metrics []Metric `json:"metrics"`
+		// Since marks a delta query: counter values are changes measured
+		// from this snapshot time.
+		Since string `json:"since,omitempty"`
+	}
+
+	StaticPubSub struct {
+		ID    int64  `json:"id"`
+		Name  string `json:"name"`
+		Token string `json:"token"`
+	}
+
+	TopicRates struct {
+		ID         string `json:"id"`
+		LastSecond int64  `json:"last_second"`
+		Last10Secs int64  `json:"last_10_seconds"`
+		LastMinute int64  `json:"last_minute"`
 	}
 
 	Metric struct {
@@ -0,0 +1,83 @@
+package pubsub
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultPublishWaitTimeout bounds a synchronous publish (?wait=delivered)
+// when the caller didn't pass an explicit timeout.
+const defaultPublishWaitTimeout = 2 * time.Second
+
+// ackTracker collects per-delivery outcomes for a synchronous publish. The
+// fan-out workers report each delivery as it completes; the publisher arms
+// the tracker with the enqueued count once fan-out has finished queueing and
+// then waits for all outcomes or its timeout, whichever comes first. A nil
+// tracker (the asynchronous default) is a no-op for the workers.
+type ackTracker struct {
+	mutex     sync.Mutex
+	expected  int
+	armed     bool
+	delivered int
+	failed    int
+	done      chan struct{}
+}
+
+func newAckTracker() *ackTracker {
+	return &ackTracker{done: make(chan struct{})}
+}
+
+// observe records one delivery outcome.
+func (a *ackTracker) observe(delivered bool) {
+	if a == nil {
+		return
+	}
+	a.mutex.Lock()
+	if delivered {
+		a.delivered++
+	} else {
+		a.failed++
+	}
+	a.maybeFinish()
+	a.mutex.Unlock()
+}
+
+// arm tells the tracker how many outcomes to expect; deliveries may already
+// have reported by the time it is called.
+func (a *ackTracker) arm(expected int) {
+	a.mutex.Lock()
+	a.expected = expected
+	a.armed = true
+	a.maybeFinish()
+	a.mutex.Unlock()
+}
+
+// maybeFinish is called with the mutex held.
+func (a *ackTracker) maybeFinish() {
+	if !a.armed || a.delivered+a.failed < a.expected {
+		return
+	}
+	select {
+	case <-a.done:
+	default:
+		close(a.done)
+	}
+}
+
+// wait blocks until every delivery reported or the timeout elapsed and
+// returns the outcome counts; pending is whatever was still unconfirmed.
+func (a *ackTracker) wait(timeout time.Duration) (delivered, failed, pending int) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-a.done:
+	case <-timer.C:
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	pending = a.expected - a.delivered - a.failed
+	if pending < 0 {
+		pending = 0
+	}
+	return a.delivered, a.failed, pending
+}
@@ -0,0 +1,76 @@
+package pubsub
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	alertKindSlowDelivery    = "slow_delivery"
+	alertKindDeliveryTimeout = "delivery_timeout"
+
+	eventTypeAlert = "alert"
+)
+
+// alertLimiter rate-limits alert events per pubsub so a single slow consumer
+// can't flood the system topic
+type alertLimiter struct {
+	cooldown time.Duration
+	mutex    sync.Mutex
+	lastAt   map[int64]time.Time
+}
+
+func newAlertLimiter(cooldown time.Duration) *alertLimiter {
+	return &alertLimiter{
+		cooldown: cooldown,
+		lastAt:   make(map[int64]time.Time),
+	}
+}
+
+// allow reports whether an alert for the pubsub may fire now and records the
+// attempt when it may
+func (l *alertLimiter) allow(pubsubID int64) bool {
+	if l.cooldown <= 0 {
+		return true
+	}
+	now := time.Now().UTC()
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if last, ok := l.lastAt[pubsubID]; ok && now.Sub(last) < l.cooldown {
+		return false
+	}
+	l.lastAt[pubsubID] = now
+	return true
+}
+
+// alertSlowSubscriber publishes a structured alert on the system topic and
+// bumps the matching alert metric when a subscriber delivery breaches the
+// configured thresholds. The reserved metrics pubsub never alerts on itself.
+func (c *controller) alertSlowSubscriber(kind string, pubsubID, subscriberID int64, elapsed time.Duration) {
+	mcfg := c.config().MetricsTopic
+	if pubsubID == mcfg.ID {
+		return
+	}
+
+	switch kind {
+	case alertKindSlowDelivery:
+		c.metrics.inc(metricAlertSlowDelivery)
+	case alertKindDeliveryTimeout:
+		c.metrics.inc(metricAlertDeliveryTimeout)
+		c.notifyHook(HookEvent{
+			Kind:         HookKindDeliveryFailure,
+			PubSubID:     pubsubID,
+			SubscriberID: subscriberID,
+			Reason:       kind,
+		})
+	}
+
+	if mcfg.Disabled || !c.alerts.allow(pubsubID) {
+		return
+	}
+
+	msg := fmt.Sprintf(`{"alert": "%s", "pubsub_id": %d, "subscriber_id": %d, "elapsed_ms": %d}`,
+		kind, pubsubID, subscriberID, elapsed.Milliseconds())
+	_, _ = c.publish(mcfg.ID, "", eventTypeAlert, nil, []byte(msg), nil)
+}
@@ -0,0 +1,102 @@
+package pubsub
+
+import (
+	"context"
+	"time"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	"github.com/hasmcp/sser/internal/recorder/kv"
+	zlog "github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultArchiveQueryLimit applies when the admin query doesn't set one
+	defaultArchiveQueryLimit = 100
+	// maxArchiveQueryLimit caps a single archive page
+	maxArchiveQueryLimit = 1000
+)
+
+// archiveEvent writes a published event into the archive when the topic is
+// persistent and the configured storage driver supports archival; it runs in
+// the background so the publish path never waits on the database
+func (c *controller) archiveEvent(pubsubID int64, eventID, eventType string, data []byte) {
+	archiver, ok := c.kv.(kv.Archiver)
+	if !ok {
+		return
+	}
+	go func() {
+		err := archiver.ArchiveEvent(context.Background(), kv.ArchivedEvent{
+			PubSubID:    pubsubID,
+			EventID:     eventID,
+			EventType:   eventType,
+			Data:        data,
+			PublishedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			zlog.Error().Err(err).Int64("id", pubsubID).Str("eventID", eventID).
+				Msg(logPrefix + "failed to archive published event")
+		}
+	}()
+}
+
+// QueryArchive returns archived events of a persistent topic within a time
+// range; it's an admin API guarded by the instance access token.
+func (c *controller) QueryArchive(ctx context.Context, req entity.QueryArchiveRequest) (*entity.QueryArchiveResponse, error) {
+	if req.ApiAccessToken != c.config().ApiAccessToken {
+		return nil, entity.Err{
+			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
+			Message: "API access token mismatch",
+			Details: map[string]any{
+				"token": req.ApiAccessToken,
+			},
+		}
+	}
+
+	archiver, ok := c.kv.(kv.Archiver)
+	if !ok {
+		return nil, entity.Err{
+			Code:    entity.ErrorCodeNotImplemented,
+			Message: "the configured storage driver doesn't support event archival",
+		}
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultArchiveQueryLimit
+	}
+	if limit > maxArchiveQueryLimit {
+		limit = maxArchiveQueryLimit
+	}
+
+	from := req.From
+	to := req.To
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+
+	archived, err := archiver.QueryArchive(ctx, req.PubSubID, from, to, limit)
+	if err != nil {
+		return nil, entity.Err{
+			Code:    500,
+			Message: "Couldn't query the event archive",
+			Details: map[string]any{
+				"err": err.Error(),
+			},
+		}
+	}
+
+	events := make([]entity.ArchivedEvent, 0, len(archived))
+	for _, ev := range archived {
+		events = append(events, entity.ArchivedEvent{
+			ID:          ev.EventID,
+			Type:        ev.EventType,
+			Data:        ev.Data,
+			PublishedAt: ev.PublishedAt,
+		})
+	}
+
+	return &entity.QueryArchiveResponse{
+		Events: events,
+	}, nil
+}
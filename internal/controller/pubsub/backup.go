@@ -0,0 +1,61 @@
+package pubsub
+
+import (
+	"bytes"
+	"context"
+
+	zlog "github.com/rs/zerolog/log"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	"github.com/hasmcp/sser/internal/recorder/kv"
+)
+
+// BackupStore streams a consistent snapshot of the KV store into memory and
+// hands it back; it's an admin API guarded by the instance access token since
+// the snapshot contains every tenant's records.
+func (c *controller) BackupStore(ctx context.Context, req entity.BackupStoreRequest) (*entity.BackupStoreResponse, error) {
+	if req.ApiAccessToken != c.config().ApiAccessToken {
+		return nil, entity.Err{
+			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
+			Message: "API access token mismatch",
+			Details: map[string]any{
+				"token": req.ApiAccessToken,
+			},
+		}
+	}
+
+	if c.kv == nil {
+		return nil, entity.Err{
+			Code:    400,
+			Kind:    entity.ErrorKindStorageUnavailable,
+			Message: "Persistent store is not available",
+		}
+	}
+
+	backuper, ok := c.kv.(kv.Backuper)
+	if !ok {
+		return nil, entity.Err{
+			Code:    entity.ErrorCodeNotImplemented,
+			Message: "the configured storage driver doesn't support online backup",
+		}
+	}
+
+	var buf bytes.Buffer
+	n, err := backuper.Backup(ctx, &buf)
+	if err != nil {
+		return nil, entity.Err{
+			Code:    500,
+			Kind:    entity.ErrorKindStorageFailure,
+			Message: "Couldn't snapshot the store",
+			Details: map[string]any{
+				"err": err.Error(),
+			},
+		}
+	}
+
+	zlog.Info().Int64("bytes", n).Msg(logPrefix + "streamed store snapshot")
+	return &entity.BackupStoreResponse{
+		Data: buf.Bytes(),
+	}, nil
+}
@@ -6,10 +6,15 @@ import (
 	"crypto/rand"
 	"fmt"
 	"math/big"
+	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hasmcp/sser/internal/_data/entity"
+	"github.com/hasmcp/sser/internal/histogram"
 	"github.com/hasmcp/sser/internal/recorder/kv"
 	"github.com/hasmcp/sser/internal/servicer/config"
 	"github.com/hasmcp/sser/internal/servicer/idgen"
@@ -23,35 +28,112 @@ type (
 		Delete(ctx context.Context, req entity.DeletePubSubRequest) error
 		Publish(ctx context.Context, req entity.PublishRequest) (*entity.PublishResponse, error)
 		Subscribe(ctx context.Context, req entity.SubscribeRequest) (*entity.SubscribeResponse, error)
+		Poll(ctx context.Context, req entity.PollRequest) (*entity.PollResponse, error)
+		GetSubscribers(ctx context.Context, req entity.GetSubscribersRequest) (*entity.GetSubscribersResponse, error)
 		Unsubscribe(ctx context.Context, req entity.UnsubscribeRequest) error
 		GetMetrics(ctx context.Context, req entity.GetMetricsRequest) (*entity.GetMetricsResponse, error)
+		GetTopicRates(ctx context.Context, req entity.GetTopicRatesRequest) (*entity.GetTopicRatesResponse, error)
+		UpsertStaticPubSub(ctx context.Context, req entity.UpsertStaticPubSubRequest) error
+		DeleteStaticPubSub(ctx context.Context, req entity.DeleteStaticPubSubRequest) error
+		QueryArchive(ctx context.Context, req entity.QueryArchiveRequest) (*entity.QueryArchiveResponse, error)
+		ResolveName(ctx context.Context, name string) (int64, error)
+		Update(ctx context.Context, req entity.UpdatePubSubRequest) error
+		Pause(ctx context.Context, req entity.PausePubSubRequest) error
+		Resume(ctx context.Context, req entity.ResumePubSubRequest) error
+		ListPubSubs(ctx context.Context, req entity.ListPubSubsRequest) (*entity.ListPubSubsResponse, error)
+		SetSchema(ctx context.Context, req entity.SetSchemaRequest) error
+		GetSchema(ctx context.Context, req entity.GetSchemaRequest) (*entity.GetSchemaResponse, error)
+		DeleteSchema(ctx context.Context, req entity.DeleteSchemaRequest) error
+		SetScript(ctx context.Context, req entity.SetScriptRequest) error
+		GetScript(ctx context.Context, req entity.GetScriptRequest) (*entity.GetScriptResponse, error)
+		DeleteScript(ctx context.Context, req entity.DeleteScriptRequest) error
+		ExportTopics(ctx context.Context, req entity.ExportTopicsRequest) (*entity.ExportTopicsResponse, error)
+		ImportTopics(ctx context.Context, req entity.ImportTopicsRequest) (*entity.ImportTopicsResponse, error)
+		BackupStore(ctx context.Context, req entity.BackupStoreRequest) (*entity.BackupStoreResponse, error)
+		GetUsage(ctx context.Context, req entity.GetUsageRequest) (*entity.GetUsageResponse, error)
+		Ready(ctx context.Context) error
 	}
 
 	controller struct {
-		cfg     pubsubConfig
-		idgen   idgen.Servicer
-		kv      kv.Recorder
-		pubsubs sync.Map
-		metrics *metrics
+		cfg           pubsubConfig
+		cfgMutex      sync.RWMutex
+		idgen         idgen.Servicer
+		kv            kv.Recorder
+		pubsubs       sync.Map
+		names         sync.Map
+		metrics       *metrics
+		tenantMetrics sync.Map
+		alerts        *alertLimiter
+		idem          *idempotencyCache
+		dedup         *idempotencyCache
+		fanout        *fanoutPool
+		fanoutLatency *histogram.Histogram
+		history       *metricsHistory
+		routes        map[int64][]topicRoute
+		hooks         []Hook
+		hookCh        chan HookEvent
+		// usage maps tenant -> *usageCounters for the open billing window;
+		// usageWindowStart is its unix start time, swapped on each rollup
+		usage            sync.Map
+		usageWindowStart int64
 	}
 
 	Params struct {
 		Config config.Servicer
 		IDGen  idgen.Servicer
 		KV     kv.Recorder
+		// Hooks receive lifecycle notifications; embedders link their own
+		// integrations here, next to the config-registered built-ins
+		Hooks []Hook
 	}
 
 	pubsub struct {
-		id          int64
-		static      bool
-		subscribers []subscriber
-		mutex       sync.RWMutex
-		token       []byte
+		id             int64
+		static         bool
+		persist        bool
+		presence       bool
+		profile        string
+		tenant         string
+		name           string
+		subscribers    *subscriberList
+		mutex          sync.RWMutex
+		rates          *rateWindow
+		retained       *retentionBuffer
+		token          []byte
+		tokenTTL       time.Duration
+		tokenExpiresAt time.Time
+		expiresAt      time.Time
+		idleTTL        time.Duration
+		tickFrequency  time.Duration
+		retryHint      time.Duration
+		maxSubscribers int
+		paused         int32
+		lastActivity   int64
+		schema         *eventSchema
+		script         *publishScript
+		keyed          *lastValueCache
 	}
 
 	subscriber struct {
-		channel chan *entity.Event
-		id      int64
+		channel     chan *entity.Event
+		id          int64
+		filter      *subscriberFilter
+		transform   *transform
+		connectedAt time.Time
+		// lastFlush is the unix-nano time of the last successful write to
+		// the client's socket; a pointer so every copy-on-write snapshot of
+		// the subscriber list shares it
+		lastFlush *int64
+	}
+
+	// deliveryStats summarizes the synchronous half of a publish: how many
+	// subscribers were connected when the event entered the fan-out, how
+	// many deliveries were queued to them and how many subscribers were
+	// skipped by their event filters
+	deliveryStats struct {
+		subscribers int
+		enqueued    int
+		dropped     int
 	}
 
 	pubsubConfig struct {
@@ -59,13 +141,64 @@ type (
 		MetricsAccessToken                string               `yaml:"metricsAccessToken"`
 		MaxDurationForSubscriberToReceive time.Duration        `yaml:"maxDurationForSubscriberToReceive"`
 		TickFrequency                     time.Duration        `yaml:"tickFrequency"`
+		MaxStreamLifetime                 time.Duration        `yaml:"maxStreamLifetime"`
+		ReconnectRetryHint                time.Duration        `yaml:"reconnectRetryHint"`
+		TokenJanitorFrequency             time.Duration        `yaml:"tokenJanitorFrequency"`
+		TopicReaperFrequency              time.Duration        `yaml:"topicReaperFrequency"`
+		UsageRollupFrequency              time.Duration        `yaml:"usageRollupFrequency"`
+		AutoRotateExpiredTokens           bool                 `yaml:"autoRotateExpiredTokens"`
+		KeepaliveStyle                    string               `yaml:"keepaliveStyle"`
+		SlowDeliveryThreshold             time.Duration        `yaml:"slowDeliveryThreshold"`
+		StaleSubscriberThreshold          time.Duration        `yaml:"staleSubscriberThreshold"`
+		AlertCooldown                     time.Duration        `yaml:"alertCooldown"`
+		RetentionBufferSize               int                  `yaml:"retentionBufferSize"`
+		LongPollTimeout                   time.Duration        `yaml:"longPollTimeout"`
+		IdempotencyKeyTTL                 time.Duration        `yaml:"idempotencyKeyTTL"`
+		EventIDDedupTTL                   time.Duration        `yaml:"eventIdDedupTTL"`
+		FanoutWorkers                     int                  `yaml:"fanoutWorkers"`
+		MaxTopics                         int64                `yaml:"maxTopics"`
+		MaxSubscribersPerTopic            int                  `yaml:"maxSubscribersPerTopic"`
+		MaxMessageSize                    int                  `yaml:"maxMessageSize"`
+		MetricsTopic                      metricsTopicConfig   `yaml:"metricsTopic"`
 		StaticPubSubs                     []StaticPubSubConfig `yaml:"staticPubSubs"`
+		Tenants                           []TenantConfig       `yaml:"tenants"`
+		Routes                            []RouteConfig        `yaml:"routes"`
+		Hooks                             hooksConfig          `yaml:"hooks"`
 	}
 
 	StaticPubSubConfig struct {
 		ID    int64  `yaml:"id"`
 		Name  string `yaml:"name"`
 		Token string `yaml:"token"`
+		// TokenEnv names an environment variable holding the token so
+		// secrets can stay out of config files; it is only consulted when
+		// token is empty
+		TokenEnv string `yaml:"tokenEnv"`
+		// the remaining fields mirror the per-topic overrides dynamic topics
+		// get at create time; zero values fall back to the instance-wide
+		// settings
+		TickFrequency       time.Duration `yaml:"tickFrequency"`
+		RetentionBufferSize int           `yaml:"retentionBufferSize"`
+		MaxSubscribers      int           `yaml:"maxSubscribers"`
+	}
+
+	// RouteConfig mirrors events published to one topic into another when
+	// they match the optional types/attrs filter, so aggregation topics (e.g.
+	// an audit topic collecting several tenant topics) don't need an external
+	// consumer.
+	RouteConfig struct {
+		From  int64             `yaml:"from"`
+		To    int64             `yaml:"to"`
+		Types []string          `yaml:"types"`
+		Attrs map[string]string `yaml:"attrs"`
+	}
+
+	// TenantConfig scopes an API key to a named tenant with its own quotas,
+	// metrics, and KV bucket.
+	TenantConfig struct {
+		Name           string `yaml:"name"`
+		ApiAccessToken string `yaml:"apiAccessToken"`
+		MaxTopics      int64  `yaml:"maxTopics"`
 	}
 )
 
@@ -83,11 +216,24 @@ func New(p Params) (Controller, error) {
 	}
 
 	c := &controller{
-		cfg:     cfg,
-		idgen:   p.IDGen,
-		kv:      p.KV,
-		pubsubs: sync.Map{},
-		metrics: newMetrics(),
+		cfg:           cfg,
+		idgen:         p.IDGen,
+		kv:            p.KV,
+		pubsubs:       sync.Map{},
+		metrics:       newMetrics(),
+		alerts:        newAlertLimiter(cfg.AlertCooldown),
+		idem:          newIdempotencyCache(idempotencyTTL(cfg.IdempotencyKeyTTL)),
+		dedup:         newIdempotencyCache(idempotencyTTL(cfg.EventIDDedupTTL)),
+		routes:        buildRoutes(cfg.Routes),
+		fanoutLatency: histogram.New("latency.fanout"),
+		history:       newMetricsHistory(metricsHistorySize),
+	}
+	c.fanout = c.newFanoutPool(cfg.FanoutWorkers)
+
+	c.hooks = buildHooks(cfg.Hooks, p.Hooks)
+	if len(c.hooks) > 0 {
+		c.hookCh = make(chan HookEvent, hookQueueSize)
+		go c.runHookDispatcher()
 	}
 
 	err = c.registerStaticPubSubs()
@@ -95,18 +241,170 @@ func New(p Params) (Controller, error) {
 		return nil, err
 	}
 
+	err = c.registerPersistedStaticPubSubs()
+	if err != nil {
+		return nil, err
+	}
+
 	err = c.registerPersistentPubSubs()
 	if err != nil {
 		return nil, err
 	}
+	c.loadSchemas()
+	c.loadScripts()
+
+	if cfg.AutoRotateExpiredTokens && cfg.TokenJanitorFrequency > 0 {
+		go c.runTokenJanitor()
+	}
+
+	go c.runTopicReaper()
+	go c.runMetricsPublisher()
+
+	atomic.StoreInt64(&c.usageWindowStart, time.Now().UTC().Unix())
+	go c.runUsageSampler()
+	if c.kv != nil {
+		go c.runUsageRollup()
+	}
+
+	p.Config.OnReload(func() { c.reloadConfig(p.Config) })
 
 	return c, nil
 }
 
+// config returns a snapshot of the current configuration so that reloads don't
+// race with in-flight requests
+func (c *controller) config() pubsubConfig {
+	c.cfgMutex.RLock()
+	defer c.cfgMutex.RUnlock()
+	return c.cfg
+}
+
+// reloadConfig re-populates the pubsub section and applies changes (tick
+// frequency, timeouts, tokens, static pubsubs) to the running controller
+func (c *controller) reloadConfig(cfgsrv config.Servicer) {
+	var cfg pubsubConfig
+	if err := cfgsrv.Populate(cfgKey, &cfg); err != nil {
+		zlog.Error().Err(err).Msg(logPrefix + "failed to re-populate config on reload; keeping the previous one")
+		return
+	}
+
+	c.cfgMutex.Lock()
+	c.cfg = cfg
+	c.routes = buildRoutes(cfg.Routes)
+	c.cfgMutex.Unlock()
+
+	// upsert static pubsubs; existing ones keep their subscribers and only
+	// refresh their tokens and names
+	newCnt := int64(0)
+	for _, ps := range cfg.StaticPubSubs {
+		token := ps.resolveToken()
+		if ps.ID == 0 || len(token) < 1 {
+			zlog.Error().Int64("id", ps.ID).Str("name", ps.Name).Msg(logPrefix + "invalid static pubsub in reloaded config; skipping")
+			continue
+		}
+		if t, ok := c.pubsubs.Load(ps.ID); ok {
+			if existing, ok := t.(*pubsub); ok && existing.static {
+				existing.mutex.Lock()
+				oldName := existing.name
+				existing.token = token
+				existing.name = ps.Name
+				existing.tickFrequency = ps.TickFrequency
+				existing.maxSubscribers = ps.MaxSubscribers
+				if ps.RetentionBufferSize > 0 && ps.RetentionBufferSize != existing.retained.capacity() {
+					existing.retained = newRetentionBuffer(ps.RetentionBufferSize)
+				}
+				existing.mutex.Unlock()
+				if oldName != ps.Name {
+					c.unregisterName(oldName)
+					if !c.registerName(ps.Name, ps.ID) {
+						zlog.Warn().Int64("id", ps.ID).Str("name", ps.Name).Msg(logPrefix + "static topic name already claimed; keeping it reachable by id only")
+					}
+				}
+				continue
+			}
+		}
+		retentionSize := cfg.RetentionBufferSize
+		if ps.RetentionBufferSize > 0 {
+			retentionSize = ps.RetentionBufferSize
+		}
+		c.pubsubs.Store(ps.ID, &pubsub{
+			id:             ps.ID,
+			static:         true,
+			name:           ps.Name,
+			subscribers:    newSubscriberList(),
+			mutex:          sync.RWMutex{},
+			rates:          newRateWindow(),
+			retained:       newRetentionBuffer(retentionSize),
+			token:          token,
+			tickFrequency:  ps.TickFrequency,
+			maxSubscribers: ps.MaxSubscribers,
+		})
+		if !c.registerName(ps.Name, ps.ID) {
+			zlog.Warn().Int64("id", ps.ID).Str("name", ps.Name).Msg(logPrefix + "static topic name already claimed; keeping it reachable by id only")
+		}
+		newCnt++
+	}
+	if newCnt > 0 {
+		c.incBy(metricTopics, newCnt)
+		c.incBy(metricActiveTopics, newCnt)
+		c.incBy(metricStaticTopics, newCnt)
+	}
+
+	zlog.Info().Msg(logPrefix + "configuration reloaded")
+}
+
+// tenantForAPIToken resolves the tenant owning the presented API key; the
+// instance-wide key maps to the default (unnamed) tenant
+func (c *controller) tenantForAPIToken(token string) (string, bool) {
+	cfg := c.config()
+	if token == cfg.ApiAccessToken {
+		return "", true
+	}
+	for _, t := range cfg.Tenants {
+		if t.ApiAccessToken != "" && token == t.ApiAccessToken {
+			return t.Name, true
+		}
+	}
+	return "", false
+}
+
+// tenantConfig returns the config entry for a named tenant
+func (c *controller) tenantConfig(tenant string) (TenantConfig, bool) {
+	for _, t := range c.config().Tenants {
+		if t.Name == tenant {
+			return t, true
+		}
+	}
+	return TenantConfig{}, false
+}
+
+// tenantTopicCount counts the live topics owned by a tenant
+func (c *controller) tenantTopicCount(tenant string) int64 {
+	cnt := int64(0)
+	c.pubsubs.Range(func(_, val any) bool {
+		if ps, ok := val.(*pubsub); ok && !ps.static && ps.tenant == tenant {
+			cnt++
+		}
+		return true
+	})
+	return cnt
+}
+
+// tenantInc bumps a counter on the tenant-scoped metrics registry
+func (c *controller) tenantInc(tenant string, k metric, val int64) {
+	if tenant == "" {
+		return
+	}
+	m, _ := c.tenantMetrics.LoadOrStore(tenant, newMetrics())
+	m.(*metrics).incBy(k, val)
+}
+
 func (c *controller) Create(ctx context.Context, req entity.CreatePubSubRequest) (*entity.CreatePubSubResponse, error) {
-	if req.ApiAccessToken != c.cfg.ApiAccessToken {
+	tenant, ok := c.tenantForAPIToken(req.ApiAccessToken)
+	if !ok {
 		return nil, entity.Err{
 			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
 			Message: "API access token mismatch",
 			Details: map[string]any{
 				"token": req.ApiAccessToken,
@@ -114,34 +412,98 @@ func (c *controller) Create(ctx context.Context, req entity.CreatePubSubRequest)
 		}
 	}
 
+	if tcfg, ok := c.tenantConfig(tenant); ok && tcfg.MaxTopics > 0 && c.tenantTopicCount(tenant) >= tcfg.MaxTopics {
+		c.inc(metricQuotaTopicsExceeded)
+		c.tenantInc(tenant, metricQuotaTopicsExceeded, 1)
+		return nil, entity.Err{
+			Code:    entity.ErrorCodeTooManyRequests,
+			Kind:    entity.ErrorKindQuotaExceeded,
+			Message: "max topics quota exceeded for the tenant",
+			Details: map[string]any{
+				"tenant":    tenant,
+				"maxTopics": tcfg.MaxTopics,
+			},
+		}
+	}
+
+	if maxTopics := c.config().MaxTopics; maxTopics > 0 && c.get(metricActiveTopics) >= maxTopics {
+		c.inc(metricQuotaTopicsExceeded)
+		return nil, entity.Err{
+			Code:    entity.ErrorCodeTooManyRequests,
+			Kind:    entity.ErrorKindQuotaExceeded,
+			Message: "max topics quota exceeded",
+			Details: map[string]any{
+				"maxTopics": maxTopics,
+			},
+		}
+	}
+
 	defer c.inc(metricTopics)
 	defer c.inc(metricActiveTopics)
 
+	if req.Name != "" {
+		if err := validateTopicName(req.Name); err != nil {
+			return nil, err
+		}
+	}
+
 	id := c.idgen.Next()
 
-	token, err := generateRandom64()
-	if err != nil {
+	if !c.registerName(req.Name, id) {
 		return nil, entity.Err{
-			Code:    500,
-			Message: "Couldn't generate random token",
+			Code:    entity.ErrorCodeConflict,
+			Kind:    entity.ErrorKindNameTaken,
+			Message: "topic name is already in use",
 			Details: map[string]any{
-				"err": err.Error(),
+				"name": req.Name,
 			},
 		}
 	}
 
+	token := req.Token
+	if token == "" {
+		var err error
+		token, err = generateRandom64()
+		if err != nil {
+			c.unregisterName(req.Name)
+			return nil, entity.Err{
+				Code:    500,
+				Message: "Couldn't generate random token",
+				Details: map[string]any{
+					"err": err.Error(),
+				},
+			}
+		}
+	}
+
+	var tokenExpiresAt time.Time
+	if req.TokenTTL > 0 {
+		tokenExpiresAt = time.Now().UTC().Add(req.TokenTTL)
+	}
+
 	if req.Persist {
 		if c.kv == nil {
+			c.unregisterName(req.Name)
 			return nil, entity.Err{
 				Code:    400,
+				Kind:    entity.ErrorKindStorageUnavailable,
 				Message: "Persistent store is not available",
 			}
 		}
 
-		err := c.kv.Set(ctx, monoflake.ID(id).BigEndianBytes(), []byte(token))
+		err := c.kv.SetIn(ctx, tenant, monoflake.ID(id).BigEndianBytes(), encodeTokenRecord(tokenRecord{
+			token:          []byte(token),
+			tokenExpiresAt: tokenExpiresAt,
+			name:           req.Name,
+			tickFrequency:  req.TickFrequency,
+			retryHint:      req.RetryHint,
+			retentionSize:  req.RetentionBufferSize,
+		}))
 		if err != nil {
+			c.unregisterName(req.Name)
 			return nil, entity.Err{
 				Code:    500,
+				Kind:    entity.ErrorKindStorageFailure,
 				Message: "Couldn't persist to store",
 				Details: map[string]any{
 					"err": err.Error(),
@@ -150,23 +512,60 @@ func (c *controller) Create(ctx context.Context, req entity.CreatePubSubRequest)
 		}
 	}
 
+	c.tenantInc(tenant, metricTopics, 1)
+	c.tenantInc(tenant, metricActiveTopics, 1)
+
+	var expiresAt time.Time
+	if req.TTL > 0 {
+		expiresAt = time.Now().UTC().Add(req.TTL)
+	}
+
+	retentionSize := c.config().RetentionBufferSize
+	if req.RetentionBufferSize > 0 {
+		retentionSize = req.RetentionBufferSize
+	}
+
 	c.pubsubs.Store(id, &pubsub{
-		id:          id,
-		subscribers: make([]subscriber, 0, 1),
-		mutex:       sync.RWMutex{},
-		token:       []byte(token),
+		id:             id,
+		persist:        req.Persist,
+		presence:       req.Presence,
+		profile:        req.Profile,
+		tenant:         tenant,
+		name:           req.Name,
+		subscribers:    newSubscriberList(),
+		mutex:          sync.RWMutex{},
+		rates:          newRateWindow(),
+		retained:       newRetentionBuffer(retentionSize),
+		token:          []byte(token),
+		tokenTTL:       req.TokenTTL,
+		tokenExpiresAt: tokenExpiresAt,
+		expiresAt:      expiresAt,
+		idleTTL:        req.IdleTTL,
+		tickFrequency:  req.TickFrequency,
+		retryHint:      req.RetryHint,
+		lastActivity:   time.Now().UnixNano(),
+	})
+
+	c.notifyHook(HookEvent{
+		Kind:     HookKindTopicCreated,
+		PubSubID: id,
+		Tenant:   tenant,
+		Topic:    req.Name,
 	})
 
 	return &entity.CreatePubSubResponse{
-		ID:    id,
-		Token: []byte(token),
+		ID:             id,
+		Token:          []byte(token),
+		TokenExpiresAt: tokenExpiresAt,
 	}, nil
 }
 
 func (c *controller) Delete(ctx context.Context, req entity.DeletePubSubRequest) error {
-	if req.ApiAccessToken != c.cfg.ApiAccessToken {
+	tenant, ok := c.tenantForAPIToken(req.ApiAccessToken)
+	if !ok {
 		return entity.Err{
 			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
 			Message: "API access token mismatch",
 			Details: map[string]any{
 				"token": req.ApiAccessToken,
@@ -199,11 +598,31 @@ func (c *controller) Delete(ctx context.Context, req entity.DeletePubSubRequest)
 		}
 	}
 
+	if pubsub.tenant != tenant {
+		return entity.Err{
+			Code:    entity.ErrorCodeForbidden,
+			Message: "pubsub belongs to another tenant",
+			Details: map[string]any{
+				"id": req.ID,
+			},
+		}
+	}
+
 	if c.kv != nil {
-		err := c.kv.Delete(context.Background(), monoflake.ID(req.ID).BigEndianBytes())
+		// the attached schema and script go with the topic; a failure here
+		// only leaves an orphaned record behind
+		if err := c.kv.DeleteIn(context.Background(), schemaBucket, monoflake.ID(req.ID).BigEndianBytes()); err != nil {
+			zlog.Error().Err(err).Int64("id", req.ID).Msg(logPrefix + "failed to delete the topic's schema from storage")
+		}
+		if err := c.kv.DeleteIn(context.Background(), scriptBucket, monoflake.ID(req.ID).BigEndianBytes()); err != nil {
+			zlog.Error().Err(err).Int64("id", req.ID).Msg(logPrefix + "failed to delete the topic's script from storage")
+		}
+
+		err := c.kv.DeleteIn(context.Background(), pubsub.tenant, monoflake.ID(req.ID).BigEndianBytes())
 		if err != nil {
 			return entity.Err{
 				Code:    500,
+				Kind:    entity.ErrorKindStorageFailure,
 				Message: "Couldn't delete the pubsub from storage",
 				Details: map[string]any{
 					"id": req.ID,
@@ -213,20 +632,44 @@ func (c *controller) Delete(ctx context.Context, req entity.DeletePubSubRequest)
 	}
 
 	defer c.dec(metricActiveTopics)
+	c.tenantInc(tenant, metricActiveTopics, -1)
 
-	pubsub.mutex.Lock()
-	for _, s := range pubsub.subscribers {
-		close(s.channel)
-	}
+	// the deletion announcement carries the optional redirect hint, then the
+	// regular close event terminates the stream
+	c.drainSubscribers(pubsub, entity.DeletedEvent(req.RedirectTo), entity.CloseEvent(entity.CloseReasonDeleted))
 	c.pubsubs.Delete(req.ID)
-	pubsub.mutex.Unlock()
+	c.unregisterName(pubsub.name)
 	return nil
 }
 
+// closeSubscribers delivers the terminal close event with the given reason to
+// every subscriber and closes their channels.
+func (c *controller) closeSubscribers(ps *pubsub, reason entity.CloseReason) {
+	c.drainSubscribers(ps, entity.CloseEvent(reason))
+}
+
+// drainSubscribers delivers the given terminal events in order to every
+// subscriber and closes their channels; draining the list up front guarantees
+// each channel is closed exactly once.
+func (c *controller) drainSubscribers(ps *pubsub, events ...*entity.Event) {
+	for _, s := range ps.subscribers.drain() {
+		for _, event := range events {
+			err := publishWithTimeout(s.channel, event, c.config().MaxDurationForSubscriberToReceive)
+			if err != nil {
+				zlog.Warn().Err(err).Int64("id", ps.id).Str("type", event.Type).
+					Msg(logPrefix + "failed to deliver terminal event before closing the stream")
+			}
+		}
+		close(s.channel)
+	}
+}
+
 func (c *controller) Publish(ctx context.Context, req entity.PublishRequest) (*entity.PublishResponse, error) {
-	if req.ApiAccessToken != c.cfg.ApiAccessToken {
+	tenant, ok := c.tenantForAPIToken(req.ApiAccessToken)
+	if !ok {
 		return nil, entity.Err{
 			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
 			Message: "API access token mismatch",
 			Details: map[string]any{
 				"token": req.ApiAccessToken,
@@ -234,16 +677,177 @@ func (c *controller) Publish(ctx context.Context, req entity.PublishRequest) (*e
 		}
 	}
 
-	cnt, err := c.publish(req.PubSubID, req.EventID, req.EventType, req.Message)
+	if t, ok := c.pubsubs.Load(req.PubSubID); ok {
+		if ps, ok := t.(*pubsub); ok {
+			if !ps.static && ps.tenant != tenant {
+				return nil, entity.Err{
+					Code:    entity.ErrorCodeForbidden,
+					Message: "pubsub belongs to another tenant",
+					Details: map[string]any{
+						"id": req.PubSubID,
+					},
+				}
+			}
+			if ps.isPaused() {
+				return nil, entity.Err{
+					Code:    entity.ErrorCodeConflict,
+					Kind:    entity.ErrorKindTopicPaused,
+					Message: "pubsub is paused",
+					Details: map[string]any{
+						"id":   req.PubSubID,
+						"hint": "resume the pubsub to accept publishes again",
+					},
+				}
+			}
+			// malformed events are cheapest to stop here, before they reach
+			// every subscriber of the topic
+			if schema := ps.eventSchema(); schema != nil {
+				if violations := schema.validate(req.Message); len(violations) > 0 {
+					return nil, entity.Err{
+						Code:    entity.ErrorCodeUnprocessableEntity,
+						Kind:    entity.ErrorKindSchemaViolation,
+						Message: "event payload violates the topic schema",
+						Details: map[string]any{
+							"id":         req.PubSubID,
+							"violations": violations,
+						},
+					}
+				}
+			}
+			// the topic script runs after validation so it only ever sees
+			// conforming payloads; a filtered event is acknowledged without
+			// an id instead of erroring back to the publisher
+			if script := ps.publishScript(); script != nil {
+				out, filtered, err := script.run(req.Message)
+				if err != nil {
+					return nil, entity.Err{
+						Code:    entity.ErrorCodeUnprocessableEntity,
+						Kind:    entity.ErrorKindValidationFailed,
+						Message: "topic script failed on the event payload",
+						Details: map[string]any{
+							"id":  req.PubSubID,
+							"err": err.Error(),
+						},
+					}
+				}
+				if filtered {
+					c.inc(metricScriptFilteredPublishes)
+					return &entity.PublishResponse{Filtered: true}, nil
+				}
+				req.Message = out
+			}
+		}
+	}
+
+	if maxSize := c.config().MaxMessageSize; maxSize > 0 && len(req.Message) > maxSize {
+		c.inc(metricQuotaMessageSizeExceeded)
+		return nil, entity.Err{
+			Code:    entity.ErrorCodeRequestEntityTooLarge,
+			Kind:    entity.ErrorKindMessageTooLarge,
+			Message: "message exceeds the max message size for the pubsub",
+			Details: map[string]any{
+				"maxMessageSize": maxSize,
+				"messageSize":    len(req.Message),
+			},
+		}
+	}
+
+	// a retried publish carrying the same Idempotency-Key returns the original
+	// event id without delivering the message again
+	if req.IdempotencyKey != "" {
+		if id, ok := c.idem.get(req.PubSubID, req.IdempotencyKey); ok {
+			c.inc(metricDuplicatePublishes)
+			return &entity.PublishResponse{
+				ID: id,
+			}, nil
+		}
+	}
+
+	// a client-supplied event id seen recently on the same topic is a
+	// duplicate publish; the original id is returned without redelivery
+	if req.EventID != "" {
+		if id, ok := c.dedup.get(req.PubSubID, req.EventID); ok {
+			c.inc(metricDuplicatePublishes)
+			return &entity.PublishResponse{
+				ID:        id,
+				Duplicate: true,
+			}, nil
+		}
+	}
+
+	// the envelope carries the generated id and publish time so consumers can
+	// order events; the same id is returned to the publisher
+	id := c.idgen.Next()
+	eventID := req.EventID
+	if eventID == "" {
+		eventID = monoflake.ID(id).String()
+	}
+	envelope := encodeEnvelope(monoflake.ID(id).String(), req.EventType, req.Encoding, req.ContentType, req.Key, req.KeyID, req.Message, req.Headers)
+
+	var ack *ackTracker
+	if req.WaitDelivered {
+		ack = newAckTracker()
+	}
+
+	stats, err := c.publish(req.PubSubID, eventID, req.EventType, req.Headers, envelope, ack)
 	if err != nil {
 		return nil, err
 	}
+	if req.IdempotencyKey != "" {
+		c.idem.set(req.PubSubID, req.IdempotencyKey, id)
+	}
+	if req.EventID != "" {
+		c.dedup.set(req.PubSubID, req.EventID, id)
+	}
+	if t, ok := c.pubsubs.Load(req.PubSubID); ok {
+		if ps, ok := t.(*pubsub); ok {
+			if ps.persist {
+				c.archiveEvent(ps.id, eventID, req.EventType, envelope)
+			}
+			if req.Key != "" {
+				ps.storeKeyed(req.Key, &entity.Event{
+					ID:          eventID,
+					Type:        req.EventType,
+					Data:        envelope,
+					PublishedAt: time.Now(),
+				})
+			}
+		}
+	}
 	defer c.inc(metricMessageReceived)
-	defer c.incBy(metricMessageSent, int64(cnt))
+	defer c.incBy(metricMessageSent, int64(stats.enqueued))
+	c.tenantInc(tenant, metricMessageReceived, 1)
+	c.tenantInc(tenant, metricMessageSent, int64(stats.enqueued))
+	c.recordPublishUsage(tenant, int64(len(req.Message)), stats.enqueued, int64(len(envelope)))
+
+	c.notifyHook(HookEvent{
+		Kind:      HookKindPublish,
+		PubSubID:  req.PubSubID,
+		Tenant:    tenant,
+		EventID:   eventID,
+		EventType: req.EventType,
+	})
 
-	return &entity.PublishResponse{
-		ID: c.idgen.Next(),
-	}, nil
+	res := &entity.PublishResponse{
+		ID:          id,
+		Subscribers: stats.subscribers,
+		Enqueued:    stats.enqueued,
+		Dropped:     stats.dropped,
+	}
+
+	// a synchronous publish holds the response until every enqueued delivery
+	// reported back, bounded by the caller's timeout
+	if ack != nil {
+		timeout := req.WaitTimeout
+		if timeout <= 0 {
+			timeout = defaultPublishWaitTimeout
+		}
+		ack.arm(stats.enqueued)
+		res.Waited = true
+		res.Delivered, res.Failed, res.Pending = ack.wait(timeout)
+	}
+
+	return res, nil
 }
 
 func (c *controller) Subscribe(ctx context.Context, req entity.SubscribeRequest) (*entity.SubscribeResponse, error) {
@@ -251,6 +855,7 @@ func (c *controller) Subscribe(ctx context.Context, req entity.SubscribeRequest)
 	if !ok {
 		return nil, entity.Err{
 			Code:    404,
+			Kind:    entity.ErrorKindPubSubNotFound,
 			Message: "pubsub not found",
 			Details: map[string]any{
 				"id": req.PubSubID,
@@ -272,6 +877,7 @@ func (c *controller) Subscribe(ctx context.Context, req entity.SubscribeRequest)
 	if !bytes.Equal(pubsub.token, req.Token) {
 		return nil, entity.Err{
 			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
 			Message: "token mismatch for the pubsub",
 			Details: map[string]any{
 				"token": string(req.Token),
@@ -279,32 +885,228 @@ func (c *controller) Subscribe(ctx context.Context, req entity.SubscribeRequest)
 		}
 	}
 
+	if !pubsub.tokenExpiresAt.IsZero() && time.Now().UTC().After(pubsub.tokenExpiresAt) {
+		return nil, entity.Err{
+			Code:    entity.ErrorCodeGone,
+			Kind:    entity.ErrorKindTokenExpired,
+			Message: "token expired for the pubsub",
+			Details: map[string]any{
+				"id":             req.PubSubID,
+				"tokenExpiredAt": pubsub.tokenExpiresAt.Format(time.RFC3339),
+				"hint":           "rotate the token by recreating the pubsub or wait for the janitor to rotate it",
+			},
+		}
+	}
+
+	tr, err := parseTransform(req.Transform)
+	if err != nil {
+		return nil, entity.Err{
+			Code:    400,
+			Message: "invalid transform expression",
+			Details: map[string]any{
+				"transform": req.Transform,
+				"err":       err.Error(),
+			},
+		}
+	}
+
 	id := c.idgen.Next()
 
+	// a valid resumption token reattaches the client under its previous
+	// identity; any stream still open under that id is superseded so the
+	// subscriber never exists twice
+	resumeCursor := ""
+	if req.ResumptionToken != "" {
+		if rid, cursor, ok := decodeResumptionToken(pubsub.token, req.ResumptionToken); ok {
+			id = rid
+			resumeCursor = cursor
+			if old, found := pubsub.subscribers.take(rid); found {
+				go func(ch chan *entity.Event) {
+					_ = publishWithTimeout(ch, entity.CloseEvent(entity.CloseReasonSuperseded), c.config().MaxDurationForSubscriberToReceive)
+				}(old.channel)
+			}
+		}
+	}
+
 	subscriber := subscriber{
-		channel: make(chan *entity.Event),
-		id:      id,
+		channel:     make(chan *entity.Event),
+		id:          id,
+		filter:      newSubscriberFilter(req.Types, req.Attrs),
+		transform:   tr,
+		connectedAt: time.Now().UTC(),
+		lastFlush:   ptrInt64(time.Now().UnixNano()),
+	}
+
+	// topics may override the instance-wide subscriber quota
+	maxSubscribers := c.config().MaxSubscribersPerTopic
+	if pubsub.maxSubscribers > 0 {
+		maxSubscribers = pubsub.maxSubscribers
 	}
 
-	pubsub.mutex.Lock()
-	pubsub.subscribers = append(pubsub.subscribers, subscriber)
-	pubsub.mutex.Unlock()
+	if !pubsub.subscribers.add(subscriber, maxSubscribers) {
+		c.inc(metricQuotaSubscribersExceeded)
+		return nil, entity.Err{
+			Code:    entity.ErrorCodeTooManyRequests,
+			Kind:    entity.ErrorKindQuotaExceeded,
+			Message: "max subscribers quota exceeded for the pubsub",
+			Details: map[string]any{
+				"id":             req.PubSubID,
+				"maxSubscribers": maxSubscribers,
+			},
+		}
+	}
+	pubsub.touch()
+	c.emitPresence(pubsub, presenceActionJoin, subscriber.id)
 
 	defer c.inc(metricActiveSubscribers)
 	defer c.inc(metricSubscribers)
 
+	cfg := c.config()
+
+	// replay retained events past the client's Last-Event-ID so a
+	// reconnecting stream resumes where it left off; events published while
+	// the replay runs are delivered afterwards in fan-out order. The header
+	// wins over the resumption token's cursor since it is always fresher.
+	replayFrom := req.LastEventID
+	if replayFrom == "" {
+		replayFrom = resumeCursor
+	}
+	replay := pubsub.retained.afterEventID(replayFrom, 100)
+	// ?snapshot=true delivers the newest value of every key ahead of the
+	// replay and the live stream — the dashboard "state + deltas" pattern
+	if req.Snapshot {
+		replay = append(pubsub.keyedSnapshot(), replay...)
+	}
+	if len(replay) > 0 {
+		go func(ch chan *entity.Event, events []*entity.Event, timeout time.Duration) {
+			for _, ev := range events {
+				if err := publishWithTimeout(ch, ev, timeout); err != nil {
+					zlog.Warn().Err(err).Int64("pubsubID", req.PubSubID).
+						Str("requestID", entity.RequestIDFromContext(ctx)).
+						Msg(logPrefix + "failed to replay retained event to subscriber")
+					return
+				}
+			}
+		}(subscriber.channel, replay, cfg.MaxDurationForSubscriberToReceive)
+	}
+	// topics may override the instance-wide heartbeat interval
+	tickFrequency := cfg.TickFrequency
+	if pubsub.tickFrequency > 0 {
+		tickFrequency = pubsub.tickFrequency
+	}
+
+	// topics may also override the reconnect retry hint; when the topic is
+	// near its subscriber cap the hint is doubled, so a mass disconnect
+	// trickles back instead of reconnecting all at once
+	retryHint := cfg.ReconnectRetryHint
+	if pubsub.retryHint > 0 {
+		retryHint = pubsub.retryHint
+	}
+	if retryHint > 0 && maxSubscribers > 0 && pubsub.subscribers.len()*5 >= maxSubscribers*4 {
+		retryHint *= 2
+	}
+
+	keepaliveStyle := cfg.KeepaliveStyle
+	if keepaliveStyle == "" {
+		keepaliveStyle = entity.KeepaliveStyleComment
+	}
+
+	c.notifyHook(HookEvent{
+		Kind:         HookKindSubscribe,
+		PubSubID:     pubsub.id,
+		Tenant:       pubsub.tenant,
+		Topic:        pubsub.name,
+		SubscriberID: subscriber.id,
+	})
+
 	return &entity.SubscribeResponse{
-		ID:            subscriber.id,
-		Events:        subscriber.channel,
-		TickFrequency: c.cfg.TickFrequency,
+		ID:                 subscriber.id,
+		Events:             subscriber.channel,
+		TickFrequency:      tickFrequency,
+		MaxStreamLifetime:  cfg.MaxStreamLifetime,
+		ReconnectRetryHint: retryHint,
+		Profile:            pubsub.profile,
+		KeepaliveStyle:     keepaliveStyle,
+		ResumptionToken:    encodeResumptionToken(pubsub.token, subscriber.id, pubsub.retained.lastEventID()),
+		RetentionAvailable: pubsub.retained.capacity() > 0,
+		MarkFlush:          subscriber.markFlush,
 	}, nil
 }
 
+// Poll serves the long-poll fallback transport from the retention buffer; it
+// returns as soon as events past the cursor exist, or with an empty batch
+// once the long-poll timeout elapses
+func (c *controller) Poll(ctx context.Context, req entity.PollRequest) (*entity.PollResponse, error) {
+	t, ok := c.pubsubs.Load(req.PubSubID)
+	if !ok {
+		return nil, entity.Err{
+			Code:    404,
+			Kind:    entity.ErrorKindPubSubNotFound,
+			Message: "pubsub not found",
+			Details: map[string]any{
+				"id": req.PubSubID,
+			},
+		}
+	}
+
+	pubsub, ok := t.(*pubsub)
+	if !ok {
+		return nil, entity.Err{
+			Code:    500,
+			Message: "malformed pubsub",
+			Details: map[string]any{
+				"id": req.PubSubID,
+			},
+		}
+	}
+
+	if !bytes.Equal(pubsub.token, req.Token) {
+		return nil, entity.Err{
+			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
+			Message: "token mismatch for the pubsub",
+			Details: map[string]any{
+				"token": string(req.Token),
+			},
+		}
+	}
+
+	if pubsub.retained == nil {
+		return nil, entity.Err{
+			Code:    400,
+			Message: "retention buffer is not enabled, long-polling is unavailable",
+			Details: map[string]any{
+				"id": req.PubSubID,
+			},
+		}
+	}
+
+	deadline := time.Now().Add(c.config().LongPollTimeout)
+	for {
+		events, cursor := pubsub.retained.after(req.Cursor, 100)
+		if len(events) > 0 || !time.Now().Before(deadline) {
+			return &entity.PollResponse{
+				Events: events,
+				Cursor: cursor,
+			}, nil
+		}
+		select {
+		case <-ctx.Done():
+			return &entity.PollResponse{
+				Events: events,
+				Cursor: cursor,
+			}, nil
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
 func (c *controller) Unsubscribe(ctx context.Context, req entity.UnsubscribeRequest) error {
 	t, ok := c.pubsubs.Load(req.PubSubID)
 	if !ok {
 		return entity.Err{
 			Code:    404,
+			Kind:    entity.ErrorKindPubSubNotFound,
 			Message: "pubsub not found",
 			Details: map[string]any{
 				"id": req.PubSubID,
@@ -326,6 +1128,7 @@ func (c *controller) Unsubscribe(ctx context.Context, req entity.UnsubscribeRequ
 	if !bytes.Equal(pubsub.token, req.Token) {
 		return entity.Err{
 			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
 			Message: "token mismatch for the pubsub",
 			Details: map[string]any{
 				"token": string(req.Token[:]),
@@ -333,23 +1136,26 @@ func (c *controller) Unsubscribe(ctx context.Context, req entity.UnsubscribeRequ
 		}
 	}
 
-	pubsub.mutex.Lock()
-	for i := 0; i < len(pubsub.subscribers); i++ {
-		if pubsub.subscribers[i].id == req.ID {
-			pubsub.subscribers[i], pubsub.subscribers[len(pubsub.subscribers)-1] = pubsub.subscribers[len(pubsub.subscribers)-1], pubsub.subscribers[i]
-			pubsub.subscribers = pubsub.subscribers[0 : len(pubsub.subscribers)-1]
-			break
-		}
+	removed := pubsub.subscribers.remove(req.ID)
+	if removed {
+		c.emitPresence(pubsub, presenceActionLeave, req.ID)
+		c.notifyHook(HookEvent{
+			Kind:         HookKindUnsubscribe,
+			PubSubID:     pubsub.id,
+			Tenant:       pubsub.tenant,
+			Topic:        pubsub.name,
+			SubscriberID: req.ID,
+		})
 	}
-	pubsub.mutex.Unlock()
 	defer c.dec(metricActiveSubscribers)
 	return nil
 }
 
 func (c *controller) GetMetrics(ctx context.Context, req entity.GetMetricsRequest) (*entity.GetMetricsResponse, error) {
-	if req.MetricsAccessToken != c.cfg.MetricsAccessToken {
+	if req.MetricsAccessToken != c.config().MetricsAccessToken {
 		return nil, entity.Err{
 			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
 			Message: "API access token mismatch",
 			Details: map[string]any{
 				"token": req.MetricsAccessToken,
@@ -357,16 +1163,166 @@ func (c *controller) GetMetrics(ctx context.Context, req entity.GetMetricsReques
 		}
 	}
 
+	// a since/window query turns the counters into deltas against the
+	// newest retained snapshot not after the requested start; only counters
+	// are retained, so tenant metrics and histograms stay absolute
+	since := req.Since
+	if since.IsZero() && req.Window > 0 {
+		since = time.Now().UTC().Add(-req.Window)
+	}
+	var base metricsSnapshot
+	deltas := false
+	if !since.IsZero() {
+		base, deltas = c.history.before(since)
+	}
+
 	metrics := make([]entity.Metric, 0, len(c.metrics.vals))
 	for k := range c.metrics.vals {
+		val := c.get(k)
+		if deltas {
+			val -= base.vals[k]
+		}
 		metrics = append(metrics, entity.Metric{
 			Name:  k.String(),
-			Value: float64(c.get(k)),
+			Value: float64(val),
 		})
 	}
 
-	return &entity.GetMetricsResponse{
+	// tenant-scoped metrics are exposed with a tenant prefix
+	metrics = append(metrics, c.tenantMetricsSnapshot()...)
+	metrics = append(metrics, c.fanoutLatency.Snapshot()...)
+	metrics = append(metrics, c.storeStats(ctx)...)
+
+	res := &entity.GetMetricsResponse{
 		Metrics: metrics,
+	}
+	if deltas {
+		res.Since = base.at
+	}
+	return res, nil
+}
+
+// storeStats reports file-level storage statistics alongside the counters
+// when the backend supports them: on-disk size, free pages and per-bucket key
+// counts named "kv_keys.<bucket>"
+func (c *controller) storeStats(ctx context.Context) []entity.Metric {
+	statser, ok := c.kv.(kv.Compactor)
+	if !ok {
+		return nil
+	}
+	stats, err := statser.Stats(ctx)
+	if err != nil {
+		zlog.Warn().Err(err).Msg(logPrefix + "failed to read store stats for the metrics endpoint")
+		return nil
+	}
+
+	out := make([]entity.Metric, 0, 2+len(stats.BucketKeys))
+	out = append(out, entity.Metric{
+		Name:  "kv_size_bytes",
+		Value: float64(stats.SizeBytes),
+	})
+	out = append(out, entity.Metric{
+		Name:  "kv_free_pages",
+		Value: float64(stats.FreePages),
+	})
+	out = append(out, entity.Metric{
+		Name:  "kv_reopens",
+		Value: float64(stats.Reopens),
+	})
+	for bucket, keys := range stats.BucketKeys {
+		if bucket == "" {
+			bucket = "_default"
+		}
+		out = append(out, entity.Metric{
+			Name:  "kv_keys." + bucket,
+			Value: float64(keys),
+		})
+	}
+	return out
+}
+
+// Ready reports whether the instance can serve traffic; with persistence
+// enabled the storage backend must answer a ping, which also gives it the
+// chance to recover a dead handle. Backs the /readyz probe.
+func (c *controller) Ready(ctx context.Context) error {
+	if c.kv == nil {
+		return nil
+	}
+	if err := c.kv.Ping(ctx); err != nil {
+		c.inc(metricStorePingFailures)
+		zlog.Error().Err(err).Msg(logPrefix + "storage ping failed; reporting unready")
+		return err
+	}
+	return nil
+}
+
+// tenantMetricsSnapshot flattens the tenant-scoped registries into metrics
+// named "<tenant>.<metric>"
+func (c *controller) tenantMetricsSnapshot() []entity.Metric {
+	out := make([]entity.Metric, 0)
+	c.tenantMetrics.Range(func(key, val any) bool {
+		tenant, ok := key.(string)
+		if !ok {
+			return true
+		}
+		tm, ok := val.(*metrics)
+		if !ok {
+			return true
+		}
+		for k := range tm.vals {
+			if v := tm.get(k); v != 0 {
+				out = append(out, entity.Metric{
+					Name:  tenant + "." + k.String(),
+					Value: float64(v),
+				})
+			}
+		}
+		return true
+	})
+	return out
+}
+
+func (c *controller) GetTopicRates(ctx context.Context, req entity.GetTopicRatesRequest) (*entity.GetTopicRatesResponse, error) {
+	if req.MetricsAccessToken != c.config().MetricsAccessToken {
+		return nil, entity.Err{
+			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
+			Message: "API access token mismatch",
+			Details: map[string]any{
+				"token": req.MetricsAccessToken,
+			},
+		}
+	}
+
+	t, ok := c.pubsubs.Load(req.PubSubID)
+	if !ok {
+		return nil, entity.Err{
+			Code:    404,
+			Kind:    entity.ErrorKindPubSubNotFound,
+			Message: "pubsub not found",
+			Details: map[string]any{
+				"id": req.PubSubID,
+			},
+		}
+	}
+
+	pubsub, ok := t.(*pubsub)
+	if !ok {
+		return nil, entity.Err{
+			Code:    500,
+			Message: "malformed pubsub",
+			Details: map[string]any{
+				"id": req.PubSubID,
+			},
+		}
+	}
+
+	last1s, last10s, last60s := pubsub.rates.sums()
+	return &entity.GetTopicRatesResponse{
+		PubSubID:   req.PubSubID,
+		LastSecond: last1s,
+		Last10Secs: last10s,
+		LastMinute: last60s,
 	}, nil
 }
 
@@ -376,71 +1332,152 @@ func (c *controller) registerPersistentPubSubs() error {
 		return nil
 	}
 
-	keys, err := c.kv.ListKeys(context.Background())
-	if err != nil {
-		return err
-	}
 	ctx := context.Background()
+	tenants := []string{""}
+	for _, t := range c.config().Tenants {
+		tenants = append(tenants, t.Name)
+	}
+
 	cnt := int64(0)
-	for _, k := range keys {
-		id := monoflake.IDFromBigEndianBytes(k).Int64()
-		token, err := c.kv.Get(ctx, k)
+	for _, tenant := range tenants {
+		keys, err := c.kv.ListKeysIn(ctx, tenant)
 		if err != nil {
-			zlog.Error().Err(err).Int64("id", id).Msg(logPrefix + "failed to load pubsub from storage; going on with the next one.")
-			continue
+			return err
+		}
+		for _, k := range keys {
+			id := monoflake.IDFromBigEndianBytes(k).Int64()
+			record, err := c.kv.GetIn(ctx, tenant, k)
+			if err != nil {
+				zlog.Error().Err(err).Int64("id", id).Msg(logPrefix + "failed to load pubsub from storage; going on with the next one.")
+				continue
+			}
+			r := decodeTokenRecord(record)
+			if !c.registerName(r.name, id) {
+				zlog.Warn().Int64("id", id).Str("name", r.name).Msg(logPrefix + "topic name already claimed; keeping the topic reachable by id only")
+				r.name = ""
+			}
+			retentionSize := c.config().RetentionBufferSize
+			if r.retentionSize > 0 {
+				retentionSize = r.retentionSize
+			}
+			c.pubsubs.Store(id, &pubsub{
+				id:             id,
+				persist:        true,
+				tenant:         tenant,
+				name:           r.name,
+				subscribers:    newSubscriberList(),
+				mutex:          sync.RWMutex{},
+				rates:          newRateWindow(),
+				retained:       newRetentionBuffer(retentionSize),
+				token:          r.token,
+				tokenExpiresAt: r.tokenExpiresAt,
+				tickFrequency:  r.tickFrequency,
+				retryHint:      r.retryHint,
+				maxSubscribers: r.maxSubscribers,
+			})
+			c.tenantInc(tenant, metricTopics, 1)
+			c.tenantInc(tenant, metricActiveTopics, 1)
+			cnt++
 		}
-		c.pubsubs.Store(id, &pubsub{
-			id:          id,
-			subscribers: make([]subscriber, 0),
-			mutex:       sync.RWMutex{},
-			token:       token,
-		})
-		cnt++
 	}
 	c.incBy(metricTopics, cnt)
 	c.incBy(metricActiveTopics, cnt)
 	return nil
 }
 
+// resolveToken returns the static topic's access token, falling back to the
+// tokenEnv environment variable when the literal token is empty
+func (ps StaticPubSubConfig) resolveToken() []byte {
+	if ps.Token != "" {
+		return []byte(ps.Token)
+	}
+	if ps.TokenEnv != "" {
+		return []byte(os.Getenv(ps.TokenEnv))
+	}
+	return nil
+}
+
 func (c *controller) registerStaticPubSubs() error {
-	// it is used for publishing system metrics (do not override!)
-	c.pubsubs.Store(int64(0), &pubsub{
-		id:          0, // reserved id
-		static:      true,
-		subscribers: make([]subscriber, 0),
-		mutex:       sync.RWMutex{},
-		token:       []byte(c.cfg.MetricsAccessToken),
-	})
+	// the reserved system topic publishes metrics and alerts; the
+	// metricsTopic section can move it off id 0 or disable it entirely (do
+	// not reuse its id for a static topic!)
+	mcfg := c.config().MetricsTopic
+	if !mcfg.Disabled {
+		c.pubsubs.Store(mcfg.ID, &pubsub{
+			id:          mcfg.ID, // reserved id
+			static:      true,
+			subscribers: newSubscriberList(),
+			mutex:       sync.RWMutex{},
+			rates:       newRateWindow(),
+			retained:    newRetentionBuffer(c.config().RetentionBufferSize),
+			token:       []byte(c.config().MetricsAccessToken),
+		})
+	}
 
-	for _, ps := range c.cfg.StaticPubSubs {
+	// a duplicated id or name in the static list is a config mistake that
+	// would silently shadow a topic, so it fails startup instead
+	seenIDs := map[int64]struct{}{}
+	if !mcfg.Disabled {
+		seenIDs[mcfg.ID] = struct{}{}
+	}
+	seenNames := map[string]int64{}
+
+	for _, ps := range c.config().StaticPubSubs {
 		if ps.ID == 0 {
 			return fmt.Errorf("[pubsub] id for static token must be >= 1 (name: %s)", ps.Name)
 		}
+		if _, ok := seenIDs[ps.ID]; ok {
+			return fmt.Errorf("[pubsub] duplicate static pubsub id %d (name: %s)", ps.ID, ps.Name)
+		}
+		seenIDs[ps.ID] = struct{}{}
+		if ps.Name != "" {
+			if other, ok := seenNames[ps.Name]; ok {
+				return fmt.Errorf("[pubsub] duplicate static pubsub name %s (ids: %d, %d)", ps.Name, other, ps.ID)
+			}
+			seenNames[ps.Name] = ps.ID
+		}
 
-		token := []byte(ps.Token)
+		token := ps.resolveToken()
 		if len(token) < 1 {
-			return fmt.Errorf("[pubsub] token must be >= 1 chars (name: %s)", ps.Name)
+			return fmt.Errorf("[pubsub] token must be >= 1 chars (name: %s, tokenEnv: %s)", ps.Name, ps.TokenEnv)
+		}
+		retentionSize := c.config().RetentionBufferSize
+		if ps.RetentionBufferSize > 0 {
+			retentionSize = ps.RetentionBufferSize
 		}
 		c.pubsubs.Store(ps.ID, &pubsub{
-			id:          ps.ID,
-			static:      true,
-			subscribers: make([]subscriber, 0),
-			mutex:       sync.RWMutex{},
-			token:       []byte(token),
+			id:             ps.ID,
+			static:         true,
+			name:           ps.Name,
+			subscribers:    newSubscriberList(),
+			mutex:          sync.RWMutex{},
+			rates:          newRateWindow(),
+			retained:       newRetentionBuffer(retentionSize),
+			token:          token,
+			tickFrequency:  ps.TickFrequency,
+			maxSubscribers: ps.MaxSubscribers,
 		})
+		if !c.registerName(ps.Name, ps.ID) {
+			zlog.Warn().Int64("id", ps.ID).Str("name", ps.Name).Msg(logPrefix + "static topic name already claimed; keeping it reachable by id only")
+		}
 	}
 
-	c.incBy(metricTopics, int64(len(c.cfg.StaticPubSubs)+1))
-	c.incBy(metricActiveTopics, int64(len(c.cfg.StaticPubSubs)+1))
-	c.incBy(metricStaticTopics, int64(len(c.cfg.StaticPubSubs)+1))
+	cnt := int64(len(c.config().StaticPubSubs))
+	if !mcfg.Disabled {
+		cnt++
+	}
+	c.incBy(metricTopics, cnt)
+	c.incBy(metricActiveTopics, cnt)
+	c.incBy(metricStaticTopics, cnt)
 	return nil
 }
 
-func (c *controller) publish(id int64, eventID, eventType string, msg []byte) (int, error) {
+func (c *controller) publish(id int64, eventID, eventType string, headers map[string]string, msg []byte, ack *ackTracker) (deliveryStats, error) {
 	t, ok := c.pubsubs.Load(id)
 	if !ok {
-		return 0, entity.Err{
+		return deliveryStats{}, entity.Err{
 			Code:    404,
+			Kind:    entity.ErrorKindPubSubNotFound,
 			Message: "pubsub not found",
 			Details: map[string]any{
 				"id": id,
@@ -448,9 +1485,9 @@ func (c *controller) publish(id int64, eventID, eventType string, msg []byte) (i
 		}
 	}
 
-	pubsub, ok := t.(*pubsub)
+	ps, ok := t.(*pubsub)
 	if !ok {
-		return 0, entity.Err{
+		return deliveryStats{}, entity.Err{
 			Code:    500,
 			Message: "malformed pubsub, please create another pubsub",
 			Details: map[string]any{
@@ -459,49 +1496,102 @@ func (c *controller) publish(id int64, eventID, eventType string, msg []byte) (i
 		}
 	}
 
-	pubsub.mutex.RLock()
-	subscribers := pubsub.subscribers
-	pubsub.mutex.RUnlock()
+	ev := &entity.Event{
+		ID:          eventID,
+		Type:        eventType,
+		Data:        msg,
+		PublishedAt: time.Now(),
+	}
+	stats := c.fanOut(ps, ev, headers, ack)
 
-	go func(msg *entity.Event, subscribers []subscriber) {
-		timeoutDuration := c.cfg.MaxDurationForSubscriberToReceive
-		wg := sync.WaitGroup{}
-		for _, s := range subscribers {
-			wg.Add(1)
-			go func(ch chan *entity.Event) {
-				defer wg.Done()
-				err := publishWithTimeout(ch, msg, timeoutDuration)
-				if err != nil {
-					zlog.Error().Err(err).Dur("timeout", timeoutDuration).
-						Msg(logPrefix + "failed to send message to subscriber within the given timeout duration")
-				}
-			}(s.channel)
+	// routed topics mirror matching events into their targets; forwarding is
+	// a single hop, so route cycles can't amplify a publish
+	for _, rt := range c.routesFrom(id) {
+		if !rt.filter.matches(eventType, headers) {
+			continue
+		}
+		t, ok := c.pubsubs.Load(rt.to)
+		if !ok {
+			continue
+		}
+		target, ok := t.(*pubsub)
+		if !ok {
+			continue
 		}
-		wg.Wait()
-	}(&entity.Event{
-		ID:   eventID,
-		Type: eventType,
-		Data: msg,
-	}, subscribers)
+		stats = stats.merge(c.fanOut(target, ev, headers, ack))
+	}
+
+	return stats, nil
+}
+
+// fanOut delivers an event to the topic's matching subscribers and returns
+// the enqueue-time delivery stats
+func (c *controller) fanOut(ps *pubsub, ev *entity.Event, headers map[string]string, ack *ackTracker) deliveryStats {
+	ps.rates.incr(1)
+	ps.touch()
+	ps.retained.add(ev)
+
+	snapshot := ps.subscribers.snapshot()
+	subscribers := make([]subscriber, 0, len(snapshot))
+	for _, s := range snapshot {
+		if s.filter.matches(ev.Type, headers) {
+			subscribers = append(subscribers, s)
+		}
+	}
+
+	// fan out through the bounded worker pool; routing by subscriber id keeps
+	// per-subscriber delivery in publish order
+	now := time.Now()
+	for _, s := range subscribers {
+		c.fanout.enqueue(deliveryJob{pubsubID: ps.id, sub: s, ev: ev, enqueuedAt: now, ack: ack})
+	}
+
+	return deliveryStats{
+		subscribers: len(snapshot),
+		enqueued:    len(subscribers),
+		dropped:     len(snapshot) - len(subscribers),
+	}
+}
+
+// merge combines delivery stats across the origin topic and its routed
+// targets
+func (s deliveryStats) merge(o deliveryStats) deliveryStats {
+	s.subscribers += o.subscribers
+	s.enqueued += o.enqueued
+	s.dropped += o.dropped
+	return s
+}
 
-	return len(subscribers), nil
+// runMetricsPublisher periodically publishes a snapshot of every counter to
+// the system metrics topic; counter updates themselves stay purely atomic so
+// the publish hot path never pays for metric emission. The interval is
+// re-read each round, so config reloads take effect without a restart.
+func (c *controller) runMetricsPublisher() {
+	for {
+		mcfg := c.config().MetricsTopic
+		interval := mcfg.SamplingInterval
+		if interval <= 0 {
+			interval = defaultMetricsPublishInterval
+		}
+		time.Sleep(interval)
+		// the history ring is fed even when the topic is disabled so delta
+		// queries keep working
+		c.history.record(c.metrics)
+		if mcfg := c.config().MetricsTopic; !mcfg.Disabled {
+			_, _ = c.publish(mcfg.ID, "", "", nil, c.metrics.snapshotPayload(), nil)
+		}
+	}
 }
 
 func (c *controller) inc(k metric) {
-	msg := fmt.Sprintf(`{"val": 1, "metric": "%s"}`, k.String())
-	_, _ = c.publish(0, "", "", []byte(msg))
 	c.metrics.inc(k)
 }
 
 func (c *controller) incBy(k metric, val int64) {
-	msg := fmt.Sprintf(`{"val": %d, "metric": "%s"}`, val, k.String())
-	_, _ = c.publish(0, "", "", []byte(msg))
 	c.metrics.incBy(k, val)
 }
 
 func (c *controller) dec(k metric) {
-	msg := fmt.Sprintf(`{"val": -1, "metric": "%s"}`, k.String())
-	_, _ = c.publish(0, "", "", []byte(msg))
 	c.metrics.dec(k)
 }
 
@@ -509,8 +1599,122 @@ func (c *controller) get(k metric) int64 {
 	return c.metrics.get(k)
 }
 
+func (c *controller) set(k metric, val int64) {
+	c.metrics.set(k, val)
+}
+
+func (c *controller) runTokenJanitor() {
+	ticker := time.NewTicker(c.config().TokenJanitorFrequency)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.rotateExpiredTokens()
+	}
+}
+
+func (c *controller) rotateExpiredTokens() {
+	now := time.Now().UTC()
+	c.pubsubs.Range(func(key, val any) bool {
+		ps, ok := val.(*pubsub)
+		if !ok || ps.static || ps.tokenExpiresAt.IsZero() || now.Before(ps.tokenExpiresAt) {
+			return true
+		}
+
+		token, err := generateRandom64()
+		if err != nil {
+			zlog.Error().Err(err).Int64("id", ps.id).Msg(logPrefix + "failed to generate a fresh token on rotation")
+			return true
+		}
+
+		ps.mutex.Lock()
+		ps.token = []byte(token)
+		ps.tokenExpiresAt = now.Add(ps.tokenTTL)
+		tokenExpiresAt := ps.tokenExpiresAt
+		c.closeSubscribers(ps, entity.CloseReasonExpired)
+		ps.mutex.Unlock()
+
+		if ps.persist && c.kv != nil {
+			err := c.kv.SetIn(context.Background(), ps.tenant, monoflake.ID(ps.id).BigEndianBytes(), encodeTokenRecord(ps.record()))
+			if err != nil {
+				zlog.Error().Err(err).Int64("id", ps.id).Msg(logPrefix + "failed to persist the rotated token")
+			}
+		}
+
+		zlog.Info().Int64("id", ps.id).Time("tokenExpiresAt", tokenExpiresAt).Msg(logPrefix + "rotated expired token")
+		return true
+	})
+}
+
 // independent functions
 
+// tokenRecord is the pipe-delimited per-topic persistence record; fields were
+// appended over time, so decoding tolerates shorter records written by older
+// versions and encoding trims trailing zero-value fields
+type tokenRecord struct {
+	token          []byte
+	tokenExpiresAt time.Time
+	name           string
+	tickFrequency  time.Duration
+	retentionSize  int
+	maxSubscribers int
+	retryHint      time.Duration
+}
+
+func encodeTokenRecord(r tokenRecord) []byte {
+	unix := int64(0)
+	if !r.tokenExpiresAt.IsZero() {
+		unix = r.tokenExpiresAt.Unix()
+	}
+	parts := []string{
+		string(r.token),
+		strconv.FormatInt(unix, 10),
+		r.name,
+		strconv.FormatInt(int64(r.tickFrequency), 10),
+		strconv.Itoa(r.retentionSize),
+		strconv.Itoa(r.maxSubscribers),
+		strconv.FormatInt(int64(r.retryHint), 10),
+	}
+	zeroes := []string{"", "0", "", "0", "0", "0", "0"}
+	last := len(parts)
+	for last > 1 && parts[last-1] == zeroes[last-1] {
+		last--
+	}
+	return []byte(strings.Join(parts[:last], "|"))
+}
+
+func decodeTokenRecord(record []byte) tokenRecord {
+	parts := bytes.SplitN(record, []byte("|"), 7)
+	r := tokenRecord{token: parts[0]}
+	if len(parts) > 1 {
+		if unix, err := strconv.ParseInt(string(parts[1]), 10, 64); err == nil && unix != 0 {
+			r.tokenExpiresAt = time.Unix(unix, 0).UTC()
+		}
+	}
+	if len(parts) > 2 {
+		r.name = string(parts[2])
+	}
+	if len(parts) > 3 {
+		if ns, err := strconv.ParseInt(string(parts[3]), 10, 64); err == nil && ns > 0 {
+			r.tickFrequency = time.Duration(ns)
+		}
+	}
+	if len(parts) > 4 {
+		if size, err := strconv.Atoi(string(parts[4])); err == nil && size > 0 {
+			r.retentionSize = size
+		}
+	}
+	if len(parts) > 5 {
+		if max, err := strconv.Atoi(string(parts[5])); err == nil && max > 0 {
+			r.maxSubscribers = max
+		}
+	}
+	if len(parts) > 6 {
+		if ns, err := strconv.ParseInt(string(parts[6]), 10, 64); err == nil && ns > 0 {
+			r.retryHint = time.Duration(ns)
+		}
+	}
+	return r
+}
+
 func generateRandom64() (string, error) {
 	b := make([]byte, 64)
 	_, err := rand.Read(b)
@@ -0,0 +1,40 @@
+package pubsub
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+)
+
+// encodeEnvelope wraps a published message in the structured envelope carried
+// on the wire; binary payloads are base64-encoded with an encoding marker and
+// non-JSON text is embedded as a JSON string, so the envelope itself always
+// stays valid JSON
+func encodeEnvelope(id, eventType, encoding, contentType, key, keyID string, msg []byte, headers map[string]string) []byte {
+	var data json.RawMessage
+	switch {
+	case encoding == entity.EncodingBase64:
+		data, _ = json.Marshal(base64.StdEncoding.EncodeToString(msg))
+	case json.Valid(msg):
+		data = json.RawMessage(msg)
+	default:
+		data, _ = json.Marshal(string(msg))
+	}
+
+	envelope := entity.Envelope{
+		ID:          id,
+		Type:        eventType,
+		PublishedAt: time.Now().UTC(),
+		Encoding:    encoding,
+		ContentType: contentType,
+		Key:         key,
+		KeyID:       keyID,
+		Data:        data,
+		Headers:     headers,
+	}
+
+	out, _ := json.Marshal(envelope)
+	return out
+}
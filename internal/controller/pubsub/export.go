@@ -0,0 +1,287 @@
+package pubsub
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mustafaturan/monoflake"
+	zlog "github.com/rs/zerolog/log"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+)
+
+// exportBundleVersion guards against replaying bundles produced by an
+// incompatible build.
+const exportBundleVersion = 1
+
+type (
+	// exportBundle is the portable snapshot carried inside the encrypted
+	// export; it holds everything needed to recreate topics with their
+	// credentials on another instance or storage backend.
+	exportBundle struct {
+		Version int             `json:"version"`
+		Topics  []exportedTopic `json:"topics"`
+	}
+
+	exportedTopic struct {
+		ID             int64  `json:"id"`
+		Tenant         string `json:"tenant,omitempty"`
+		Name           string `json:"name,omitempty"`
+		Persist        bool   `json:"persist,omitempty"`
+		Token          string `json:"token"`
+		TokenExpiresAt int64  `json:"token_expires_at,omitempty"`
+		TickFrequency  int64  `json:"tick_frequency_ns,omitempty"`
+		RetentionSize  int    `json:"retention_size,omitempty"`
+		MaxSubscribers int    `json:"max_subscribers,omitempty"`
+		RetryHint      int64  `json:"retry_hint_ns,omitempty"`
+	}
+)
+
+// bundleCipher builds the AES-GCM cipher for the caller-supplied hex key;
+// the same helper serves export and import so both sides agree on framing.
+func bundleCipher(key string) (cipher.AEAD, error) {
+	secret, err := hex.DecodeString(key)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ExportTopics seals every live topic's metadata and token into an encrypted
+// bundle; tenant-scoped API keys only export their own topics while the
+// instance-wide key exports everything. Static topics come from config, so
+// they are not part of the bundle.
+func (c *controller) ExportTopics(ctx context.Context, req entity.ExportTopicsRequest) (*entity.ExportTopicsResponse, error) {
+	tenant, ok := c.tenantForAPIToken(req.ApiAccessToken)
+	if !ok {
+		return nil, entity.Err{
+			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
+			Message: "API access token mismatch",
+			Details: map[string]any{
+				"token": req.ApiAccessToken,
+			},
+		}
+	}
+
+	aead, err := bundleCipher(req.Key)
+	if err != nil {
+		return nil, entity.Err{
+			Code:    400,
+			Kind:    entity.ErrorKindValidationFailed,
+			Message: "bundle key must be a hex-encoded AES key (16, 24 or 32 bytes decoded)",
+			Details: map[string]any{
+				"err": err.Error(),
+			},
+		}
+	}
+
+	all := tenant == ""
+	bundle := exportBundle{Version: exportBundleVersion}
+	c.pubsubs.Range(func(_, val any) bool {
+		ps, ok := val.(*pubsub)
+		if !ok || ps.static {
+			return true
+		}
+		if !all && ps.tenant != tenant {
+			return true
+		}
+		t := exportedTopic{
+			ID:             ps.id,
+			Tenant:         ps.tenant,
+			Name:           ps.name,
+			Persist:        ps.persist,
+			Token:          string(ps.token),
+			TickFrequency:  int64(ps.tickFrequency),
+			RetentionSize:  ps.retained.capacity(),
+			MaxSubscribers: ps.maxSubscribers,
+			RetryHint:      int64(ps.retryHint),
+		}
+		if !ps.tokenExpiresAt.IsZero() {
+			t.TokenExpiresAt = ps.tokenExpiresAt.Unix()
+		}
+		bundle.Topics = append(bundle.Topics, t)
+		return true
+	})
+	sort.Slice(bundle.Topics, func(i, j int) bool { return bundle.Topics[i].ID < bundle.Topics[j].ID })
+
+	raw, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, entity.Err{
+			Code:    500,
+			Message: "Couldn't encode the export bundle",
+			Details: map[string]any{
+				"err": err.Error(),
+			},
+		}
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, entity.Err{
+			Code:    500,
+			Message: "Couldn't generate a nonce for the export bundle",
+			Details: map[string]any{
+				"err": err.Error(),
+			},
+		}
+	}
+
+	zlog.Info().Int("topics", len(bundle.Topics)).Msg(logPrefix + "exported topic bundle")
+	return &entity.ExportTopicsResponse{
+		Bundle: aead.Seal(nonce, nonce, raw, nil),
+	}, nil
+}
+
+// ImportTopics replays an exported bundle: topics whose id is already live
+// are skipped, names that collide stay reachable by id only, and persistent
+// topics are written back to this instance's store. Tenant-scoped API keys
+// can only import topics belonging to their own tenant.
+func (c *controller) ImportTopics(ctx context.Context, req entity.ImportTopicsRequest) (*entity.ImportTopicsResponse, error) {
+	tenant, ok := c.tenantForAPIToken(req.ApiAccessToken)
+	if !ok {
+		return nil, entity.Err{
+			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
+			Message: "API access token mismatch",
+			Details: map[string]any{
+				"token": req.ApiAccessToken,
+			},
+		}
+	}
+
+	aead, err := bundleCipher(req.Key)
+	if err != nil {
+		return nil, entity.Err{
+			Code:    400,
+			Kind:    entity.ErrorKindValidationFailed,
+			Message: "bundle key must be a hex-encoded AES key (16, 24 or 32 bytes decoded)",
+			Details: map[string]any{
+				"err": err.Error(),
+			},
+		}
+	}
+
+	if len(req.Bundle) < aead.NonceSize() {
+		return nil, entity.Err{
+			Code:    400,
+			Kind:    entity.ErrorKindValidationFailed,
+			Message: "malformed export bundle",
+		}
+	}
+	raw, err := aead.Open(nil, req.Bundle[:aead.NonceSize()], req.Bundle[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, entity.Err{
+			Code:    400,
+			Kind:    entity.ErrorKindValidationFailed,
+			Message: "bundle key mismatch or corrupted bundle",
+		}
+	}
+
+	var bundle exportBundle
+	if err := json.Unmarshal(raw, &bundle); err != nil {
+		return nil, entity.Err{
+			Code:    400,
+			Kind:    entity.ErrorKindValidationFailed,
+			Message: "malformed export bundle",
+			Details: map[string]any{
+				"err": err.Error(),
+			},
+		}
+	}
+	if bundle.Version != exportBundleVersion {
+		return nil, entity.Err{
+			Code:    400,
+			Kind:    entity.ErrorKindValidationFailed,
+			Message: "export bundle version is not supported",
+			Details: map[string]any{
+				"version":   bundle.Version,
+				"supported": exportBundleVersion,
+			},
+		}
+	}
+
+	imported, skipped := 0, 0
+	for _, t := range bundle.Topics {
+		if tenant != "" && t.Tenant != tenant {
+			skipped++
+			continue
+		}
+		if _, exists := c.pubsubs.Load(t.ID); exists {
+			skipped++
+			continue
+		}
+
+		name := t.Name
+		if name != "" && !c.registerName(name, t.ID) {
+			zlog.Warn().Int64("id", t.ID).Str("name", name).Msg(logPrefix + "imported topic name already claimed; keeping the topic reachable by id only")
+			name = ""
+		}
+
+		var tokenExpiresAt time.Time
+		if t.TokenExpiresAt != 0 {
+			tokenExpiresAt = time.Unix(t.TokenExpiresAt, 0).UTC()
+		}
+
+		if t.Persist && c.kv != nil {
+			err := c.kv.SetIn(ctx, t.Tenant, monoflake.ID(t.ID).BigEndianBytes(), encodeTokenRecord(tokenRecord{
+				token:          []byte(t.Token),
+				tokenExpiresAt: tokenExpiresAt,
+				name:           name,
+				tickFrequency:  time.Duration(t.TickFrequency),
+				retentionSize:  t.RetentionSize,
+				maxSubscribers: t.MaxSubscribers,
+				retryHint:      time.Duration(t.RetryHint),
+			}))
+			if err != nil {
+				zlog.Error().Err(err).Int64("id", t.ID).Msg(logPrefix + "failed to persist imported topic; going on with the next one.")
+				c.unregisterName(name)
+				skipped++
+				continue
+			}
+		}
+
+		retentionSize := c.config().RetentionBufferSize
+		if t.RetentionSize > 0 {
+			retentionSize = t.RetentionSize
+		}
+		c.pubsubs.Store(t.ID, &pubsub{
+			id:             t.ID,
+			persist:        t.Persist,
+			tenant:         t.Tenant,
+			name:           name,
+			subscribers:    newSubscriberList(),
+			mutex:          sync.RWMutex{},
+			rates:          newRateWindow(),
+			retained:       newRetentionBuffer(retentionSize),
+			token:          []byte(t.Token),
+			tokenExpiresAt: tokenExpiresAt,
+			tickFrequency:  time.Duration(t.TickFrequency),
+			retryHint:      time.Duration(t.RetryHint),
+			maxSubscribers: t.MaxSubscribers,
+			lastActivity:   time.Now().UnixNano(),
+		})
+		c.inc(metricTopics)
+		c.inc(metricActiveTopics)
+		c.tenantInc(t.Tenant, metricTopics, 1)
+		c.tenantInc(t.Tenant, metricActiveTopics, 1)
+		imported++
+	}
+
+	zlog.Info().Int("imported", imported).Int("skipped", skipped).Msg(logPrefix + "imported topic bundle")
+	return &entity.ImportTopicsResponse{
+		Imported: imported,
+		Skipped:  skipped,
+	}, nil
+}
@@ -0,0 +1,102 @@
+package pubsub
+
+import (
+	"time"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	zlog "github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultFanoutWorkers is used when fanoutWorkers is unset in the config
+	defaultFanoutWorkers = 64
+
+	// fanoutQueueDepth is the per-worker job buffer; a full queue applies
+	// backpressure to the publish path instead of growing without bound
+	fanoutQueueDepth = 256
+)
+
+type (
+	// deliveryJob carries one event to one subscriber.
+	deliveryJob struct {
+		pubsubID   int64
+		sub        subscriber
+		ev         *entity.Event
+		enqueuedAt time.Time
+		// ack, when set, receives the delivery outcome for a synchronous
+		// publish
+		ack *ackTracker
+	}
+
+	// fanoutPool delivers events through a fixed set of workers instead of a
+	// goroutine per subscriber per message. Jobs are routed to a worker by
+	// subscriber id, so each subscriber keeps its own queue and receives
+	// events in publish order.
+	fanoutPool struct {
+		queues []chan deliveryJob
+	}
+)
+
+func (c *controller) newFanoutPool(workers int) *fanoutPool {
+	if workers <= 0 {
+		workers = defaultFanoutWorkers
+	}
+	p := &fanoutPool{
+		queues: make([]chan deliveryJob, workers),
+	}
+	for i := range p.queues {
+		p.queues[i] = make(chan deliveryJob, fanoutQueueDepth)
+		go c.runFanoutWorker(p.queues[i])
+	}
+	zlog.Info().Int("workers", workers).Msg(logPrefix + "fan-out worker pool started")
+	return p
+}
+
+// enqueue hands the job to the worker owning the subscriber's queue; it
+// blocks when that queue is full, which bounds memory under slow consumers
+func (p *fanoutPool) enqueue(job deliveryJob) {
+	p.queues[uint64(job.sub.id)%uint64(len(p.queues))] <- job
+}
+
+func (c *controller) runFanoutWorker(jobs chan deliveryJob) {
+	for job := range jobs {
+		c.deliver(job)
+	}
+}
+
+// deliver sends one event to one subscriber, applying the subscriber's
+// projection and raising slow-delivery alerts just like the previous
+// per-goroutine fan-out did
+func (c *controller) deliver(job deliveryJob) {
+	cfg := c.config()
+
+	ev := job.ev
+	if job.sub.transform != nil {
+		// projections produce a per-subscriber copy; the shared event stays
+		// untouched for everyone else
+		ev = &entity.Event{
+			ID:          job.ev.ID,
+			Type:        job.ev.Type,
+			Data:        job.sub.transform.apply(job.ev.Data),
+			PublishedAt: job.ev.PublishedAt,
+		}
+	}
+
+	start := time.Now()
+	err := publishWithTimeout(job.sub.channel, ev, cfg.MaxDurationForSubscriberToReceive)
+	elapsed := time.Since(start)
+	job.ack.observe(err == nil)
+	if !job.enqueuedAt.IsZero() {
+		// queue wait plus channel send: how long fan-out held the event
+		c.fanoutLatency.Observe(time.Since(job.enqueuedAt))
+	}
+	if err != nil {
+		zlog.Error().Err(err).Dur("timeout", cfg.MaxDurationForSubscriberToReceive).
+			Msg(logPrefix + "failed to send message to subscriber within the given timeout duration")
+		c.alertSlowSubscriber(alertKindDeliveryTimeout, job.pubsubID, job.sub.id, elapsed)
+		return
+	}
+	if cfg.SlowDeliveryThreshold > 0 && elapsed >= cfg.SlowDeliveryThreshold {
+		c.alertSlowSubscriber(alertKindSlowDelivery, job.pubsubID, job.sub.id, elapsed)
+	}
+}
@@ -0,0 +1,50 @@
+package pubsub
+
+// subscriberFilter narrows which published events a subscriber receives; a
+// nil filter matches everything
+type subscriberFilter struct {
+	types []string
+	attrs map[string]string
+}
+
+// newSubscriberFilter builds a filter from the requested event types and
+// header attribute matches; it returns nil when nothing is requested so the
+// fan-out fast path stays filter-free
+func newSubscriberFilter(types []string, attrs map[string]string) *subscriberFilter {
+	if len(types) == 0 && len(attrs) == 0 {
+		return nil
+	}
+	return &subscriberFilter{
+		types: types,
+		attrs: attrs,
+	}
+}
+
+// matches reports whether an event with the given type and headers should be
+// delivered
+func (f *subscriberFilter) matches(eventType string, headers map[string]string) bool {
+	if f == nil {
+		return true
+	}
+
+	if len(f.types) > 0 {
+		ok := false
+		for _, t := range f.types {
+			if t == eventType {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	for k, v := range f.attrs {
+		if headers[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,74 @@
+package pubsub
+
+import (
+	"sync"
+	"time"
+)
+
+// metricsHistorySize bounds the snapshot ring; at the default 5s publish
+// interval it covers roughly an hour.
+const metricsHistorySize = 720
+
+type (
+	// metricsSnapshot is one timestamped copy of every counter.
+	metricsSnapshot struct {
+		at   time.Time
+		vals map[metric]int64
+	}
+
+	// metricsHistory retains timestamped counter snapshots in a ring so
+	// GetMetrics can answer delta and window queries without client-side
+	// bookkeeping.
+	metricsHistory struct {
+		mutex   sync.Mutex
+		entries []metricsSnapshot
+		next    int
+		full    bool
+	}
+)
+
+func newMetricsHistory(size int) *metricsHistory {
+	return &metricsHistory{entries: make([]metricsSnapshot, size)}
+}
+
+// record appends a snapshot of the current counters.
+func (h *metricsHistory) record(m *metrics) {
+	vals := make(map[metric]int64, len(m.vals))
+	for k := range m.vals {
+		vals[k] = m.get(k)
+	}
+	h.mutex.Lock()
+	h.entries[h.next] = metricsSnapshot{at: time.Now().UTC(), vals: vals}
+	h.next = (h.next + 1) % len(h.entries)
+	if h.next == 0 {
+		h.full = true
+	}
+	h.mutex.Unlock()
+}
+
+// before returns the newest snapshot taken at or before t; when t predates
+// the ring it falls back to the oldest retained snapshot. The second return
+// is false until the first snapshot has been recorded.
+func (h *metricsHistory) before(t time.Time) (metricsSnapshot, bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	size := len(h.entries)
+	count, start := h.next, 0
+	if h.full {
+		count, start = size, h.next
+	}
+	var best metricsSnapshot
+	found := false
+	for i := 0; i < count; i++ {
+		e := h.entries[(start+i)%size]
+		if i == 0 {
+			// oldest entry as the fallback baseline
+			best, found = e, true
+		}
+		if e.at.After(t) {
+			break
+		}
+		best, found = e, true
+	}
+	return best, found
+}
@@ -0,0 +1,163 @@
+package pubsub
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+)
+
+type (
+	// Hook receives lifecycle notifications from the controller so external
+	// code — bridges, audit trails, mirrors — can react to what topics are
+	// doing. Notifications are dispatched off the hot path by a single
+	// goroutine; a hook that blocks only delays the other hooks, never a
+	// publish or a stream.
+	Hook interface {
+		OnTopicCreated(ev HookEvent)
+		OnPublish(ev HookEvent)
+		OnSubscribe(ev HookEvent)
+		OnUnsubscribe(ev HookEvent)
+		OnDeliveryFailure(ev HookEvent)
+	}
+
+	// HookEvent is the payload shared by every notification; not every field
+	// is set for every kind.
+	HookEvent struct {
+		Kind         string    `json:"kind"`
+		PubSubID     int64     `json:"pubsub_id"`
+		Tenant       string    `json:"tenant,omitempty"`
+		Topic        string    `json:"topic,omitempty"`
+		EventID      string    `json:"event_id,omitempty"`
+		EventType    string    `json:"event_type,omitempty"`
+		SubscriberID int64     `json:"subscriber_id,omitempty"`
+		Reason       string    `json:"reason,omitempty"`
+		At           time.Time `json:"at"`
+	}
+
+	// hooksConfig registers the built-in hooks from yaml; embedders link
+	// their own implementations through Params.Hooks instead
+	hooksConfig struct {
+		// Audit logs every lifecycle notification through the server log
+		Audit bool `yaml:"audit"`
+		// Webhooks POST each notification as a JSON body to every URL listed
+		Webhooks []string `yaml:"webhooks"`
+	}
+)
+
+const (
+	HookKindTopicCreated    = "topic_created"
+	HookKindPublish         = "publish"
+	HookKindSubscribe       = "subscribe"
+	HookKindUnsubscribe     = "unsubscribe"
+	HookKindDeliveryFailure = "delivery_failure"
+)
+
+// hookQueueSize bounds the dispatch queue; notifications past it are dropped
+// with a warning rather than back-pressuring the hot path
+const hookQueueSize = 1024
+
+// webhookTimeout bounds a single delivery attempt
+const webhookTimeout = 5 * time.Second
+
+// buildHooks combines the config-registered built-ins with the hooks the
+// embedder passed in
+func buildHooks(cfg hooksConfig, extra []Hook) []Hook {
+	hooks := make([]Hook, 0, len(extra)+len(cfg.Webhooks)+1)
+	if cfg.Audit {
+		hooks = append(hooks, &auditHook{})
+	}
+	for _, url := range cfg.Webhooks {
+		hooks = append(hooks, &webhookHook{
+			url:    url,
+			client: &http.Client{Timeout: webhookTimeout},
+		})
+	}
+	return append(hooks, extra...)
+}
+
+// notifyHook queues a lifecycle notification for dispatch; with no hooks
+// registered it is a nil-channel no-op
+func (c *controller) notifyHook(ev HookEvent) {
+	if c.hookCh == nil {
+		return
+	}
+	ev.At = time.Now().UTC()
+	select {
+	case c.hookCh <- ev:
+	default:
+		zlog.Warn().Str("kind", ev.Kind).Int64("pubsubID", ev.PubSubID).
+			Msg(logPrefix + "hook queue is full; dropping notification")
+	}
+}
+
+// runHookDispatcher fans each queued notification out to every registered
+// hook in order
+func (c *controller) runHookDispatcher() {
+	for ev := range c.hookCh {
+		for _, h := range c.hooks {
+			switch ev.Kind {
+			case HookKindTopicCreated:
+				h.OnTopicCreated(ev)
+			case HookKindPublish:
+				h.OnPublish(ev)
+			case HookKindSubscribe:
+				h.OnSubscribe(ev)
+			case HookKindUnsubscribe:
+				h.OnUnsubscribe(ev)
+			case HookKindDeliveryFailure:
+				h.OnDeliveryFailure(ev)
+			}
+		}
+	}
+}
+
+// auditHook writes every notification to the server log; the cheapest way to
+// get a lifecycle audit trail without standing up extra infrastructure
+type auditHook struct{}
+
+func (h *auditHook) log(ev HookEvent) {
+	zlog.Info().Str("kind", ev.Kind).Int64("pubsubID", ev.PubSubID).
+		Str("tenant", ev.Tenant).Str("topic", ev.Topic).
+		Str("eventID", ev.EventID).Int64("subscriberID", ev.SubscriberID).
+		Str("reason", ev.Reason).Msg(logPrefix + "audit")
+}
+
+func (h *auditHook) OnTopicCreated(ev HookEvent)    { h.log(ev) }
+func (h *auditHook) OnPublish(ev HookEvent)         { h.log(ev) }
+func (h *auditHook) OnSubscribe(ev HookEvent)       { h.log(ev) }
+func (h *auditHook) OnUnsubscribe(ev HookEvent)     { h.log(ev) }
+func (h *auditHook) OnDeliveryFailure(ev HookEvent) { h.log(ev) }
+
+// webhookHook POSTs each notification as JSON; a failed delivery is logged
+// and dropped — webhooks are best-effort observers, not a durable bus
+type webhookHook struct {
+	url    string
+	client *http.Client
+}
+
+func (h *webhookHook) deliver(ev HookEvent) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	res, err := h.client.Post(h.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		zlog.Warn().Err(err).Str("url", h.url).Str("kind", ev.Kind).
+			Msg(logPrefix + "webhook delivery failed")
+		return
+	}
+	res.Body.Close()
+	if res.StatusCode >= 300 {
+		zlog.Warn().Int("status", res.StatusCode).Str("url", h.url).Str("kind", ev.Kind).
+			Msg(logPrefix + "webhook returned a non-success status")
+	}
+}
+
+func (h *webhookHook) OnTopicCreated(ev HookEvent)    { h.deliver(ev) }
+func (h *webhookHook) OnPublish(ev HookEvent)         { h.deliver(ev) }
+func (h *webhookHook) OnSubscribe(ev HookEvent)       { h.deliver(ev) }
+func (h *webhookHook) OnUnsubscribe(ev HookEvent)     { h.deliver(ev) }
+func (h *webhookHook) OnDeliveryFailure(ev HookEvent) { h.deliver(ev) }
@@ -0,0 +1,80 @@
+package pubsub
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+type (
+	// idempotencyCache remembers recently seen Idempotency-Key headers per
+	// pubsub so retried publishes return the original event id instead of
+	// delivering the message twice
+	idempotencyCache struct {
+		ttl     time.Duration
+		mutex   sync.Mutex
+		entries map[string]idempotencyEntry
+	}
+
+	idempotencyEntry struct {
+		id int64
+		at time.Time
+	}
+)
+
+const (
+	// maxIdempotencyEntries bounds the cache; expired entries are pruned when
+	// the bound is hit
+	maxIdempotencyEntries = 10_000
+
+	// defaultIdempotencyKeyTTL applies when `idempotencyKeyTTL` is not set
+	defaultIdempotencyKeyTTL = 5 * time.Minute
+)
+
+func idempotencyTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return defaultIdempotencyKeyTTL
+	}
+	return ttl
+}
+
+func newIdempotencyCache(ttl time.Duration) *idempotencyCache {
+	return &idempotencyCache{
+		ttl:     ttl,
+		entries: make(map[string]idempotencyEntry),
+	}
+}
+
+func idempotencyKey(pubsubID int64, key string) string {
+	return strconv.FormatInt(pubsubID, 10) + "|" + key
+}
+
+// get returns the event id of an earlier publish with the same key, if it
+// hasn't expired yet
+func (c *idempotencyCache) get(pubsubID int64, key string) (int64, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.entries[idempotencyKey(pubsubID, key)]
+	if !ok || time.Since(entry.at) > c.ttl {
+		return 0, false
+	}
+	return entry.id, true
+}
+
+// set records a publish under its idempotency key
+func (c *idempotencyCache) set(pubsubID int64, key string, id int64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.entries) >= maxIdempotencyEntries {
+		now := time.Now()
+		for k, entry := range c.entries {
+			if now.Sub(entry.at) > c.ttl {
+				delete(c.entries, k)
+			}
+		}
+	}
+	c.entries[idempotencyKey(pubsubID, key)] = idempotencyEntry{
+		id: id,
+		at: time.Now(),
+	}
+}
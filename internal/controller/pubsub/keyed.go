@@ -0,0 +1,99 @@
+package pubsub
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+)
+
+// maxKeyedEntries bounds the per-topic last-value cache; when it is full the
+// stalest key is evicted to make room
+const maxKeyedEntries = 10_000
+
+type (
+	// lastValueCache keeps the newest event per client-supplied key (a
+	// compacted view of the topic), so new subscribers can ask for the
+	// current state of every key before live updates
+	lastValueCache struct {
+		mutex   sync.RWMutex
+		entries map[string]keyedEntry
+	}
+
+	keyedEntry struct {
+		event *entity.Event
+		at    time.Time
+	}
+)
+
+func newLastValueCache() *lastValueCache {
+	return &lastValueCache{
+		entries: make(map[string]keyedEntry),
+	}
+}
+
+// store replaces the cached value for the key, evicting the stalest key when
+// the cache is full
+func (c *lastValueCache) store(key string, ev *entity.Event) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= maxKeyedEntries {
+		var (
+			oldestKey string
+			oldestAt  time.Time
+		)
+		for k, entry := range c.entries {
+			if oldestKey == "" || entry.at.Before(oldestAt) {
+				oldestKey, oldestAt = k, entry.at
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+	c.entries[key] = keyedEntry{
+		event: ev,
+		at:    time.Now(),
+	}
+}
+
+// snapshot returns the newest event of every key, oldest update first, so
+// replaying them preserves cross-key ordering
+func (c *lastValueCache) snapshot() []*entity.Event {
+	c.mutex.RLock()
+	entries := make([]keyedEntry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		entries = append(entries, entry)
+	}
+	c.mutex.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].at.Before(entries[j].at) })
+	events := make([]*entity.Event, len(entries))
+	for i, entry := range entries {
+		events[i] = entry.event
+	}
+	return events
+}
+
+// storeKeyed records the event as the latest value of its key; the cache is
+// created on the first keyed publish so unkeyed topics pay nothing.
+func (p *pubsub) storeKeyed(key string, ev *entity.Event) {
+	p.mutex.Lock()
+	if p.keyed == nil {
+		p.keyed = newLastValueCache()
+	}
+	keyed := p.keyed
+	p.mutex.Unlock()
+	keyed.store(key, ev)
+}
+
+// keyedSnapshot returns the topic's compacted state, nil when no keyed event
+// was ever published.
+func (p *pubsub) keyedSnapshot() []*entity.Event {
+	p.mutex.RLock()
+	keyed := p.keyed
+	p.mutex.RUnlock()
+	if keyed == nil {
+		return nil
+	}
+	return keyed.snapshot()
+}
@@ -0,0 +1,54 @@
+package pubsub
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+)
+
+// ListPubSubs returns a summary of every live topic for the admin dashboard;
+// tenant-scoped API keys only see their own topics while the instance-wide
+// key sees everything.
+func (c *controller) ListPubSubs(ctx context.Context, req entity.ListPubSubsRequest) (*entity.ListPubSubsResponse, error) {
+	tenant, ok := c.tenantForAPIToken(req.ApiAccessToken)
+	if !ok {
+		return nil, entity.Err{
+			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
+			Message: "API access token mismatch",
+			Details: map[string]any{
+				"token": req.ApiAccessToken,
+			},
+		}
+	}
+
+	all := tenant == ""
+	pubsubs := make([]entity.PubSubSummary, 0)
+	c.pubsubs.Range(func(_, val any) bool {
+		ps, ok := val.(*pubsub)
+		if !ok {
+			return true
+		}
+		if !all && ps.tenant != tenant {
+			return true
+		}
+		pubsubs = append(pubsubs, entity.PubSubSummary{
+			ID:          ps.id,
+			Name:        ps.name,
+			Tenant:      ps.tenant,
+			Static:      ps.static,
+			Persist:     ps.persist,
+			Paused:      ps.isPaused(),
+			Profile:     ps.profile,
+			Subscribers: ps.subscribers.len(),
+		})
+		return true
+	})
+
+	sort.Slice(pubsubs, func(i, j int) bool { return pubsubs[i].ID < pubsubs[j].ID })
+
+	return &entity.ListPubSubsResponse{
+		PubSubs: pubsubs,
+	}, nil
+}
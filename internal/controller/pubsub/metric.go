@@ -1,6 +1,10 @@
 package pubsub
 
-import "sync/atomic"
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
 
 type (
 	metrics struct {
@@ -8,6 +12,28 @@ type (
 	}
 
 	metric uint8
+
+	// metricsTopicConfig controls the reserved system topic carrying live
+	// metric and alert events
+	metricsTopicConfig struct {
+		// Disabled stops publishing metric events entirely; counters keep
+		// updating and stay visible through the metrics endpoint
+		Disabled bool `yaml:"disabled"`
+		// ID moves the system topic off the default reserved id 0
+		ID int64 `yaml:"id"`
+		// SamplingInterval is how often the snapshot publisher emits; zero
+		// picks the default
+		SamplingInterval time.Duration `yaml:"samplingInterval"`
+	}
+)
+
+const (
+	// metricSchema versions the metric event payload so consumers can detect
+	// format changes
+	metricSchema = "sser.metrics.v1"
+
+	// defaultMetricsPublishInterval is used when samplingInterval is unset
+	defaultMetricsPublishInterval = 5 * time.Second
 )
 
 const (
@@ -19,18 +45,38 @@ const (
 	metricActiveSubscribers
 	metricMessageReceived
 	metricMessageSent
+	metricQuotaTopicsExceeded
+	metricQuotaSubscribersExceeded
+	metricQuotaMessageSizeExceeded
+	metricAlertSlowDelivery
+	metricAlertDeliveryTimeout
+	metricDuplicatePublishes
+	metricTopicsReaped
+	metricStaleSubscribers
+	metricStorePingFailures
+	metricScriptFilteredPublishes
 )
 
 func newMetrics() *metrics {
 	return &metrics{
 		vals: map[metric]*int64{
-			metricTopics:            ptrInt64(0),
-			metricStaticTopics:      ptrInt64(0),
-			metricActiveTopics:      ptrInt64(0),
-			metricSubscribers:       ptrInt64(0),
-			metricActiveSubscribers: ptrInt64(0),
-			metricMessageReceived:   ptrInt64(0),
-			metricMessageSent:       ptrInt64(0),
+			metricTopics:                   ptrInt64(0),
+			metricStaticTopics:             ptrInt64(0),
+			metricActiveTopics:             ptrInt64(0),
+			metricSubscribers:              ptrInt64(0),
+			metricActiveSubscribers:        ptrInt64(0),
+			metricMessageReceived:          ptrInt64(0),
+			metricMessageSent:              ptrInt64(0),
+			metricQuotaTopicsExceeded:      ptrInt64(0),
+			metricQuotaSubscribersExceeded: ptrInt64(0),
+			metricQuotaMessageSizeExceeded: ptrInt64(0),
+			metricAlertSlowDelivery:        ptrInt64(0),
+			metricAlertDeliveryTimeout:     ptrInt64(0),
+			metricDuplicatePublishes:       ptrInt64(0),
+			metricTopicsReaped:             ptrInt64(0),
+			metricStaleSubscribers:         ptrInt64(0),
+			metricStorePingFailures:        ptrInt64(0),
+			metricScriptFilteredPublishes:  ptrInt64(0),
 		},
 	}
 }
@@ -51,10 +97,52 @@ func (m metric) String() string {
 		return "message_received"
 	case metricMessageSent:
 		return "message_sent"
+	case metricQuotaTopicsExceeded:
+		return "quota_topics_exceeded"
+	case metricQuotaSubscribersExceeded:
+		return "quota_subscribers_exceeded"
+	case metricQuotaMessageSizeExceeded:
+		return "quota_message_size_exceeded"
+	case metricAlertSlowDelivery:
+		return "alert_slow_delivery"
+	case metricAlertDeliveryTimeout:
+		return "alert_delivery_timeout"
+	case metricDuplicatePublishes:
+		return "duplicate_publishes"
+	case metricTopicsReaped:
+		return "topics_reaped"
+	case metricStaleSubscribers:
+		return "stale_subscribers"
+	case metricStorePingFailures:
+		return "store_ping_failures"
+	case metricScriptFilteredPublishes:
+		return "script_filtered_publishes"
 	}
 	return ""
 }
 
+// snapshotPayload renders every counter into one schema-versioned event for
+// the periodic system-topic publisher
+func (m *metrics) snapshotPayload() []byte {
+	buf := make([]byte, 0, 512)
+	buf = append(buf, `{"schema": "`...)
+	buf = append(buf, metricSchema...)
+	buf = append(buf, `", "metrics": {`...)
+	first := true
+	for k, v := range m.vals {
+		if !first {
+			buf = append(buf, `, `...)
+		}
+		first = false
+		buf = append(buf, '"')
+		buf = append(buf, k.String()...)
+		buf = append(buf, `": `...)
+		buf = strconv.AppendInt(buf, atomic.LoadInt64(v), 10)
+	}
+	buf = append(buf, `}}`...)
+	return buf
+}
+
 func (m *metrics) inc(k metric) {
 	v := m.vals[k]
 	atomic.AddInt64(v, 1)
@@ -70,6 +158,12 @@ func (m *metrics) dec(k metric) {
 	atomic.AddInt64(v, -1)
 }
 
+// set overwrites a gauge-style metric with a freshly computed value
+func (m *metrics) set(k metric, val int64) {
+	v := m.vals[k]
+	atomic.StoreInt64(v, val)
+}
+
 func (m *metrics) get(k metric) int64 {
 	v := m.vals[k]
 	return atomic.LoadInt64(v)
@@ -0,0 +1,83 @@
+package pubsub
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// maxTopicNameLength bounds human-readable topic aliases
+const maxTopicNameLength = 128
+
+// validateTopicName rejects aliases that can't travel in URLs or the
+// pipe-delimited persistence records
+func validateTopicName(name string) error {
+	if len(name) > maxTopicNameLength {
+		return entity.Err{
+			Code:    400,
+			Message: "topic name is too long",
+			Details: map[string]any{
+				"maxLength": maxTopicNameLength,
+			},
+		}
+	}
+	if strings.ContainsAny(name, "|/ ") {
+		return entity.Err{
+			Code:    400,
+			Message: "topic name can't contain '|', '/' or spaces",
+			Details: map[string]any{
+				"name": name,
+			},
+		}
+	}
+	return nil
+}
+
+// registerName claims a unique alias for the topic; the empty name is always
+// available
+func (c *controller) registerName(name string, id int64) bool {
+	if name == "" {
+		return true
+	}
+	_, loaded := c.names.LoadOrStore(name, id)
+	return !loaded
+}
+
+func (c *controller) unregisterName(name string) {
+	if name != "" {
+		c.names.Delete(name)
+	}
+}
+
+// ResolveName maps a topic alias back to its numeric id so by-name routes can
+// reuse the id-based handlers.
+func (c *controller) ResolveName(ctx context.Context, name string) (int64, error) {
+	if name == "" {
+		return 0, entity.Err{
+			Code:    400,
+			Message: "topic name is required",
+		}
+	}
+	id, ok := c.names.Load(name)
+	if !ok {
+		return 0, entity.Err{
+			Code:    404,
+			Kind:    entity.ErrorKindPubSubNotFound,
+			Message: "pubsub not found",
+			Details: map[string]any{
+				"name": name,
+			},
+		}
+	}
+	resolved, ok := id.(int64)
+	if !ok {
+		zlog.Error().Str("name", name).Msg(logPrefix + "malformed name registry entry")
+		return 0, entity.Err{
+			Code:    500,
+			Message: "malformed name registry entry",
+		}
+	}
+	return resolved, nil
+}
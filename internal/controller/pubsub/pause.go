@@ -0,0 +1,102 @@
+package pubsub
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+)
+
+// isPaused reports whether the topic currently rejects publishes.
+func (p *pubsub) isPaused() bool {
+	return atomic.LoadInt32(&p.paused) == 1
+}
+
+// setPaused flips the paused flag and reports whether the state changed.
+func (p *pubsub) setPaused(paused bool) bool {
+	to := int32(0)
+	if paused {
+		to = 1
+	}
+	return atomic.SwapInt32(&p.paused, to) != to
+}
+
+// Pause stops a topic from accepting publishes (they are rejected with a 409)
+// while keeping subscriber streams open; useful during maintenance of
+// downstream consumers. Subscribers are told via a `paused` event.
+func (c *controller) Pause(ctx context.Context, req entity.PausePubSubRequest) error {
+	return c.setTopicPaused(req.ApiAccessToken, req.ID, true)
+}
+
+// Resume lifts a pause so the topic accepts publishes again; subscribers are
+// told via a `resumed` event.
+func (c *controller) Resume(ctx context.Context, req entity.ResumePubSubRequest) error {
+	return c.setTopicPaused(req.ApiAccessToken, req.ID, false)
+}
+
+func (c *controller) setTopicPaused(apiAccessToken string, id int64, paused bool) error {
+	tenant, ok := c.tenantForAPIToken(apiAccessToken)
+	if !ok {
+		return entity.Err{
+			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
+			Message: "API access token mismatch",
+			Details: map[string]any{
+				"token": apiAccessToken,
+			},
+		}
+	}
+
+	t, ok := c.pubsubs.Load(id)
+	if !ok {
+		return entity.Err{
+			Code:    404,
+			Kind:    entity.ErrorKindPubSubNotFound,
+			Message: "pubsub not found",
+			Details: map[string]any{
+				"id": id,
+			},
+		}
+	}
+	pubsub, ok := t.(*pubsub)
+	if !ok {
+		return entity.Err{
+			Code:    500,
+			Message: "malformed pubsub type",
+			Details: map[string]any{
+				"id": id,
+			},
+		}
+	}
+
+	if !pubsub.static && pubsub.tenant != tenant {
+		return entity.Err{
+			Code:    entity.ErrorCodeForbidden,
+			Message: "pubsub belongs to another tenant",
+			Details: map[string]any{
+				"id": id,
+			},
+		}
+	}
+
+	if !pubsub.setPaused(paused) {
+		// already in the requested state; nothing to announce
+		return nil
+	}
+
+	// the state-change event bypasses subscriber filters so every stream
+	// learns about the maintenance window
+	eventType := entity.EventTypeResumed
+	if paused {
+		eventType = entity.EventTypePaused
+	}
+	ev := &entity.Event{
+		Type: eventType,
+		Data: []byte(`{"status": "` + eventType + `"}`),
+	}
+	for _, s := range pubsub.subscribers.snapshot() {
+		c.fanout.enqueue(deliveryJob{pubsubID: id, sub: s, ev: ev})
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package pubsub
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	"github.com/mustafaturan/monoflake"
+)
+
+// presence actions published as `presence` events on topics created with the
+// presence option.
+const (
+	eventTypePresence = "presence"
+
+	presenceActionJoin  = "join"
+	presenceActionLeave = "leave"
+)
+
+// GetSubscribers lists the currently connected subscribers of a pubsub; it
+// authenticates with the topic token like Subscribe does.
+func (c *controller) GetSubscribers(ctx context.Context, req entity.GetSubscribersRequest) (*entity.GetSubscribersResponse, error) {
+	t, ok := c.pubsubs.Load(req.PubSubID)
+	if !ok {
+		return nil, entity.Err{
+			Code:    404,
+			Kind:    entity.ErrorKindPubSubNotFound,
+			Message: "pubsub not found",
+			Details: map[string]any{
+				"id": req.PubSubID,
+			},
+		}
+	}
+
+	pubsub, ok := t.(*pubsub)
+	if !ok {
+		return nil, entity.Err{
+			Code:    500,
+			Message: "malformed pubsub",
+			Details: map[string]any{
+				"id": req.PubSubID,
+			},
+		}
+	}
+
+	if !bytes.Equal(pubsub.token, req.Token) {
+		return nil, entity.Err{
+			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
+			Message: "token mismatch for the pubsub",
+			Details: map[string]any{
+				"token": string(req.Token),
+			},
+		}
+	}
+
+	snapshot := pubsub.subscribers.snapshot()
+	subscribers := make([]entity.SubscriberInfo, 0, len(snapshot))
+	for _, s := range snapshot {
+		subscribers = append(subscribers, entity.SubscriberInfo{
+			ID:          s.id,
+			ConnectedAt: s.connectedAt,
+			LastFlushAt: s.lastFlushTime(),
+		})
+	}
+
+	return &entity.GetSubscribersResponse{
+		Subscribers: subscribers,
+	}, nil
+}
+
+// emitPresence publishes a join/leave event to the topic itself so chat-style
+// clients can track who is online.
+func (c *controller) emitPresence(ps *pubsub, action string, subscriberID int64) {
+	if !ps.presence {
+		return
+	}
+	msg := fmt.Sprintf(`{"action": "%s", "subscriber_id": "%s"}`, action, monoflake.ID(subscriberID).String())
+	_, _ = c.publish(ps.id, "", eventTypePresence, nil, []byte(msg), nil)
+}
@@ -0,0 +1,58 @@
+package pubsub
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// rateWindow keeps a ring of per-second publish counters so rolling rates
+	// can be computed without an external metrics stack.
+	rateWindow struct {
+		mutex sync.Mutex
+		slots [rateWindowSize]int64
+		times [rateWindowSize]int64
+	}
+)
+
+const rateWindowSize = 60
+
+func newRateWindow() *rateWindow {
+	return &rateWindow{}
+}
+
+// incr adds n events to the slot for the current second
+func (r *rateWindow) incr(n int64) {
+	now := time.Now().Unix()
+	i := now % rateWindowSize
+
+	r.mutex.Lock()
+	if r.times[i] != now {
+		r.slots[i] = 0
+		r.times[i] = now
+	}
+	r.slots[i] += n
+	r.mutex.Unlock()
+}
+
+// sums returns event counts over the last 1s, 10s and 60s windows
+func (r *rateWindow) sums() (last1s, last10s, last60s int64) {
+	now := time.Now().Unix()
+
+	r.mutex.Lock()
+	for i := 0; i < rateWindowSize; i++ {
+		age := now - r.times[i]
+		if age < 0 || age >= rateWindowSize {
+			continue
+		}
+		last60s += r.slots[i]
+		if age < 10 {
+			last10s += r.slots[i]
+		}
+		if age < 1 {
+			last1s += r.slots[i]
+		}
+	}
+	r.mutex.Unlock()
+	return last1s, last10s, last60s
+}
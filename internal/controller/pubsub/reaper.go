@@ -0,0 +1,106 @@
+package pubsub
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/mustafaturan/monoflake"
+	zlog "github.com/rs/zerolog/log"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+)
+
+// defaultTopicReaperFrequency applies when `topicReaperFrequency` is not set.
+const defaultTopicReaperFrequency = time.Minute
+
+// defaultStaleSubscriberThreshold applies when `staleSubscriberThreshold` is
+// not set.
+const defaultStaleSubscriberThreshold = 30 * time.Second
+
+// touch records activity (a publish or a new subscription) for idle-expiry.
+func (p *pubsub) touch() {
+	atomic.StoreInt64(&p.lastActivity, time.Now().UnixNano())
+}
+
+func (p *pubsub) lastActivityTime() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&p.lastActivity))
+}
+
+// runTopicReaper periodically deletes topics past their TTL or idle-expiry so
+// ephemeral topics don't accumulate forever.
+func (c *controller) runTopicReaper() {
+	freq := c.config().TopicReaperFrequency
+	if freq <= 0 {
+		freq = defaultTopicReaperFrequency
+	}
+	ticker := time.NewTicker(freq)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.reapExpiredTopics()
+		c.updateStaleSubscribers()
+	}
+}
+
+// updateStaleSubscribers refreshes the stale-subscriber gauge: connections
+// whose last successful flush is older than the threshold are likely
+// half-dead behind NATs even though their sockets still look open.
+func (c *controller) updateStaleSubscribers() {
+	threshold := c.config().StaleSubscriberThreshold
+	if threshold <= 0 {
+		threshold = defaultStaleSubscriberThreshold
+	}
+	cutoff := time.Now().Add(-threshold)
+
+	stale := int64(0)
+	c.pubsubs.Range(func(_, val any) bool {
+		ps, ok := val.(*pubsub)
+		if !ok {
+			return true
+		}
+		for _, s := range ps.subscribers.snapshot() {
+			if t := s.lastFlushTime(); !t.IsZero() && t.Before(cutoff) {
+				stale++
+			}
+		}
+		return true
+	})
+	c.set(metricStaleSubscribers, stale)
+}
+
+// reapExpiredTopics walks the registry once, closing subscribers with a
+// terminal close event and dropping KV records for every expired topic.
+func (c *controller) reapExpiredTopics() {
+	now := time.Now().UTC()
+	c.pubsubs.Range(func(key, val any) bool {
+		ps, ok := val.(*pubsub)
+		if !ok || ps.static {
+			return true
+		}
+
+		expired := !ps.expiresAt.IsZero() && now.After(ps.expiresAt)
+		idle := ps.idleTTL > 0 && now.Sub(ps.lastActivityTime()) > ps.idleTTL
+		if !expired && !idle {
+			return true
+		}
+
+		if ps.persist && c.kv != nil {
+			err := c.kv.DeleteIn(context.Background(), ps.tenant, monoflake.ID(ps.id).BigEndianBytes())
+			if err != nil {
+				zlog.Error().Err(err).Int64("id", ps.id).Msg(logPrefix + "failed to delete the expired pubsub from storage; retrying next sweep")
+				return true
+			}
+		}
+
+		c.closeSubscribers(ps, entity.CloseReasonExpired)
+		c.pubsubs.Delete(ps.id)
+		c.unregisterName(ps.name)
+
+		c.inc(metricTopicsReaped)
+		c.dec(metricActiveTopics)
+		c.tenantInc(ps.tenant, metricActiveTopics, -1)
+
+		zlog.Info().Int64("id", ps.id).Bool("idle", idle).Msg(logPrefix + "reaped expired topic")
+		return true
+	})
+}
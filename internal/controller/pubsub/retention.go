@@ -0,0 +1,125 @@
+package pubsub
+
+import (
+	"sync"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+)
+
+type (
+	// retentionBuffer keeps the most recent events of a pubsub in a ring so
+	// long-poll clients can catch up with a cursor; a nil buffer retains
+	// nothing
+	retentionBuffer struct {
+		mutex  sync.Mutex
+		size   int
+		seq    int64
+		events []retainedEvent
+	}
+
+	retainedEvent struct {
+		seq   int64
+		event *entity.Event
+	}
+)
+
+// newRetentionBuffer returns nil when the size is not positive so retention
+// stays disabled without extra branching at the call sites
+func newRetentionBuffer(size int) *retentionBuffer {
+	if size <= 0 {
+		return nil
+	}
+	return &retentionBuffer{
+		size:   size,
+		events: make([]retainedEvent, 0, size),
+	}
+}
+
+// capacity reports the ring size; a nil (disabled) buffer has capacity zero
+func (b *retentionBuffer) capacity() int {
+	if b == nil {
+		return 0
+	}
+	return b.size
+}
+
+// lastEventID returns the id of the newest retained event; it seeds the
+// cursor inside freshly issued resumption tokens
+func (b *retentionBuffer) lastEventID() string {
+	if b == nil {
+		return ""
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if len(b.events) == 0 {
+		return ""
+	}
+	return b.events[len(b.events)-1].event.ID
+}
+
+// add retains an event, evicting the oldest one when the ring is full
+func (b *retentionBuffer) add(ev *entity.Event) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.seq++
+	if len(b.events) >= b.size {
+		copy(b.events, b.events[1:])
+		b.events = b.events[:len(b.events)-1]
+	}
+	b.events = append(b.events, retainedEvent{seq: b.seq, event: ev})
+}
+
+// afterEventID returns the retained events published after the event with
+// the given SSE id; when the id is no longer (or never was) in the ring,
+// nothing can be replayed
+func (b *retentionBuffer) afterEventID(id string, max int) []*entity.Event {
+	if b == nil || id == "" {
+		return nil
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	out := make([]*entity.Event, 0)
+	found := false
+	for _, r := range b.events {
+		if found {
+			out = append(out, r.event)
+			if len(out) >= max {
+				break
+			}
+			continue
+		}
+		if r.event.ID == id {
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return out
+}
+
+// after returns up to max events published after the cursor along with the
+// new cursor to resume from
+func (b *retentionBuffer) after(cursor int64, max int) ([]*entity.Event, int64) {
+	if b == nil {
+		return nil, cursor
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	out := make([]*entity.Event, 0)
+	next := cursor
+	for _, r := range b.events {
+		if r.seq <= cursor {
+			continue
+		}
+		out = append(out, r.event)
+		next = r.seq
+		if len(out) >= max {
+			break
+		}
+	}
+	return out, next
+}
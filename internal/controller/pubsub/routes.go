@@ -0,0 +1,38 @@
+package pubsub
+
+import (
+	zlog "github.com/rs/zerolog/log"
+)
+
+// topicRoute is one compiled forwarding rule: events published to the source
+// topic that pass the filter are mirrored into the target topic.
+type topicRoute struct {
+	to     int64
+	filter *subscriberFilter
+}
+
+// buildRoutes compiles the configured forwarding rules, keyed by source topic
+// for the publish hot path; self-routes are dropped since they would only
+// duplicate deliveries.
+func buildRoutes(cfgs []RouteConfig) map[int64][]topicRoute {
+	routes := make(map[int64][]topicRoute, len(cfgs))
+	for _, r := range cfgs {
+		if r.From == r.To {
+			zlog.Warn().Int64("from", r.From).Msg(logPrefix + "route forwards a topic to itself; skipping")
+			continue
+		}
+		routes[r.From] = append(routes[r.From], topicRoute{
+			to:     r.To,
+			filter: newSubscriberFilter(r.Types, r.Attrs),
+		})
+	}
+	return routes
+}
+
+// routesFrom returns the forwarding rules for a source topic; routes are
+// rebuilt on config reload, so reads go through the config lock.
+func (c *controller) routesFrom(id int64) []topicRoute {
+	c.cfgMutex.RLock()
+	defer c.cfgMutex.RUnlock()
+	return c.routes[id]
+}
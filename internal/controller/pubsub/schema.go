@@ -0,0 +1,334 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	"github.com/mustafaturan/monoflake"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// schemaBucket is the KV bucket holding per-topic JSON schemas, keyed by the
+// topic id; the underscore keeps it out of the tenant bucket namespace
+const schemaBucket = "_schemas"
+
+type (
+	// eventSchema is a compiled per-topic JSON Schema; publishes are
+	// validated against it and rejected with a 422 when they don't conform.
+	// Only the pragmatic keyword subset below is enforced — type, required,
+	// properties, items, enum, minLength/maxLength, minimum/maximum —
+	// unknown keywords are ignored so schemas written for full validators
+	// still load.
+	eventSchema struct {
+		raw      []byte
+		compiled *schemaNode
+	}
+
+	schemaNode struct {
+		Type       string                 `json:"type"`
+		Required   []string               `json:"required"`
+		Properties map[string]*schemaNode `json:"properties"`
+		Items      *schemaNode            `json:"items"`
+		Enum       []any                  `json:"enum"`
+		MinLength  *int                   `json:"minLength"`
+		MaxLength  *int                   `json:"maxLength"`
+		Minimum    *float64               `json:"minimum"`
+		Maximum    *float64               `json:"maximum"`
+	}
+)
+
+func compileSchema(raw []byte) (*eventSchema, error) {
+	var node schemaNode
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return nil, fmt.Errorf("schema is not valid JSON: %w", err)
+	}
+	return &eventSchema{raw: raw, compiled: &node}, nil
+}
+
+// validate checks the payload against the schema and returns the list of
+// violations; an empty list means the payload conforms.
+func (s *eventSchema) validate(payload []byte) []string {
+	var doc any
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return []string{"payload is not valid JSON"}
+	}
+	var violations []string
+	s.compiled.check("$", doc, &violations)
+	return violations
+}
+
+func (n *schemaNode) check(path string, doc any, violations *[]string) {
+	if n == nil {
+		return
+	}
+
+	if n.Type != "" && !matchesType(n.Type, doc) {
+		*violations = append(*violations, path+": expected type "+n.Type)
+		return
+	}
+
+	if len(n.Enum) > 0 {
+		found := false
+		for _, allowed := range n.Enum {
+			if reflect.DeepEqual(doc, allowed) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			*violations = append(*violations, path+": value is not one of the allowed enum values")
+		}
+	}
+
+	switch v := doc.(type) {
+	case string:
+		if n.MinLength != nil && len(v) < *n.MinLength {
+			*violations = append(*violations, path+": shorter than minLength "+strconv.Itoa(*n.MinLength))
+		}
+		if n.MaxLength != nil && len(v) > *n.MaxLength {
+			*violations = append(*violations, path+": longer than maxLength "+strconv.Itoa(*n.MaxLength))
+		}
+	case float64:
+		if n.Minimum != nil && v < *n.Minimum {
+			*violations = append(*violations, path+": below minimum "+strconv.FormatFloat(*n.Minimum, 'f', -1, 64))
+		}
+		if n.Maximum != nil && v > *n.Maximum {
+			*violations = append(*violations, path+": above maximum "+strconv.FormatFloat(*n.Maximum, 'f', -1, 64))
+		}
+	case map[string]any:
+		for _, field := range n.Required {
+			if _, ok := v[field]; !ok {
+				*violations = append(*violations, path+": missing required property "+field)
+			}
+		}
+		for name, sub := range n.Properties {
+			if val, ok := v[name]; ok {
+				sub.check(path+"."+name, val, violations)
+			}
+		}
+	case []any:
+		if n.Items != nil {
+			for i, item := range v {
+				n.Items.check(path+"["+strconv.Itoa(i)+"]", item, violations)
+			}
+		}
+	}
+}
+
+func matchesType(typ string, doc any) bool {
+	switch typ {
+	case "object":
+		_, ok := doc.(map[string]any)
+		return ok
+	case "array":
+		_, ok := doc.([]any)
+		return ok
+	case "string":
+		_, ok := doc.(string)
+		return ok
+	case "boolean":
+		_, ok := doc.(bool)
+		return ok
+	case "number":
+		_, ok := doc.(float64)
+		return ok
+	case "integer":
+		f, ok := doc.(float64)
+		return ok && f == float64(int64(f))
+	case "null":
+		return doc == nil
+	default:
+		// unknown type keywords don't fail the payload
+		return true
+	}
+}
+
+// eventSchema returns the schema currently attached to the topic, nil when
+// validation is off.
+func (p *pubsub) eventSchema() *eventSchema {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.schema
+}
+
+func (p *pubsub) setEventSchema(s *eventSchema) {
+	p.mutex.Lock()
+	p.schema = s
+	p.mutex.Unlock()
+}
+
+// SetSchema attaches a JSON Schema to a topic; subsequent publishes are
+// validated against it. The schema survives restarts when a store is
+// configured.
+func (c *controller) SetSchema(ctx context.Context, req entity.SetSchemaRequest) error {
+	pubsub, err := c.topicForSchema(req.ApiAccessToken, req.ID)
+	if err != nil {
+		return err
+	}
+
+	schema, err := compileSchema(req.Schema)
+	if err != nil {
+		return entity.Err{
+			Code:    400,
+			Kind:    entity.ErrorKindValidationFailed,
+			Message: "schema document is malformed",
+			Details: map[string]any{
+				"err": err.Error(),
+			},
+		}
+	}
+
+	if c.kv != nil {
+		err := c.kv.SetIn(ctx, schemaBucket, monoflake.ID(req.ID).BigEndianBytes(), req.Schema)
+		if err != nil {
+			return entity.Err{
+				Code:    500,
+				Kind:    entity.ErrorKindStorageFailure,
+				Message: "Couldn't persist schema to store",
+				Details: map[string]any{
+					"err": err.Error(),
+				},
+			}
+		}
+	}
+
+	pubsub.setEventSchema(schema)
+	return nil
+}
+
+// GetSchema returns the schema attached to a topic.
+func (c *controller) GetSchema(ctx context.Context, req entity.GetSchemaRequest) (*entity.GetSchemaResponse, error) {
+	pubsub, err := c.topicForSchema(req.ApiAccessToken, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := pubsub.eventSchema()
+	if schema == nil {
+		return nil, entity.Err{
+			Code:    404,
+			Kind:    entity.ErrorKindNotFound,
+			Message: "no schema is attached to the pubsub",
+			Details: map[string]any{
+				"id": req.ID,
+			},
+		}
+	}
+
+	return &entity.GetSchemaResponse{Schema: schema.raw}, nil
+}
+
+// DeleteSchema detaches the schema from a topic so publishes are no longer
+// validated.
+func (c *controller) DeleteSchema(ctx context.Context, req entity.DeleteSchemaRequest) error {
+	pubsub, err := c.topicForSchema(req.ApiAccessToken, req.ID)
+	if err != nil {
+		return err
+	}
+
+	if c.kv != nil {
+		if err := c.kv.DeleteIn(ctx, schemaBucket, monoflake.ID(req.ID).BigEndianBytes()); err != nil {
+			return entity.Err{
+				Code:    500,
+				Kind:    entity.ErrorKindStorageFailure,
+				Message: "Couldn't delete schema from store",
+				Details: map[string]any{
+					"err": err.Error(),
+				},
+			}
+		}
+	}
+
+	pubsub.setEventSchema(nil)
+	return nil
+}
+
+// topicForSchema resolves the target topic of a schema or script management
+// call with the usual token and tenant checks.
+func (c *controller) topicForSchema(apiAccessToken string, id int64) (*pubsub, error) {
+	tenant, ok := c.tenantForAPIToken(apiAccessToken)
+	if !ok {
+		return nil, entity.Err{
+			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
+			Message: "API access token mismatch",
+			Details: map[string]any{
+				"token": apiAccessToken,
+			},
+		}
+	}
+
+	t, ok := c.pubsubs.Load(id)
+	if !ok {
+		return nil, entity.Err{
+			Code:    404,
+			Kind:    entity.ErrorKindPubSubNotFound,
+			Message: "pubsub not found",
+			Details: map[string]any{
+				"id": id,
+			},
+		}
+	}
+	ps, ok := t.(*pubsub)
+	if !ok {
+		return nil, entity.Err{
+			Code:    500,
+			Message: "malformed pubsub type",
+			Details: map[string]any{
+				"id": id,
+			},
+		}
+	}
+	if !ps.static && ps.tenant != tenant {
+		return nil, entity.Err{
+			Code:    entity.ErrorCodeForbidden,
+			Message: "pubsub belongs to another tenant",
+			Details: map[string]any{
+				"id": id,
+			},
+		}
+	}
+	return ps, nil
+}
+
+// loadSchemas re-attaches persisted schemas to their topics at startup;
+// schemas for topics that no longer exist are left in place until the topic
+// is deleted through the API.
+func (c *controller) loadSchemas() {
+	if c.kv == nil {
+		return
+	}
+
+	ctx := context.Background()
+	keys, err := c.kv.ListKeysIn(ctx, schemaBucket)
+	if err != nil {
+		zlog.Error().Err(err).Msg(logPrefix + "failed to list persisted schemas; topics start without validation")
+		return
+	}
+	for _, k := range keys {
+		id := monoflake.IDFromBigEndianBytes(k).Int64()
+		t, ok := c.pubsubs.Load(id)
+		if !ok {
+			continue
+		}
+		ps, ok := t.(*pubsub)
+		if !ok {
+			continue
+		}
+		raw, err := c.kv.GetIn(ctx, schemaBucket, k)
+		if err != nil {
+			zlog.Error().Err(err).Int64("id", id).Msg(logPrefix + "failed to load persisted schema; going on with the next one.")
+			continue
+		}
+		schema, err := compileSchema(raw)
+		if err != nil {
+			zlog.Error().Err(err).Int64("id", id).Msg(logPrefix + "persisted schema doesn't compile; topic starts without validation")
+			continue
+		}
+		ps.setEventSchema(schema)
+	}
+}
@@ -0,0 +1,282 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	"github.com/mustafaturan/monoflake"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// scriptBucket is the KV bucket holding per-topic publish scripts, keyed by
+// the topic id; the underscore keeps it out of the tenant bucket namespace
+const scriptBucket = "_scripts"
+
+// maxScriptSteps caps a script's pipeline length. Together with the step
+// language having no loops this makes the CPU cost of a script structural —
+// at most one payload walk per step — instead of something a runtime has to
+// meter. Memory is bounded downstream: the transformed payload still goes
+// through the max message size check.
+const maxScriptSteps = 32
+
+const (
+	scriptOpDropIf  = "drop_if"
+	scriptOpKeepIf  = "keep_if"
+	scriptOpProject = "project"
+	scriptOpSet     = "set"
+)
+
+type (
+	// publishScript is a compiled per-topic pipeline that runs on every
+	// publish, after schema validation, so light routing logic — drop the
+	// noise, trim the payload, stamp a field — lives on the topic instead of
+	// in a separate consumer. The step language is deliberately declarative
+	// rather than an embedded Lua/WASM runtime: with no loops, a hard step
+	// cap and the message size limit applied to the output, a script can
+	// never wedge the publish path.
+	publishScript struct {
+		raw   []byte
+		steps []scriptStep
+	}
+
+	// scriptStep is one pipeline stage; which fields apply depends on Op:
+	//
+	//	drop_if / keep_if — Path selects a value (same ".a.b[0]" expressions
+	//	    the subscribe-side transform uses) and Equals is compared to it;
+	//	    drop_if stops the publish on a match, keep_if on a mismatch
+	//	project — Path replaces the payload with the selected value
+	//	set — Key/Value set a top-level field on the payload object
+	scriptStep struct {
+		Op     string          `json:"op"`
+		Path   string          `json:"path,omitempty"`
+		Equals any             `json:"equals,omitempty"`
+		Key    string          `json:"key,omitempty"`
+		Value  json.RawMessage `json:"value,omitempty"`
+
+		selector *transform
+	}
+)
+
+func compileScript(raw []byte) (*publishScript, error) {
+	var doc struct {
+		Steps []scriptStep `json:"steps"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("script is not valid JSON: %w", err)
+	}
+	if len(doc.Steps) == 0 {
+		return nil, fmt.Errorf("script has no steps")
+	}
+	if len(doc.Steps) > maxScriptSteps {
+		return nil, fmt.Errorf("script exceeds the step limit of %d", maxScriptSteps)
+	}
+
+	for i := range doc.Steps {
+		step := &doc.Steps[i]
+		switch step.Op {
+		case scriptOpDropIf, scriptOpKeepIf, scriptOpProject:
+			if step.Path == "" {
+				return nil, fmt.Errorf("step %d: %s requires a path", i, step.Op)
+			}
+			selector, err := parseTransform(step.Path)
+			if err != nil {
+				return nil, fmt.Errorf("step %d: %w", i, err)
+			}
+			step.selector = selector
+		case scriptOpSet:
+			if step.Key == "" {
+				return nil, fmt.Errorf("step %d: set requires a key", i)
+			}
+			if !json.Valid(step.Value) {
+				return nil, fmt.Errorf("step %d: set value is not valid JSON", i)
+			}
+		default:
+			return nil, fmt.Errorf("step %d: unknown op %q", i, step.Op)
+		}
+	}
+
+	return &publishScript{raw: raw, steps: doc.Steps}, nil
+}
+
+// run pipes the payload through the steps in order and reports whether a
+// drop_if/keep_if step stopped the publish; a dropped event is not an error.
+func (s *publishScript) run(payload []byte) (out []byte, dropped bool, err error) {
+	for i, step := range s.steps {
+		switch step.Op {
+		case scriptOpDropIf:
+			if selectedEquals(step.selector, payload, step.Equals) {
+				return nil, true, nil
+			}
+		case scriptOpKeepIf:
+			if !selectedEquals(step.selector, payload, step.Equals) {
+				return nil, true, nil
+			}
+		case scriptOpProject:
+			payload = step.selector.apply(payload)
+		case scriptOpSet:
+			var obj map[string]any
+			if err := json.Unmarshal(payload, &obj); err != nil || obj == nil {
+				return nil, false, fmt.Errorf("step %d: set requires an object payload", i)
+			}
+			var val any
+			_ = json.Unmarshal(step.Value, &val)
+			obj[step.Key] = val
+			payload, err = json.Marshal(obj)
+			if err != nil {
+				return nil, false, fmt.Errorf("step %d: %w", i, err)
+			}
+		}
+	}
+	return payload, false, nil
+}
+
+// selectedEquals projects the payload through the step's selector and
+// compares the result to the expected value; payloads where the path is
+// absent come back as JSON null, matching an explicit `"equals": null`.
+func selectedEquals(selector *transform, payload []byte, expected any) bool {
+	var got any
+	if err := json.Unmarshal(selector.apply(payload), &got); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(got, expected)
+}
+
+// publishScript returns the script currently attached to the topic, nil when
+// no script runs.
+func (p *pubsub) publishScript() *publishScript {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.script
+}
+
+func (p *pubsub) setPublishScript(s *publishScript) {
+	p.mutex.Lock()
+	p.script = s
+	p.mutex.Unlock()
+}
+
+// SetScript attaches a publish script to a topic; subsequent publishes run
+// through it. The script survives restarts when a store is configured.
+func (c *controller) SetScript(ctx context.Context, req entity.SetScriptRequest) error {
+	pubsub, err := c.topicForSchema(req.ApiAccessToken, req.ID)
+	if err != nil {
+		return err
+	}
+
+	script, err := compileScript(req.Script)
+	if err != nil {
+		return entity.Err{
+			Code:    400,
+			Kind:    entity.ErrorKindValidationFailed,
+			Message: "script document is malformed",
+			Details: map[string]any{
+				"err": err.Error(),
+			},
+		}
+	}
+
+	if c.kv != nil {
+		err := c.kv.SetIn(ctx, scriptBucket, monoflake.ID(req.ID).BigEndianBytes(), req.Script)
+		if err != nil {
+			return entity.Err{
+				Code:    500,
+				Kind:    entity.ErrorKindStorageFailure,
+				Message: "Couldn't persist script to store",
+				Details: map[string]any{
+					"err": err.Error(),
+				},
+			}
+		}
+	}
+
+	pubsub.setPublishScript(script)
+	return nil
+}
+
+// GetScript returns the script attached to a topic.
+func (c *controller) GetScript(ctx context.Context, req entity.GetScriptRequest) (*entity.GetScriptResponse, error) {
+	pubsub, err := c.topicForSchema(req.ApiAccessToken, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	script := pubsub.publishScript()
+	if script == nil {
+		return nil, entity.Err{
+			Code:    404,
+			Kind:    entity.ErrorKindNotFound,
+			Message: "no script is attached to the pubsub",
+			Details: map[string]any{
+				"id": req.ID,
+			},
+		}
+	}
+
+	return &entity.GetScriptResponse{Script: script.raw}, nil
+}
+
+// DeleteScript detaches the script from a topic so publishes pass through
+// unmodified again.
+func (c *controller) DeleteScript(ctx context.Context, req entity.DeleteScriptRequest) error {
+	pubsub, err := c.topicForSchema(req.ApiAccessToken, req.ID)
+	if err != nil {
+		return err
+	}
+
+	if c.kv != nil {
+		if err := c.kv.DeleteIn(ctx, scriptBucket, monoflake.ID(req.ID).BigEndianBytes()); err != nil {
+			return entity.Err{
+				Code:    500,
+				Kind:    entity.ErrorKindStorageFailure,
+				Message: "Couldn't delete script from store",
+				Details: map[string]any{
+					"err": err.Error(),
+				},
+			}
+		}
+	}
+
+	pubsub.setPublishScript(nil)
+	return nil
+}
+
+// loadScripts re-attaches persisted scripts to their topics at startup;
+// scripts for topics that no longer exist are left in place until the topic
+// is deleted through the API.
+func (c *controller) loadScripts() {
+	if c.kv == nil {
+		return
+	}
+
+	ctx := context.Background()
+	keys, err := c.kv.ListKeysIn(ctx, scriptBucket)
+	if err != nil {
+		zlog.Error().Err(err).Msg(logPrefix + "failed to list persisted scripts; topics start without them")
+		return
+	}
+	for _, k := range keys {
+		id := monoflake.IDFromBigEndianBytes(k).Int64()
+		t, ok := c.pubsubs.Load(id)
+		if !ok {
+			continue
+		}
+		ps, ok := t.(*pubsub)
+		if !ok {
+			continue
+		}
+		raw, err := c.kv.GetIn(ctx, scriptBucket, k)
+		if err != nil {
+			zlog.Error().Err(err).Int64("id", id).Msg(logPrefix + "failed to load persisted script; going on with the next one.")
+			continue
+		}
+		script, err := compileScript(raw)
+		if err != nil {
+			zlog.Error().Err(err).Int64("id", id).Msg(logPrefix + "persisted script doesn't compile; topic starts without it")
+			continue
+		}
+		ps.setPublishScript(script)
+	}
+}
@@ -0,0 +1,222 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	"github.com/mustafaturan/monoflake"
+	zlog "github.com/rs/zerolog/log"
+)
+
+type (
+	// staticPubSubRecord is the KV representation of a runtime-managed static
+	// pubsub.
+	staticPubSubRecord struct {
+		ID    int64  `json:"id"`
+		Name  string `json:"name"`
+		Token string `json:"token"`
+	}
+)
+
+// staticBucket partitions runtime-managed static pubsubs from tenant records
+const staticBucket = "_static"
+
+// UpsertStaticPubSub creates or updates a static pubsub at runtime and
+// persists it so a restart isn't required to manage static topics
+func (c *controller) UpsertStaticPubSub(ctx context.Context, req entity.UpsertStaticPubSubRequest) error {
+	if req.ApiAccessToken != c.config().ApiAccessToken {
+		return entity.Err{
+			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
+			Message: "API access token mismatch",
+			Details: map[string]any{
+				"token": req.ApiAccessToken,
+			},
+		}
+	}
+
+	if req.ID == 0 {
+		return entity.Err{
+			Code:    400,
+			Message: "id for static pubsub must be >= 1",
+		}
+	}
+	if len(req.Token) < 1 {
+		return entity.Err{
+			Code:    400,
+			Message: "token for static pubsub must be >= 1 chars",
+		}
+	}
+
+	if t, ok := c.pubsubs.Load(req.ID); ok {
+		existing, ok := t.(*pubsub)
+		if !ok || !existing.static {
+			return entity.Err{
+				Code:    entity.ErrorCodeConflict,
+				Message: "a dynamic pubsub already exists with the id",
+				Details: map[string]any{
+					"id": req.ID,
+				},
+			}
+		}
+		existing.mutex.Lock()
+		oldName := existing.name
+		existing.token = []byte(req.Token)
+		existing.name = req.Name
+		existing.mutex.Unlock()
+		if oldName != req.Name {
+			c.unregisterName(oldName)
+			if !c.registerName(req.Name, req.ID) {
+				zlog.Warn().Int64("id", req.ID).Str("name", req.Name).Msg(logPrefix + "static topic name already claimed; keeping it reachable by id only")
+			}
+		}
+	} else {
+		c.pubsubs.Store(req.ID, &pubsub{
+			id:          req.ID,
+			static:      true,
+			name:        req.Name,
+			subscribers: newSubscriberList(),
+			mutex:       sync.RWMutex{},
+			rates:       newRateWindow(),
+			retained:    newRetentionBuffer(c.config().RetentionBufferSize),
+			token:       []byte(req.Token),
+		})
+		if !c.registerName(req.Name, req.ID) {
+			zlog.Warn().Int64("id", req.ID).Str("name", req.Name).Msg(logPrefix + "static topic name already claimed; keeping it reachable by id only")
+		}
+		c.inc(metricTopics)
+		c.inc(metricActiveTopics)
+		c.inc(metricStaticTopics)
+	}
+
+	if c.kv != nil {
+		record, _ := json.Marshal(staticPubSubRecord{
+			ID:    req.ID,
+			Name:  req.Name,
+			Token: req.Token,
+		})
+		err := c.kv.SetIn(ctx, staticBucket, monoflake.ID(req.ID).BigEndianBytes(), record)
+		if err != nil {
+			return entity.Err{
+				Code:    500,
+				Message: "Couldn't persist the static pubsub to store",
+				Details: map[string]any{
+					"err": err.Error(),
+				},
+			}
+		}
+	}
+
+	return nil
+}
+
+// DeleteStaticPubSub removes a runtime-managed static pubsub; the reserved
+// metrics pubsub (id 0) can't be removed
+func (c *controller) DeleteStaticPubSub(ctx context.Context, req entity.DeleteStaticPubSubRequest) error {
+	if req.ApiAccessToken != c.config().ApiAccessToken {
+		return entity.Err{
+			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
+			Message: "API access token mismatch",
+			Details: map[string]any{
+				"token": req.ApiAccessToken,
+			},
+		}
+	}
+
+	if req.ID == 0 {
+		return entity.Err{
+			Code:    400,
+			Message: "the reserved metrics pubsub can't be deleted",
+		}
+	}
+
+	t, ok := c.pubsubs.Load(req.ID)
+	if !ok {
+		return nil
+	}
+	ps, ok := t.(*pubsub)
+	if !ok || !ps.static {
+		return entity.Err{
+			Code:    400,
+			Message: "pubsub is not static",
+			Details: map[string]any{
+				"id": req.ID,
+			},
+		}
+	}
+
+	if c.kv != nil {
+		err := c.kv.DeleteIn(ctx, staticBucket, monoflake.ID(req.ID).BigEndianBytes())
+		if err != nil {
+			return entity.Err{
+				Code:    500,
+				Message: "Couldn't delete the static pubsub from storage",
+				Details: map[string]any{
+					"id": req.ID,
+				},
+			}
+		}
+	}
+
+	defer c.dec(metricActiveTopics)
+	defer c.dec(metricStaticTopics)
+
+	c.closeSubscribers(ps, entity.CloseReasonDeleted)
+	c.pubsubs.Delete(req.ID)
+	c.unregisterName(ps.name)
+	return nil
+}
+
+// registerPersistedStaticPubSubs loads runtime-managed static pubsubs back
+// from the KV store on startup
+func (c *controller) registerPersistedStaticPubSubs() error {
+	if c.kv == nil {
+		return nil
+	}
+
+	ctx := context.Background()
+	keys, err := c.kv.ListKeysIn(ctx, staticBucket)
+	if err != nil {
+		return err
+	}
+
+	cnt := int64(0)
+	for _, k := range keys {
+		val, err := c.kv.GetIn(ctx, staticBucket, k)
+		if err != nil {
+			zlog.Error().Err(err).Msg(logPrefix + "failed to load static pubsub from storage; going on with the next one.")
+			continue
+		}
+		var record staticPubSubRecord
+		if err := json.Unmarshal(val, &record); err != nil {
+			zlog.Error().Err(err).Msg(logPrefix + "malformed static pubsub record in storage; going on with the next one.")
+			continue
+		}
+		if _, ok := c.pubsubs.Load(record.ID); ok {
+			// yaml-configured statics win over persisted ones
+			continue
+		}
+		c.pubsubs.Store(record.ID, &pubsub{
+			id:          record.ID,
+			static:      true,
+			name:        record.Name,
+			subscribers: newSubscriberList(),
+			mutex:       sync.RWMutex{},
+			rates:       newRateWindow(),
+			retained:    newRetentionBuffer(c.config().RetentionBufferSize),
+			token:       []byte(record.Token),
+		})
+		if !c.registerName(record.Name, record.ID) {
+			zlog.Warn().Int64("id", record.ID).Str("name", record.Name).Msg(logPrefix + "static topic name already claimed; keeping it reachable by id only")
+		}
+		cnt++
+	}
+
+	c.incBy(metricTopics, cnt)
+	c.incBy(metricActiveTopics, cnt)
+	c.incBy(metricStaticTopics, cnt)
+	return nil
+}
@@ -0,0 +1,117 @@
+package pubsub
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// markFlush records a successful write+flush to the subscriber's socket; the
+// transport calls it so liveness is visible to operators.
+func (s subscriber) markFlush() {
+	if s.lastFlush != nil {
+		atomic.StoreInt64(s.lastFlush, time.Now().UnixNano())
+	}
+}
+
+// lastFlushTime is when the subscriber's socket last accepted a flush; a
+// connection that stops flushing while staying open is likely half-dead
+// behind a NAT.
+func (s subscriber) lastFlushTime() time.Time {
+	if s.lastFlush == nil {
+		return time.Time{}
+	}
+	return time.Unix(0, atomic.LoadInt64(s.lastFlush))
+}
+
+// subscriberList is a copy-on-write subscriber registry. Writers serialize on
+// an internal mutex and publish a fresh slice, so the publish fan-out path
+// snapshots the membership with a single atomic load instead of contending on
+// a read lock against joins and leaves on busy topics.
+type subscriberList struct {
+	mutex sync.Mutex
+	list  atomic.Pointer[[]subscriber]
+}
+
+func newSubscriberList() *subscriberList {
+	l := &subscriberList{}
+	l.list.Store(&[]subscriber{})
+	return l
+}
+
+// snapshot returns the current membership; callers must not mutate it.
+func (l *subscriberList) snapshot() []subscriber {
+	return *l.list.Load()
+}
+
+func (l *subscriberList) len() int {
+	return len(*l.list.Load())
+}
+
+// add appends s unless the quota (0 means unlimited) is already reached; it
+// reports whether the subscriber was admitted.
+func (l *subscriberList) add(s subscriber, max int) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	current := *l.list.Load()
+	if max > 0 && len(current) >= max {
+		return false
+	}
+	next := make([]subscriber, 0, len(current)+1)
+	next = append(next, current...)
+	next = append(next, s)
+	l.list.Store(&next)
+	return true
+}
+
+// remove drops the subscriber with the given id, reporting whether it was
+// present.
+func (l *subscriberList) remove(id int64) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	current := *l.list.Load()
+	for i := range current {
+		if current[i].id == id {
+			next := make([]subscriber, 0, len(current)-1)
+			next = append(next, current[:i]...)
+			next = append(next, current[i+1:]...)
+			l.list.Store(&next)
+			return true
+		}
+	}
+	return false
+}
+
+// take removes and returns the subscriber with the given id, so the caller
+// can deliver a terminal event to a stream it is about to supersede.
+func (l *subscriberList) take(id int64) (subscriber, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	current := *l.list.Load()
+	for i := range current {
+		if current[i].id == id {
+			s := current[i]
+			next := make([]subscriber, 0, len(current)-1)
+			next = append(next, current[:i]...)
+			next = append(next, current[i+1:]...)
+			l.list.Store(&next)
+			return s, true
+		}
+	}
+	return subscriber{}, false
+}
+
+// drain atomically empties the list and returns the subscribers that were
+// present, so the caller can deliver terminal events and close their channels
+// exactly once.
+func (l *subscriberList) drain() []subscriber {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	current := *l.list.Load()
+	l.list.Store(&[]subscriber{})
+	return current
+}
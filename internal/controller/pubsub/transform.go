@@ -0,0 +1,110 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type (
+	// transform is a jq-like projection applied to event payloads before
+	// delivery, so bandwidth-constrained clients only receive the fields they
+	// asked for
+	transform struct {
+		steps []transformStep
+	}
+
+	transformStep struct {
+		key     string
+		index   int
+		isIndex bool
+	}
+)
+
+// parseTransform compiles a projection expression such as
+// ".data.items[0].name" into its steps; the empty expression yields a nil
+// transform meaning no projection
+func parseTransform(expr string) (*transform, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(expr, ".") {
+		return nil, fmt.Errorf("transform expression must start with '.' (got: %s)", expr)
+	}
+	if expr == "." {
+		return &transform{}, nil
+	}
+
+	steps := make([]transformStep, 0)
+	for _, segment := range strings.Split(expr[1:], ".") {
+		if segment == "" {
+			return nil, fmt.Errorf("transform expression has an empty segment (got: %s)", expr)
+		}
+		key := segment
+		var indexes []int
+		for {
+			open := strings.IndexByte(key, '[')
+			if open < 0 {
+				break
+			}
+			close := strings.IndexByte(key, ']')
+			if close < open {
+				return nil, fmt.Errorf("transform expression has an unterminated index (got: %s)", expr)
+			}
+			idx, err := strconv.Atoi(key[open+1 : close])
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("transform expression has an invalid index (got: %s)", expr)
+			}
+			indexes = append(indexes, idx)
+			key = key[:open] + key[close+1:]
+		}
+		if key != "" {
+			steps = append(steps, transformStep{key: key})
+		}
+		for _, idx := range indexes {
+			steps = append(steps, transformStep{index: idx, isIndex: true})
+		}
+	}
+
+	return &transform{steps: steps}, nil
+}
+
+// apply projects the payload; payloads that aren't JSON or don't contain the
+// requested path come back as JSON null so the client can tell the field was
+// absent
+func (t *transform) apply(data []byte) []byte {
+	if t == nil {
+		return data
+	}
+
+	var val any
+	if err := json.Unmarshal(data, &val); err != nil {
+		return []byte("null")
+	}
+
+	for _, step := range t.steps {
+		if step.isIndex {
+			arr, ok := val.([]any)
+			if !ok || step.index >= len(arr) {
+				return []byte("null")
+			}
+			val = arr[step.index]
+			continue
+		}
+		obj, ok := val.(map[string]any)
+		if !ok {
+			return []byte("null")
+		}
+		val, ok = obj[step.key]
+		if !ok {
+			return []byte("null")
+		}
+	}
+
+	out, err := json.Marshal(val)
+	if err != nil {
+		return []byte("null")
+	}
+	return out
+}
@@ -0,0 +1,144 @@
+package pubsub
+
+import (
+	"context"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	"github.com/mustafaturan/monoflake"
+)
+
+// record snapshots the persisted fields of a topic for the KV token record
+func (p *pubsub) record() tokenRecord {
+	return tokenRecord{
+		token:          p.token,
+		tokenExpiresAt: p.tokenExpiresAt,
+		name:           p.name,
+		tickFrequency:  p.tickFrequency,
+		retryHint:      p.retryHint,
+		retentionSize:  p.retained.capacity(),
+		maxSubscribers: p.maxSubscribers,
+	}
+}
+
+// Update applies a partial settings change to a live topic; nil request
+// fields keep their current values. New settings take effect for subsequent
+// subscriptions and publishes — already-open streams keep the heartbeat
+// interval they were handed at subscribe time. Resizing retention replaces
+// the ring, so the replay history starts fresh.
+func (c *controller) Update(ctx context.Context, req entity.UpdatePubSubRequest) error {
+	tenant, ok := c.tenantForAPIToken(req.ApiAccessToken)
+	if !ok {
+		return entity.Err{
+			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
+			Message: "API access token mismatch",
+			Details: map[string]any{
+				"token": req.ApiAccessToken,
+			},
+		}
+	}
+
+	t, ok := c.pubsubs.Load(req.ID)
+	if !ok {
+		return entity.Err{
+			Code:    404,
+			Kind:    entity.ErrorKindPubSubNotFound,
+			Message: "pubsub not found",
+			Details: map[string]any{
+				"id": req.ID,
+			},
+		}
+	}
+	pubsub, ok := t.(*pubsub)
+	if !ok {
+		return entity.Err{
+			Code:    500,
+			Message: "malformed pubsub type",
+			Details: map[string]any{
+				"id": req.ID,
+			},
+		}
+	}
+
+	if pubsub.static {
+		return entity.Err{
+			Code:    400,
+			Message: "static pubsubs are managed through configuration",
+			Details: map[string]any{
+				"id": req.ID,
+			},
+		}
+	}
+
+	if pubsub.tenant != tenant {
+		return entity.Err{
+			Code:    entity.ErrorCodeForbidden,
+			Message: "pubsub belongs to another tenant",
+			Details: map[string]any{
+				"id": req.ID,
+			},
+		}
+	}
+
+	if req.Persist != nil && *req.Persist && c.kv == nil {
+		return entity.Err{
+			Code:    400,
+			Kind:    entity.ErrorKindStorageUnavailable,
+			Message: "Persistent store is not available",
+		}
+	}
+
+	pubsub.mutex.Lock()
+	if req.TickFrequency != nil {
+		pubsub.tickFrequency = *req.TickFrequency
+	}
+	if req.RetryHint != nil {
+		pubsub.retryHint = *req.RetryHint
+	}
+	if req.MaxSubscribers != nil {
+		pubsub.maxSubscribers = *req.MaxSubscribers
+	}
+	if req.RetentionBufferSize != nil && *req.RetentionBufferSize != pubsub.retained.capacity() {
+		pubsub.retained = newRetentionBuffer(*req.RetentionBufferSize)
+	}
+	if req.Persist != nil {
+		pubsub.persist = *req.Persist
+	}
+	record := pubsub.record()
+	persist := pubsub.persist
+	pubsub.mutex.Unlock()
+
+	if c.kv == nil {
+		return nil
+	}
+
+	// the token record is rewritten (or dropped) so the new settings survive
+	// a restart
+	if persist {
+		err := c.kv.SetIn(ctx, pubsub.tenant, monoflake.ID(req.ID).BigEndianBytes(), encodeTokenRecord(record))
+		if err != nil {
+			return entity.Err{
+				Code:    500,
+				Kind:    entity.ErrorKindStorageFailure,
+				Message: "Couldn't persist to store",
+				Details: map[string]any{
+					"err": err.Error(),
+				},
+			}
+		}
+	} else if req.Persist != nil {
+		err := c.kv.DeleteIn(ctx, pubsub.tenant, monoflake.ID(req.ID).BigEndianBytes())
+		if err != nil {
+			return entity.Err{
+				Code:    500,
+				Kind:    entity.ErrorKindStorageFailure,
+				Message: "Couldn't delete the pubsub from storage",
+				Details: map[string]any{
+					"id": req.ID,
+				},
+			}
+		}
+	}
+
+	return nil
+}
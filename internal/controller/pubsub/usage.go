@@ -0,0 +1,289 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	zlog "github.com/rs/zerolog/log"
+)
+
+// usageBucket is the KV bucket holding the hourly usage rollups, keyed by
+// tenant and period start; the underscore keeps it out of the tenant bucket
+// namespace
+const usageBucket = "_usage"
+
+// defaultUsageRollupFrequency closes and persists a usage window when the
+// config leaves the frequency unset
+const defaultUsageRollupFrequency = time.Hour
+
+// usageSampleFrequency is how often open connections are metered into
+// connection-seconds; sampling catches streams that outlive a window without
+// needing a disconnect hook on every exit path
+const usageSampleFrequency = time.Minute
+
+// usageDefaultTenant labels usage accrued under the instance-wide key, which
+// has no tenant of its own
+const usageDefaultTenant = "_default"
+
+type (
+	// usageCounters accumulates one tenant's billable activity for the
+	// current window; all fields are bumped atomically on the hot path and
+	// swapped out when the window closes
+	usageCounters struct {
+		publishes   int64
+		delivered   int64
+		bytesIn     int64
+		bytesOut    int64
+		connSeconds int64
+	}
+
+	// usageRecord is the persisted form of a closed window
+	usageRecord struct {
+		Publishes         int64 `json:"publishes"`
+		Delivered         int64 `json:"delivered"`
+		BytesIn           int64 `json:"bytes_in"`
+		BytesOut          int64 `json:"bytes_out"`
+		ConnectionSeconds int64 `json:"connection_seconds"`
+	}
+)
+
+// recordPublishUsage meters one accepted publish: the ingested payload,
+// and the fan-out cost of the deliveries it enqueued.
+func (c *controller) recordPublishUsage(tenant string, bytesIn int64, enqueued int, bytesOut int64) {
+	u := c.usageFor(tenant)
+	atomic.AddInt64(&u.publishes, 1)
+	atomic.AddInt64(&u.delivered, int64(enqueued))
+	atomic.AddInt64(&u.bytesIn, bytesIn)
+	atomic.AddInt64(&u.bytesOut, int64(enqueued)*bytesOut)
+}
+
+func (c *controller) usageFor(tenant string) *usageCounters {
+	if tenant == "" {
+		tenant = usageDefaultTenant
+	}
+	u, _ := c.usage.LoadOrStore(tenant, &usageCounters{})
+	return u.(*usageCounters)
+}
+
+// runUsageSampler meters open connections into connection-seconds; each tick
+// charges every tenant its current subscriber count times the elapsed
+// interval, so a stream is billed for as long as it stays connected.
+func (c *controller) runUsageSampler() {
+	ticker := time.NewTicker(usageSampleFrequency)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for now := range ticker.C {
+		elapsed := int64(now.Sub(last).Seconds())
+		last = now
+		if elapsed <= 0 {
+			continue
+		}
+		counts := make(map[string]int64)
+		c.pubsubs.Range(func(_, v any) bool {
+			if ps, ok := v.(*pubsub); ok {
+				if n := ps.subscribers.len(); n > 0 {
+					counts[ps.tenant] += int64(n)
+				}
+			}
+			return true
+		})
+		for tenant, n := range counts {
+			atomic.AddInt64(&c.usageFor(tenant).connSeconds, n*elapsed)
+		}
+	}
+}
+
+// runUsageRollup closes the usage window on the configured cadence and
+// persists each tenant's counters; a window that lands on an existing record
+// (a restart mid-hour) merges into it instead of overwriting.
+func (c *controller) runUsageRollup() {
+	frequency := c.config().UsageRollupFrequency
+	if frequency <= 0 {
+		frequency = defaultUsageRollupFrequency
+	}
+	ticker := time.NewTicker(frequency)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		c.flushUsage(frequency)
+	}
+}
+
+// flushUsage swaps every tenant's counters out and persists the closed
+// window under its period start.
+func (c *controller) flushUsage(frequency time.Duration) {
+	windowStart := time.Unix(atomic.LoadInt64(&c.usageWindowStart), 0).UTC()
+	period := windowStart.Truncate(frequency)
+	atomic.StoreInt64(&c.usageWindowStart, time.Now().UTC().Unix())
+
+	ctx := context.Background()
+	c.usage.Range(func(k, v any) bool {
+		tenant := k.(string)
+		u := v.(*usageCounters)
+		rec := usageRecord{
+			Publishes:         atomic.SwapInt64(&u.publishes, 0),
+			Delivered:         atomic.SwapInt64(&u.delivered, 0),
+			BytesIn:           atomic.SwapInt64(&u.bytesIn, 0),
+			BytesOut:          atomic.SwapInt64(&u.bytesOut, 0),
+			ConnectionSeconds: atomic.SwapInt64(&u.connSeconds, 0),
+		}
+		if rec == (usageRecord{}) {
+			return true
+		}
+
+		key := []byte(tenant + "|" + period.Format(time.RFC3339))
+		if prev, err := c.kv.GetIn(ctx, usageBucket, key); err == nil {
+			var old usageRecord
+			if json.Unmarshal(prev, &old) == nil {
+				rec.Publishes += old.Publishes
+				rec.Delivered += old.Delivered
+				rec.BytesIn += old.BytesIn
+				rec.BytesOut += old.BytesOut
+				rec.ConnectionSeconds += old.ConnectionSeconds
+			}
+		}
+		raw, _ := json.Marshal(rec)
+		if err := c.kv.SetIn(ctx, usageBucket, key, raw); err != nil {
+			zlog.Error().Err(err).Str("tenant", tenant).Msg(logPrefix + "failed to persist usage rollup; counters for the window are lost")
+		}
+		return true
+	})
+}
+
+// GetUsage returns the usage rollups overlapping the requested range plus the
+// still-open window, so a billing export taken mid-hour doesn't under-count.
+// A tenant-scoped key sees its own rows; the instance-wide key sees every
+// tenant.
+func (c *controller) GetUsage(ctx context.Context, req entity.GetUsageRequest) (*entity.GetUsageResponse, error) {
+	tenant, ok := c.tenantForAPIToken(req.ApiAccessToken)
+	if !ok {
+		return nil, entity.Err{
+			Code:    401,
+			Kind:    entity.ErrorKindTokenMismatch,
+			Message: "API access token mismatch",
+			Details: map[string]any{
+				"token": req.ApiAccessToken,
+			},
+		}
+	}
+
+	to := req.To
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	from := req.From
+	if from.IsZero() {
+		from = to.Add(-24 * time.Hour)
+	}
+	if !from.Before(to) {
+		return nil, entity.Err{
+			Code:    400,
+			Kind:    entity.ErrorKindValidationFailed,
+			Message: "from must be before to",
+			Details: map[string]any{
+				"from": from.Format(time.RFC3339),
+				"to":   to.Format(time.RFC3339),
+			},
+		}
+	}
+
+	rows := make([]entity.UsageRow, 0)
+	if c.kv != nil {
+		keys, err := c.kv.ListKeysIn(ctx, usageBucket)
+		if err != nil {
+			return nil, entity.Err{
+				Code:    500,
+				Kind:    entity.ErrorKindStorageFailure,
+				Message: "Couldn't list usage rollups",
+				Details: map[string]any{
+					"err": err.Error(),
+				},
+			}
+		}
+		for _, k := range keys {
+			rowTenant, period, ok := parseUsageKey(string(k))
+			if !ok {
+				continue
+			}
+			if tenant != "" && rowTenant != tenant {
+				continue
+			}
+			if period.Before(from) || !period.Before(to) {
+				continue
+			}
+			raw, err := c.kv.GetIn(ctx, usageBucket, k)
+			if err != nil {
+				continue
+			}
+			var rec usageRecord
+			if json.Unmarshal(raw, &rec) != nil {
+				continue
+			}
+			rows = append(rows, usageRowFromRecord(rowTenant, period, rec))
+		}
+	}
+
+	// the open window is reported as-is under its start time; it merges into
+	// a persisted row once the rollup closes it
+	openStart := time.Unix(atomic.LoadInt64(&c.usageWindowStart), 0).UTC()
+	if !openStart.Before(from) && openStart.Before(to) {
+		c.usage.Range(func(k, v any) bool {
+			rowTenant := k.(string)
+			if tenant != "" && rowTenant != tenant {
+				return true
+			}
+			u := v.(*usageCounters)
+			rec := usageRecord{
+				Publishes:         atomic.LoadInt64(&u.publishes),
+				Delivered:         atomic.LoadInt64(&u.delivered),
+				BytesIn:           atomic.LoadInt64(&u.bytesIn),
+				BytesOut:          atomic.LoadInt64(&u.bytesOut),
+				ConnectionSeconds: atomic.LoadInt64(&u.connSeconds),
+			}
+			if rec != (usageRecord{}) {
+				rows = append(rows, usageRowFromRecord(rowTenant, openStart, rec))
+			}
+			return true
+		})
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].PeriodStart.Equal(rows[j].PeriodStart) {
+			return rows[i].PeriodStart.Before(rows[j].PeriodStart)
+		}
+		return rows[i].Tenant < rows[j].Tenant
+	})
+
+	return &entity.GetUsageResponse{Rows: rows}, nil
+}
+
+func usageRowFromRecord(tenant string, period time.Time, rec usageRecord) entity.UsageRow {
+	return entity.UsageRow{
+		Tenant:            tenant,
+		PeriodStart:       period,
+		Publishes:         rec.Publishes,
+		Delivered:         rec.Delivered,
+		BytesIn:           rec.BytesIn,
+		BytesOut:          rec.BytesOut,
+		ConnectionSeconds: rec.ConnectionSeconds,
+	}
+}
+
+// parseUsageKey splits a rollup key back into its tenant and period start.
+func parseUsageKey(key string) (tenant string, period time.Time, ok bool) {
+	sep := strings.LastIndexByte(key, '|')
+	if sep < 0 {
+		return "", time.Time{}, false
+	}
+	period, err := time.Parse(time.RFC3339, key[sep+1:])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return key[:sep], period.UTC(), true
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	zlog "github.com/rs/zerolog/log"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// headerRequestID carries the generated request id back to the client so
+	// support tickets can reference one.
+	headerRequestID = "X-Request-Id"
+
+	// userValueRequestID is where the request id is stashed on the request
+	// context for the handlers below the access log.
+	userValueRequestID = "requestID"
+
+	logPrefix = "[http] "
+)
+
+// requestID reuses a client-provided X-Request-Id (so upstream proxies keep
+// their correlation ids) or generates a fresh one.
+func requestID(ctx *fasthttp.RequestCtx) string {
+	if id := string(ctx.Request.Header.Peek(headerRequestID)); id != "" && len(id) <= 64 {
+		return id
+	}
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// logAccess writes one structured line per finished request. Streaming
+// requests log when their stream ends, so their latency reflects the stream
+// lifetime.
+func logAccess(ctx *fasthttp.RequestCtx, start time.Time) {
+	status := ctx.Response.StatusCode()
+	event := zlog.Info()
+	if status >= 500 {
+		event = zlog.Error()
+	}
+
+	event = event.
+		Str("requestID", requestIDFromUserValue(ctx)).
+		Str("method", string(ctx.Method())).
+		Str("path", string(ctx.Path())).
+		Int("status", status).
+		Dur("latency", time.Since(start)).
+		Str("ip", accessLogIP(ctx))
+
+	if fingerprint := apiKeyFingerprint(ctx); fingerprint != "" {
+		event = event.Str("apiKeyID", fingerprint)
+	}
+	if id, ok := ctx.UserValue("id").(string); ok && id != "" {
+		event = event.Str("pubsubID", id)
+	}
+
+	event.Msg(logPrefix + "request completed")
+}
+
+// accessLogIP prefers the proxy-aware resolved client address over the TCP
+// peer, so logs behind a load balancer show real callers
+func accessLogIP(ctx *fasthttp.RequestCtx) string {
+	if ip := clientIPFromUserValue(ctx); ip != "" {
+		return ip
+	}
+	return ctx.RemoteIP().String()
+}
+
+// requestContext builds the controller-facing context for a request, carrying
+// the request id so controller logs correlate with the access log.
+func requestContext(ctx *fasthttp.RequestCtx) context.Context {
+	return entity.WithRequestID(context.Background(), requestIDFromUserValue(ctx))
+}
+
+func requestIDFromUserValue(ctx *fasthttp.RequestCtx) string {
+	if id, ok := ctx.UserValue(userValueRequestID).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// apiKeyFingerprint identifies the presented bearer token without logging
+// any part of the secret itself.
+func apiKeyFingerprint(ctx *fasthttp.RequestCtx) string {
+	authorization := string(ctx.Request.Header.Peek("Authorization"))
+	token := strings.Replace(authorization, "Bearer ", "", 1)
+	if token == "" {
+		return ""
+	}
+	h := fnv.New32a()
+	h.Write([]byte(token))
+	return hex.EncodeToString(h.Sum(nil))
+}
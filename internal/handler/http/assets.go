@@ -0,0 +1,65 @@
+package http
+
+import (
+	"embed"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// The dashboard UI ships inside the binary so deployments don't depend on
+// the working directory containing a ./public copy of the assets.
+//
+//go:embed public/index.html public/admin.html public/favicon.ico public/assets/main.css public/assets/chart.js
+var _publicFS embed.FS
+
+// serveAsset writes a built-in UI asset from the embedded filesystem; when
+// `assetsDir` is configured the asset is read from that directory instead so
+// deployments can customize or rebrand the UI without forking.
+func (h *handler) serveAsset(ctx *fasthttp.RequestCtx, name string) {
+	if dir := h.cfg.AssetsDir; dir != "" {
+		fasthttp.ServeFile(ctx, filepath.Join(dir, name))
+		return
+	}
+
+	data, err := _publicFS.ReadFile("public/" + name)
+	if err != nil {
+		notfound(ctx)
+		return
+	}
+
+	ctx.SetContentType(assetContentType(name))
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody(data)
+}
+
+func assetContentType(name string) string {
+	switch path.Ext(name) {
+	case ".html":
+		return "text/html; charset=utf-8"
+	case ".css":
+		return "text/css; charset=utf-8"
+	case ".js":
+		return "text/javascript; charset=utf-8"
+	case ".ico":
+		return "image/x-icon"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// assetName maps a request path onto an embedded asset; the empty return
+// means the path is not a UI asset.
+func assetName(requestPath string) string {
+	switch requestPath {
+	case "/":
+		return "index.html"
+	case "/admin":
+		return "admin.html"
+	case "/favicon.ico", "/assets/chart.js", "/assets/main.css":
+		return strings.TrimPrefix(requestPath, "/")
+	}
+	return ""
+}
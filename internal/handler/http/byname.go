@@ -0,0 +1,26 @@
+package http
+
+import (
+	errmapper "github.com/hasmcp/sser/internal/mapper/err"
+	"github.com/mustafaturan/monoflake"
+	"github.com/valyala/fasthttp"
+)
+
+// byName wraps an id-based handler so it can serve `/by-name/:name` routes:
+// the alias is resolved through the controller's name registry and the numeric
+// id is injected as the `id` path param the wrapped handler already reads.
+func (h *handler) byName(next func(ctx *fasthttp.RequestCtx)) func(ctx *fasthttp.RequestCtx) {
+	return func(ctx *fasthttp.RequestCtx) {
+		name, _ := ctx.UserValue("name").(string)
+		id, err := h.pubsub.ResolveName(requestContext(ctx), name)
+		if err != nil {
+			msg, code := errmapper.FromErrorToHttpResponse(err)
+			ctx.SetContentType("application/json")
+			ctx.SetStatusCode(code)
+			ctx.SetBody(msg)
+			return
+		}
+		ctx.SetUserValue("id", monoflake.ID(id).String())
+		next(ctx)
+	}
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"net"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// userValueClientIP is where the resolved client address is stashed so the
+// access log, firewall, and any rate limiting all agree on who the caller is.
+const userValueClientIP = "clientIP"
+
+// resolveClientIP returns the real client address. Forwarding headers are
+// only honored when the TCP peer is a trusted proxy — behind a load balancer
+// every connection otherwise appears to come from the proxy, while on a
+// directly exposed listener the headers are attacker-controlled.
+func (h *handler) resolveClientIP(ctx *fasthttp.RequestCtx) net.IP {
+	remote := ctx.RemoteIP()
+	if !containsIP(h.trustedProxies, remote) {
+		return remote
+	}
+
+	// RFC 7239 Forwarded takes precedence where present
+	if fwd := string(ctx.Request.Header.Peek("Forwarded")); fwd != "" {
+		if ip := parseForwardedHeader(fwd); ip != nil {
+			return ip
+		}
+	}
+	// X-Forwarded-For: walk right to left past our own trusted proxies to the
+	// first address a proxy appended rather than one the client sent
+	if xff := string(ctx.Request.Header.Peek("X-Forwarded-For")); xff != "" {
+		entries := strings.Split(xff, ",")
+		for i := len(entries) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(entries[i]))
+			if ip == nil {
+				break
+			}
+			if !containsIP(h.trustedProxies, ip) {
+				return ip
+			}
+		}
+	}
+	if xr := string(ctx.Request.Header.Peek("X-Real-IP")); xr != "" {
+		if ip := net.ParseIP(strings.TrimSpace(xr)); ip != nil {
+			return ip
+		}
+	}
+	return remote
+}
+
+// parseForwardedHeader extracts the client address from the first for=
+// directive of an RFC 7239 Forwarded header
+func parseForwardedHeader(fwd string) net.IP {
+	first := strings.Split(fwd, ",")[0]
+	for _, part := range strings.Split(first, ";") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || !strings.EqualFold(k, "for") {
+			continue
+		}
+		v = strings.Trim(v, `"`)
+		// bracketed IPv6 and optional port forms
+		v = strings.TrimPrefix(v, "[")
+		if i := strings.Index(v, "]"); i >= 0 {
+			v = v[:i]
+		} else if host, _, err := net.SplitHostPort(v); err == nil {
+			v = host
+		}
+		return net.ParseIP(v)
+	}
+	return nil
+}
+
+// clientIPFromUserValue reads the address resolved at the top of Handle;
+// callers outside a request (or before resolution) get an empty string
+func clientIPFromUserValue(ctx *fasthttp.RequestCtx) string {
+	if ip, ok := ctx.UserValue(userValueClientIP).(string); ok {
+		return ip
+	}
+	return ""
+}
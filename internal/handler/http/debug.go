@@ -0,0 +1,101 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	errmapper "github.com/hasmcp/sser/internal/mapper/err"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// debugConfig switches the /debug endpoints on; they expose process
+// internals (pprof profiles, GC stats, build info), so they only register on
+// the admin router and always demand the access token.
+type debugConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// AccessToken is required as the bearer token on every /debug request;
+	// leaving it empty keeps the endpoints unusable even when enabled
+	AccessToken string `yaml:"accessToken"`
+}
+
+// the pprof handlers are net/http handlers; the adaptor bridges them onto
+// fasthttp once at startup
+var (
+	_pprofIndex   = fasthttpadaptor.NewFastHTTPHandlerFunc(pprof.Index)
+	_pprofCmdline = fasthttpadaptor.NewFastHTTPHandlerFunc(pprof.Cmdline)
+	_pprofProfile = fasthttpadaptor.NewFastHTTPHandlerFunc(pprof.Profile)
+	_pprofSymbol  = fasthttpadaptor.NewFastHTTPHandlerFunc(pprof.Symbol)
+	_pprofTrace   = fasthttpadaptor.NewFastHTTPHandlerFunc(pprof.Trace)
+)
+
+// debugAuth guards a /debug endpoint with the configured access token; there
+// is no anonymous fallback.
+func (h *handler) debugAuth(next func(ctx *fasthttp.RequestCtx)) func(ctx *fasthttp.RequestCtx) {
+	return func(ctx *fasthttp.RequestCtx) {
+		authorization := string(ctx.Request.Header.Peek("Authorization"))
+		token := strings.Replace(authorization, "Bearer ", "", 1)
+		if h.cfg.Debug.AccessToken == "" || token != h.cfg.Debug.AccessToken {
+			msg, code := errmapper.FromErrorToHttpResponse(entity.Err{
+				Code:    401,
+				Kind:    entity.ErrorKindUnauthorized,
+				Message: "debug access token mismatch",
+			})
+			ctx.SetStatusCode(code)
+			ctx.SetContentType("application/json")
+			ctx.SetBody(msg)
+			return
+		}
+		next(ctx)
+	}
+}
+
+// debugPprof dispatches to the net/http/pprof handlers; the :profile segment
+// matches the paths the go tool expects (profile, trace, heap, goroutine,
+// ...), with pprof.Index resolving the named runtime profiles itself from
+// the request path.
+func (h *handler) debugPprof(ctx *fasthttp.RequestCtx) {
+	profile, _ := ctx.UserValue("profile").(string)
+	switch profile {
+	case "cmdline":
+		_pprofCmdline(ctx)
+	case "profile":
+		_pprofProfile(ctx)
+	case "symbol":
+		_pprofSymbol(ctx)
+	case "trace":
+		_pprofTrace(ctx)
+	default:
+		_pprofIndex(ctx)
+	}
+}
+
+// debugRuntime reports GC stats, goroutine counts and build info as JSON for
+// quick triage without pulling a full profile.
+func (h *handler) debugRuntime(ctx *fasthttp.RequestCtx) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	payload := map[string]any{
+		"goroutines":        runtime.NumGoroutine(),
+		"gomaxprocs":        runtime.GOMAXPROCS(0),
+		"heap_alloc_bytes":  ms.HeapAlloc,
+		"heap_objects":      ms.HeapObjects,
+		"next_gc_bytes":     ms.NextGC,
+		"num_gc":            ms.NumGC,
+		"gc_pause_total_ns": ms.PauseTotalNs,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		payload["go_version"] = bi.GoVersion
+		payload["module"] = bi.Main.Path
+		payload["version"] = bi.Main.Version
+	}
+
+	body, _ := json.Marshal(payload)
+	ctx.SetContentType("application/json")
+	ctx.SetBody(body)
+}
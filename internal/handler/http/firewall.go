@@ -0,0 +1,187 @@
+package http
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	"github.com/valyala/fasthttp"
+)
+
+type (
+	// firewallConfig is the yaml shape of the IP allow/deny rules; rules are
+	// scoped so publish can be locked to internal networks while
+	// subscriptions stay public.
+	firewallConfig struct {
+		// TrustForwardedFor uses the first X-Forwarded-For entry as the client
+		// address; only enable it behind a proxy that overwrites the header
+		TrustForwardedFor bool                 `yaml:"trustForwardedFor"`
+		Rules             []firewallRuleConfig `yaml:"rules"`
+	}
+
+	firewallRuleConfig struct {
+		// Scope selects which endpoints the rule guards: publish, admin,
+		// metrics, or all
+		Scope string `yaml:"scope"`
+		// Allow lists the only CIDRs admitted when non-empty
+		Allow []string `yaml:"allow"`
+		// Deny lists CIDRs rejected outright; deny wins over allow
+		Deny []string `yaml:"deny"`
+	}
+
+	// firewall holds the compiled rules; a nil firewall admits everything.
+	firewall struct {
+		trustForwardedFor bool
+		rules             []firewallRule
+	}
+
+	firewallRule struct {
+		scope string
+		allow []*net.IPNet
+		deny  []*net.IPNet
+	}
+)
+
+const (
+	firewallScopeAll     = "all"
+	firewallScopePublish = "publish"
+	firewallScopeAdmin   = "admin"
+	firewallScopeMetrics = "metrics"
+)
+
+// newFirewall compiles the configured CIDRs once at startup so the hot path
+// only does mask checks; a config without rules yields a nil firewall.
+func newFirewall(cfg firewallConfig) (*firewall, error) {
+	if len(cfg.Rules) == 0 {
+		return nil, nil
+	}
+
+	f := &firewall{
+		trustForwardedFor: cfg.TrustForwardedFor,
+		rules:             make([]firewallRule, 0, len(cfg.Rules)),
+	}
+	for _, rc := range cfg.Rules {
+		switch rc.Scope {
+		case firewallScopeAll, firewallScopePublish, firewallScopeAdmin, firewallScopeMetrics:
+		default:
+			return nil, fmt.Errorf("firewall: unknown scope %q", rc.Scope)
+		}
+		rule := firewallRule{scope: rc.Scope}
+		var err error
+		if rule.allow, err = parseCIDRs(rc.Allow); err != nil {
+			return nil, err
+		}
+		if rule.deny, err = parseCIDRs(rc.Deny); err != nil {
+			return nil, err
+		}
+		f.rules = append(f.rules, rule)
+	}
+	return f, nil
+}
+
+// parseCIDRs accepts CIDR notation and bare addresses (treated as host
+// routes)
+func parseCIDRs(entries []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(entries))
+	for _, e := range entries {
+		if !strings.Contains(e, "/") {
+			ip := net.ParseIP(e)
+			if ip == nil {
+				return nil, fmt.Errorf("firewall: invalid address %q", e)
+			}
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(e)
+		if err != nil {
+			return nil, fmt.Errorf("firewall: invalid CIDR %q: %w", e, err)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// check evaluates the request against every rule matching its scope; deny
+// entries win, and a non-empty allow list admits only its members. A nil
+// firewall admits everything.
+func (f *firewall) check(ctx *fasthttp.RequestCtx) error {
+	if f == nil {
+		return nil
+	}
+
+	ip := f.clientIP(ctx)
+	scope := requestScope(ctx)
+	for _, rule := range f.rules {
+		if rule.scope != firewallScopeAll && rule.scope != scope {
+			continue
+		}
+		if containsIP(rule.deny, ip) {
+			return firewallDenied()
+		}
+		if len(rule.allow) > 0 && !containsIP(rule.allow, ip) {
+			return firewallDenied()
+		}
+	}
+	return nil
+}
+
+func firewallDenied() error {
+	return entity.Err{
+		Code:    entity.ErrorCodeForbidden,
+		Kind:    entity.ErrorKindForbidden,
+		Message: "source address is not permitted for this endpoint",
+	}
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP resolves the address the rules apply to. The handler's
+// trusted-proxy resolution is the default; the legacy trustForwardedFor flag
+// keeps working for deployments that only configured the firewall.
+func (f *firewall) clientIP(ctx *fasthttp.RequestCtx) net.IP {
+	if f.trustForwardedFor {
+		if fwd := string(ctx.Request.Header.Peek("X-Forwarded-For")); fwd != "" {
+			first := strings.TrimSpace(strings.Split(fwd, ",")[0])
+			if ip := net.ParseIP(first); ip != nil {
+				return ip
+			}
+		}
+	}
+	if s := clientIPFromUserValue(ctx); s != "" {
+		if ip := net.ParseIP(s); ip != nil {
+			return ip
+		}
+	}
+	return ctx.RemoteIP()
+}
+
+// requestScope classifies the request for rule matching: event publishes,
+// anything under /admin, the metrics endpoint, or unscoped
+func requestScope(ctx *fasthttp.RequestCtx) string {
+	path := string(ctx.Path())
+	switch {
+	case strings.HasPrefix(path, pathBase+"/admin"), strings.HasPrefix(path, pathDebug):
+		return firewallScopeAdmin
+	case path == pathMetrics:
+		return firewallScopeMetrics
+	case string(ctx.Method()) == fasthttp.MethodPost && strings.HasSuffix(path, "/events"):
+		return firewallScopePublish
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	"github.com/valyala/fasthttp"
+)
+
+// maxGzipRatio caps how much a gzip publish body may expand on decompression,
+// protecting the server from zip bombs sent through small compressed bodies
+const maxGzipRatio = 100
+
+// decompressPublishBody transparently inflates a `Content-Encoding: gzip`
+// publish body in place so the mappers always see plain bytes. It returns an
+// entity.Err when the body is not valid gzip or expands past maxGzipRatio.
+func decompressPublishBody(ctx *fasthttp.RequestCtx) error {
+	encoding := ctx.Request.Header.Peek(fasthttp.HeaderContentEncoding)
+	if len(encoding) == 0 || !bytes.EqualFold(encoding, []byte("gzip")) {
+		return nil
+	}
+
+	compressed := ctx.PostBody()
+	if len(compressed) == 0 {
+		return nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return entity.Err{
+			Code:    entity.ErrorCodeBadRequest,
+			Message: "request body is not valid gzip",
+			Details: map[string]any{
+				"err": err.Error(),
+			},
+		}
+	}
+	defer zr.Close()
+
+	limit := int64(len(compressed))*maxGzipRatio + 1
+	body, err := io.ReadAll(io.LimitReader(zr, limit))
+	if err != nil {
+		return entity.Err{
+			Code:    entity.ErrorCodeBadRequest,
+			Message: "request body is not valid gzip",
+			Details: map[string]any{
+				"err": err.Error(),
+			},
+		}
+	}
+	if int64(len(body)) >= limit {
+		return entity.Err{
+			Code:    entity.ErrorCodeRequestEntityTooLarge,
+			Message: "gzip body expands past the allowed compression ratio",
+			Details: map[string]any{
+				"maxRatio": maxGzipRatio,
+			},
+		}
+	}
+
+	ctx.Request.SetBody(body)
+	ctx.Request.Header.Del(fasthttp.HeaderContentEncoding)
+	return nil
+}
@@ -2,15 +2,19 @@ package http
 
 import (
 	"bufio"
-	"context"
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
 	"github.com/hasmcp/sser/internal/_data/entity"
 	"github.com/hasmcp/sser/internal/controller/pubsub"
+	"github.com/hasmcp/sser/internal/histogram"
 	errmapper "github.com/hasmcp/sser/internal/mapper/err"
 	pubsubmapper "github.com/hasmcp/sser/internal/mapper/pubsub"
+	"github.com/hasmcp/sser/internal/servicer/config"
+	"github.com/hasmcp/sser/internal/servicer/server"
+	"github.com/hasmcp/sser/internal/validator"
 	zlog "github.com/rs/zerolog/log"
 	"github.com/valyala/fasthttp"
 )
@@ -18,21 +22,90 @@ import (
 type (
 	Handler interface {
 		Handle(ctx *fasthttp.RequestCtx)
+		// HandlePublic serves only the data-plane routes (publish, subscribe,
+		// poll and friends); HandleAdmin serves only the management routes.
+		// Together they back split public/admin listeners.
+		HandlePublic(ctx *fasthttp.RequestCtx)
+		HandleAdmin(ctx *fasthttp.RequestCtx)
+		Use(m Middleware)
 	}
 
+	// Middleware runs before routing; returning an error aborts the request
+	// with the mapped error response. Embedders can plug in custom header
+	// validation, IP allowlists, or external auth calls without forking the
+	// handler.
+	Middleware func(ctx *fasthttp.RequestCtx) error
+
 	handler struct {
-		pubsub pubsub.Controller
+		pubsub         pubsub.Controller
+		middlewares    []Middleware
+		cfg            httpConfig
+		requests       *requestMetrics
+		streams        *streamStats
+		firewall       *firewall
+		trustedProxies []*net.IPNet
+		publicRouter   *router
+		adminRouter    *router
+		publishLatency *histogram.Histogram
+		flushLatency   *histogram.Histogram
+		router         *router
 	}
 
 	Params struct {
-		PubSub pubsub.Controller
+		Config      config.Servicer
+		PubSub      pubsub.Controller
+		Middlewares []Middleware
+	}
+
+	httpConfig struct {
+		CORS corsConfig `yaml:"cors"`
+		// MaxEventSize rejects publish bodies larger than this many bytes
+		// before they are JSON-decoded; 0 disables the handler-level check
+		MaxEventSize int `yaml:"maxEventSize"`
+		// AssetsDir overrides the embedded dashboard assets with an external
+		// directory; empty serves the built-in UI
+		AssetsDir string `yaml:"assetsDir"`
+		// VerboseErrorDetails echoes sensitive values (tokens) back in error
+		// details instead of hashing them; only for debugging environments
+		VerboseErrorDetails bool `yaml:"verboseErrorDetails"`
+		// Firewall holds IP/CIDR allow and deny rules checked before routing
+		Firewall firewallConfig `yaml:"firewall"`
+		// TrustedProxies lists the CIDRs of load balancers allowed to assert
+		// the client address via Forwarded/X-Forwarded-For/X-Real-IP; requests
+		// from other peers have those headers ignored
+		TrustedProxies []string `yaml:"trustedProxies"`
+		// Debug exposes pprof and runtime stats under /debug on the admin
+		// router, guarded by its own access token
+		Debug debugConfig `yaml:"debug"`
+	}
+
+	corsConfig struct {
+		AllowedOrigins []string          `yaml:"allowedOrigins"`
+		Routes         []corsRouteConfig `yaml:"routes"`
+	}
+
+	corsRouteConfig struct {
+		PathPrefix     string   `yaml:"pathPrefix"`
+		AllowedOrigins []string `yaml:"allowedOrigins"`
 	}
 )
 
 const (
-	pathBase    string = "/api/v1"
-	pathMetrics string = pathBase + "/metrics"
-	pathPubSubs string = pathBase + "/pubsubs"
+	cfgKey = "http"
+
+	pathBase          string = "/api/v1"
+	pathMetrics       string = pathBase + "/metrics"
+	pathPubSubs       string = pathBase + "/pubsubs"
+	pathPubSubsByName string = pathPubSubs + "/by-name"
+	pathErrors        string = pathBase + "/errors"
+	pathStaticAdmin   string = pathBase + "/admin/static-pubsubs"
+	pathArchiveAdmin  string = pathBase + "/admin/archive"
+	pathDebug         string = "/debug"
+
+	// headerResumptionToken hands the client its reconnect token; it is also
+	// delivered as the stream's initial resume event for browser clients that
+	// cannot read response headers off EventSource
+	headerResumptionToken = "X-Resumption-Token"
 
 	keyEventIDSize   = len("id: \n")
 	keyEventTypeSize = len("event: \n")
@@ -45,27 +118,200 @@ var (
 )
 
 func New(p Params) (Handler, error) {
-	return &handler{
-		pubsub: p.PubSub,
-	}, nil
+	var cfg httpConfig
+	if p.Config != nil {
+		if err := p.Config.Populate(cfgKey, &cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	fw, err := newFirewall(cfg.Firewall)
+	if err != nil {
+		return nil, err
+	}
+	trustedProxies, err := parseCIDRs(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &handler{
+		pubsub:         p.PubSub,
+		middlewares:    p.Middlewares,
+		cfg:            cfg,
+		firewall:       fw,
+		trustedProxies: trustedProxies,
+		requests:       newRequestMetrics(),
+		streams:        newStreamStats(),
+		publishLatency: histogram.New("latency.publish"),
+		flushLatency:   histogram.New("latency.publish_to_flush"),
+	}
+	h.registerRoutes()
+	errmapper.SetVerboseDetails(cfg.VerboseErrorDetails)
+
+	return h, nil
 }
 
-func (h *handler) Handle(ctx *fasthttp.RequestCtx) {
+// registerRoutes declares the full API surface; new endpoints belong here
+// rather than in hand-rolled path switches. Each route also lands on either
+// the public or the admin router, so a split-listener deployment can keep the
+// management surface off the exposed port.
+func (h *handler) registerRoutes() {
+	full, public, admin := newRouter(), newRouter(), newRouter()
+	add := func(adminOnly bool, method, name, pattern string, handle func(ctx *fasthttp.RequestCtx)) {
+		full.add(method, name, pattern, handle)
+		if adminOnly {
+			admin.add(method, name, pattern, handle)
+			return
+		}
+		public.add(method, name, pattern, handle)
+	}
+
+	add(true, fasthttp.MethodPost, "create", pathPubSubs, h.createPubSub)
+	// by-name aliases go first so a topic literally named "events" can't be
+	// shadowed by the :id patterns below
+	add(true, fasthttp.MethodDelete, "delete_by_name", pathPubSubsByName+"/:name", h.byName(h.deletePubSub))
+	add(true, fasthttp.MethodPatch, "update_by_name", pathPubSubsByName+"/:name", h.byName(h.updatePubSub))
+	add(true, fasthttp.MethodPost, "pause_by_name", pathPubSubsByName+"/:name/pause", h.byName(h.pausePubSub))
+	add(true, fasthttp.MethodPost, "resume_by_name", pathPubSubsByName+"/:name/resume", h.byName(h.resumePubSub))
+	add(true, fasthttp.MethodPut, "set_schema_by_name", pathPubSubsByName+"/:name/schema", h.byName(h.setSchema))
+	add(true, fasthttp.MethodGet, "get_schema_by_name", pathPubSubsByName+"/:name/schema", h.byName(h.getSchema))
+	add(true, fasthttp.MethodDelete, "delete_schema_by_name", pathPubSubsByName+"/:name/schema", h.byName(h.deleteSchema))
+	add(true, fasthttp.MethodPut, "set_script_by_name", pathPubSubsByName+"/:name/script", h.byName(h.setScript))
+	add(true, fasthttp.MethodGet, "get_script_by_name", pathPubSubsByName+"/:name/script", h.byName(h.getScript))
+	add(true, fasthttp.MethodDelete, "delete_script_by_name", pathPubSubsByName+"/:name/script", h.byName(h.deleteScript))
+	add(false, fasthttp.MethodPost, "publish_by_name", pathPubSubsByName+"/:name/events", h.byName(h.publishToPubSub))
+	add(false, fasthttp.MethodGet, "subscribe_by_name", pathPubSubsByName+"/:name/events", h.byName(h.subscribeToPubSub))
+	add(false, fasthttp.MethodGet, "poll_by_name", pathPubSubsByName+"/:name/events/poll", h.byName(h.pollPubSub))
+	add(false, fasthttp.MethodGet, "rates_by_name", pathPubSubsByName+"/:name/rates", h.byName(h.getTopicRates))
+	add(false, fasthttp.MethodGet, "subscribers_by_name", pathPubSubsByName+"/:name/subscribers", h.byName(h.getSubscribers))
+	add(true, fasthttp.MethodDelete, "delete", pathPubSubs+"/:id", h.deletePubSub)
+	add(true, fasthttp.MethodPatch, "update", pathPubSubs+"/:id", h.updatePubSub)
+	add(true, fasthttp.MethodPost, "pause", pathPubSubs+"/:id/pause", h.pausePubSub)
+	add(true, fasthttp.MethodPost, "resume", pathPubSubs+"/:id/resume", h.resumePubSub)
+	add(true, fasthttp.MethodPut, "set_schema", pathPubSubs+"/:id/schema", h.setSchema)
+	add(true, fasthttp.MethodGet, "get_schema", pathPubSubs+"/:id/schema", h.getSchema)
+	add(true, fasthttp.MethodDelete, "delete_schema", pathPubSubs+"/:id/schema", h.deleteSchema)
+	add(true, fasthttp.MethodPut, "set_script", pathPubSubs+"/:id/script", h.setScript)
+	add(true, fasthttp.MethodGet, "get_script", pathPubSubs+"/:id/script", h.getScript)
+	add(true, fasthttp.MethodDelete, "delete_script", pathPubSubs+"/:id/script", h.deleteScript)
+	add(false, fasthttp.MethodPost, "publish", pathPubSubs+"/:id/events", h.publishToPubSub)
+	add(false, fasthttp.MethodGet, "subscribe", pathPubSubs+"/:id/events", h.subscribeToPubSub)
+	add(false, fasthttp.MethodGet, "poll", pathPubSubs+"/:id/events/poll", h.pollPubSub)
+	add(false, fasthttp.MethodGet, "rates", pathPubSubs+"/:id/rates", h.getTopicRates)
+	add(false, fasthttp.MethodGet, "subscribers", pathPubSubs+"/:id/subscribers", h.getSubscribers)
+	add(true, fasthttp.MethodGet, "metrics", pathMetrics, h.getMetrics)
+	add(true, fasthttp.MethodGet, "usage", pathBase+"/usage", h.getUsage)
+	add(false, fasthttp.MethodGet, "errors", pathErrors, h.listErrorCatalog)
+	add(false, fasthttp.MethodGet, "readyz", "/readyz", h.readyz)
+	add(true, fasthttp.MethodGet, "admin_pubsubs", pathBase+"/admin/pubsubs", h.listPubSubs)
+	add(true, fasthttp.MethodGet, "admin_export", pathBase+"/admin/export", h.exportTopics)
+	add(true, fasthttp.MethodPost, "admin_import", pathBase+"/admin/import", h.importTopics)
+	add(true, fasthttp.MethodGet, "admin_backup", pathBase+"/admin/backup", h.backupStore)
+	add(true, fasthttp.MethodPut, "static_upsert", pathStaticAdmin, h.upsertStaticPubSub)
+	add(true, fasthttp.MethodDelete, "static_delete", pathStaticAdmin+"/:id", h.deleteStaticPubSub)
+	add(true, fasthttp.MethodGet, "archive_query", pathArchiveAdmin+"/:id", h.queryArchive)
+	h.registerRoutesV2(add)
+	if h.cfg.Debug.Enabled {
+		add(true, fasthttp.MethodGet, "debug_pprof", pathDebug+"/pprof", h.debugAuth(h.debugPprof))
+		add(true, fasthttp.MethodGet, "debug_pprof_profile", pathDebug+"/pprof/:profile", h.debugAuth(h.debugPprof))
+		add(true, fasthttp.MethodPost, "debug_pprof_symbol", pathDebug+"/pprof/symbol", h.debugAuth(h.debugPprof))
+		add(true, fasthttp.MethodGet, "debug_runtime", pathDebug+"/runtime", h.debugAuth(h.debugRuntime))
+	}
+	// browser admin UIs preflight every endpoint, so OPTIONS is answered for
+	// any registered path instead of being wired route by route
+	full.preflight = h.allowOrigin
+	public.preflight = h.allowOrigin
+	admin.preflight = h.allowOrigin
+	h.router = full
+	h.publicRouter = public
+	h.adminRouter = admin
+}
+
+// resolveOrigin decides which origin (if any) to reflect for the request and
+// whether credentials may be allowed. Credentials are only ever granted to
+// explicitly configured origins and never alongside the wildcard, since the
+// old reflect-anything behavior enabled cross-site subscription abuse.
+func (h *handler) resolveOrigin(ctx *fasthttp.RequestCtx) (string, bool) {
+	allowed := h.cfg.CORS.AllowedOrigins
 	path := string(ctx.Path())
-	if path == "/" {
-		fasthttp.ServeFile(ctx, "./public/index.html")
-		return
+	for _, route := range h.cfg.CORS.Routes {
+		if strings.HasPrefix(path, route.PathPrefix) {
+			allowed = route.AllowedOrigins
+			break
+		}
 	}
-	if path == "/favicon.ico" || path == "/assets/chart.js" || path == "/assets/main.css" {
-		fasthttp.ServeFile(ctx, "./public"+path)
+
+	origin := string(ctx.Request.Header.Peek("origin"))
+	wildcard := len(allowed) == 0
+	for _, a := range allowed {
+		if a == "*" {
+			wildcard = true
+			continue
+		}
+		if origin != "" && a == origin {
+			return origin, true
+		}
+	}
+	if wildcard {
+		return "*", false
+	}
+	return "", false
+}
+
+// Use registers a middleware; it must be called before the server starts
+// accepting requests
+func (h *handler) Use(m Middleware) {
+	h.middlewares = append(h.middlewares, m)
+}
+
+func (h *handler) Handle(ctx *fasthttp.RequestCtx) {
+	h.handle(ctx, h.router)
+}
+
+// HandlePublic serves only the data-plane routes; management endpoints 404
+// here so the public port exposes nothing an operator token guards.
+func (h *handler) HandlePublic(ctx *fasthttp.RequestCtx) {
+	h.handle(ctx, h.publicRouter)
+}
+
+// HandleAdmin serves only the management routes, meant for a listener bound
+// to an internal port or interface.
+func (h *handler) HandleAdmin(ctx *fasthttp.RequestCtx) {
+	h.handle(ctx, h.adminRouter)
+}
+
+func (h *handler) handle(ctx *fasthttp.RequestCtx, r *router) {
+	start := time.Now()
+	ctx.SetUserValue(userValueRequestID, requestID(ctx))
+	ctx.SetUserValue(userValueClientIP, h.resolveClientIP(ctx).String())
+	ctx.Response.Header.Set(headerRequestID, requestIDFromUserValue(ctx))
+	defer logAccess(ctx, start)
+
+	if err := h.firewall.check(ctx); err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetContentType("application/json")
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
 		return
 	}
-	if strings.HasPrefix(path, pathPubSubs) {
-		h.handlePubSub(ctx)
+
+	for _, m := range h.middlewares {
+		if err := m(ctx); err != nil {
+			msg, code := errmapper.FromErrorToHttpResponse(err)
+			ctx.SetContentType("application/json")
+			ctx.SetStatusCode(code)
+			ctx.SetBody(msg)
+			return
+		}
+	}
+
+	path := string(ctx.Path())
+	if asset := assetName(path); asset != "" {
+		h.serveAsset(ctx, asset)
 		return
 	}
-	if strings.HasPrefix(path, pathMetrics) {
-		h.handleMetrics(ctx)
+	if label, ok := r.serve(ctx); ok {
+		h.requests.record(label, ctx.Response.StatusCode())
 		return
 	}
 	notfound(ctx)
@@ -86,89 +332,214 @@ func badrequest(ctx *fasthttp.RequestCtx) {
 }
 
 func (h *handler) allowOrigin(ctx *fasthttp.RequestCtx) {
-	origin := string(ctx.Request.Header.Peek("origin"))
+	origin, allowCredentials := h.resolveOrigin(ctx)
 	if origin == "" {
-		origin = "*"
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.Write([]byte{})
+		return
 	}
 	ctx.Response.Header.Set("access-control-allow-origin", origin)
-	ctx.Response.Header.Set("access-control-allow-methods", "GET, POST, PUT, DELETE, OPTIONS")
+	ctx.Response.Header.Set("access-control-allow-methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 	ctx.Response.Header.Set("access-control-allow-headers", "*")
-	ctx.Response.Header.Set("access-control-allow-credentials", "true")
+	if allowCredentials {
+		ctx.Response.Header.Set("access-control-allow-credentials", "true")
+	}
 	ctx.Response.Header.Set("access-control-max-Age", "86400")
 	ctx.SetStatusCode(fasthttp.StatusOK)
 	ctx.Write([]byte{})
 }
 
-func (h *handler) handleMetrics(ctx *fasthttp.RequestCtx) {
-	method := string(ctx.Method())
-	path := string(ctx.Path())
+func (h *handler) listPubSubs(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToListPubSubsRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
+	res, err := h.pubsub.ListPubSubs(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	body := pubsubmapper.FromListPubSubsResponseToHttpResponse(*res)
+
+	ctx.SetContentType("application/json")
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody(body)
+}
 
-	// Get /metrics
-	if path == pathMetrics && method == fasthttp.MethodGet {
-		h.getMetrics(ctx)
+func (h *handler) exportTopics(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToExportTopicsRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
 		return
 	}
 
-	notfound(ctx)
+	freshCtx := requestContext(ctx)
+	res, err := h.pubsub.ExportTopics(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	ctx.SetContentType("application/octet-stream")
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody(res.Bundle)
 }
 
-func (h *handler) handlePubSub(ctx *fasthttp.RequestCtx) {
-	method := string(ctx.Method())
-	path := string(ctx.Path())
-	path = strings.Replace(path, pathPubSubs, "", -1)
-	pathParts := strings.Split(path, "/")
-
-	// POST /pubsubs
-	if len(pathParts) == 1 {
-		switch method {
-		case fasthttp.MethodPost:
-			h.createPubSub(ctx)
-		default:
-			notfound(ctx)
-		}
+func (h *handler) importTopics(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToImportTopicsRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
 		return
 	}
 
-	// DELETE /pubsubs/:id
-	if len(pathParts) == 2 {
-		switch method {
-		case fasthttp.MethodDelete:
-			h.deletePubSub(ctx)
-		default:
-			notfound(ctx)
-		}
+	freshCtx := requestContext(ctx)
+	res, err := h.pubsub.ImportTopics(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
 		return
 	}
 
-	// POST /pubsubs/:id/events
-	// GET  /pubsubs/:id/events
-	if len(pathParts) == 3 && method == fasthttp.MethodPost {
-		switch pathParts[2] {
-		case "events":
-			h.publishToPubSub(ctx)
-		default:
-			notfound(ctx)
-		}
+	ctx.SetContentType("application/json")
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody(pubsubmapper.FromImportTopicsResponseToHttpResponse(*res))
+}
+
+// readyz is the unauthenticated readiness probe: it answers 200 while the
+// instance can serve and 503 once persistence stops responding, so
+// orchestrators drain traffic instead of routing into a dead store.
+func (h *handler) readyz(ctx *fasthttp.RequestCtx) {
+	ctx.SetContentType("application/json")
+	if err := h.pubsub.Ready(requestContext(ctx)); err != nil {
+		ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+		ctx.SetBody([]byte(`{"status": "unready"}`))
 		return
 	}
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody([]byte(`{"status": "ok"}`))
+}
 
-	if len(pathParts) == 3 && method == fasthttp.MethodGet {
-		switch pathParts[2] {
-		case "events":
-			h.subscribeToPubSub(ctx)
-		default:
-			notfound(ctx)
-		}
+func (h *handler) backupStore(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToBackupStoreRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
 		return
 	}
 
-	// OPTIONS /pubsubs/:id/events
-	if len(pathParts) == 3 && pathParts[2] == "events" && method == fasthttp.MethodOptions {
-		h.allowOrigin(ctx)
+	freshCtx := requestContext(ctx)
+	res, err := h.pubsub.BackupStore(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
 		return
 	}
 
-	notfound(ctx)
+	ctx.SetContentType("application/octet-stream")
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody(res.Data)
+}
+
+func (h *handler) upsertStaticPubSub(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToUpsertStaticPubSubRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	if err := validator.UpsertStaticPubSubRequest(*req); err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
+	err := h.pubsub.UpsertStaticPubSub(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+	ctx.SetBody([]byte{})
+}
+
+func (h *handler) deleteStaticPubSub(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToDeleteStaticPubSubRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
+	err := h.pubsub.DeleteStaticPubSub(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+	ctx.SetBody([]byte{})
+}
+
+func (h *handler) queryArchive(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToQueryArchiveRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
+	res, err := h.pubsub.QueryArchive(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	body := pubsubmapper.FromQueryArchiveResponseToHttpResponse(*res)
+
+	ctx.SetContentType("application/json")
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody(body)
+}
+
+func (h *handler) getSubscribers(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToGetSubscribersRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
+	res, err := h.pubsub.GetSubscribers(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	body := pubsubmapper.FromGetSubscribersResponseToHttpResponse(*res)
+
+	ctx.SetContentType("application/json")
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody(body)
 }
 
 func (h *handler) createPubSub(ctx *fasthttp.RequestCtx) {
@@ -178,7 +549,7 @@ func (h *handler) createPubSub(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	freshCtx := context.Background()
+	freshCtx := requestContext(ctx)
 	res, err := h.pubsub.Create(freshCtx, *req)
 	if err != nil {
 		msg, code := errmapper.FromErrorToHttpResponse(err)
@@ -200,7 +571,7 @@ func (h *handler) deletePubSub(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	freshCtx := context.Background()
+	freshCtx := requestContext(ctx)
 	err := h.pubsub.Delete(freshCtx, *req)
 	if err != nil {
 		msg, code := errmapper.FromErrorToHttpResponse(err)
@@ -213,14 +584,113 @@ func (h *handler) deletePubSub(ctx *fasthttp.RequestCtx) {
 	ctx.SetBody([]byte{})
 }
 
+func (h *handler) updatePubSub(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToUpdatePubSubRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	if err := validator.UpdatePubSubRequest(*req); err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
+	err := h.pubsub.Update(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+	ctx.SetBody([]byte{})
+}
+
+func (h *handler) pausePubSub(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToPausePubSubRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
+	err := h.pubsub.Pause(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+	ctx.SetBody([]byte{})
+}
+
+func (h *handler) resumePubSub(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToResumePubSubRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
+	err := h.pubsub.Resume(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+	ctx.SetBody([]byte{})
+}
+
 func (h *handler) publishToPubSub(ctx *fasthttp.RequestCtx) {
+	defer func(start time.Time) { h.publishLatency.Observe(time.Since(start)) }(time.Now())
+
+	if err := decompressPublishBody(ctx); err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	// reject oversize bodies before paying for the JSON decode; the
+	// controller enforces its own limit on the decoded message as well
+	if max := h.cfg.MaxEventSize; max > 0 && len(ctx.PostBody()) > max {
+		msg, code := errmapper.FromErrorToHttpResponse(entity.Err{
+			Code:    entity.ErrorCodeRequestEntityTooLarge,
+			Kind:    entity.ErrorKindMessageTooLarge,
+			Message: "event payload exceeds the maximum allowed size",
+			Details: map[string]any{
+				"maxEventSize": max,
+			},
+		})
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
 	req := pubsubmapper.FromHttpRequestToPublishRequest(ctx)
 	if req == nil {
 		badrequest(ctx)
 		return
 	}
 
-	freshCtx := context.Background()
+	if err := validator.PublishRequest(*req); err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
 	res, err := h.pubsub.Publish(freshCtx, *req)
 	if err != nil {
 		msg, code := errmapper.FromErrorToHttpResponse(err)
@@ -231,10 +701,166 @@ func (h *handler) publishToPubSub(ctx *fasthttp.RequestCtx) {
 
 	body := pubsubmapper.FromPublishResponseToHttpResponse(*res)
 
-	ctx.SetStatusCode(fasthttp.StatusCreated)
+	// a deduplicated publish reports the original event rather than a newly
+	// created one
+	if res.Duplicate {
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	} else {
+		ctx.SetStatusCode(fasthttp.StatusCreated)
+	}
 	ctx.SetBody(body)
 }
 
+func (h *handler) setSchema(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToSetSchemaRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
+	err := h.pubsub.SetSchema(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+	ctx.SetBody([]byte{})
+}
+
+func (h *handler) getSchema(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToGetSchemaRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
+	res, err := h.pubsub.GetSchema(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody(pubsubmapper.FromGetSchemaResponseToHttpResponse(*res))
+}
+
+func (h *handler) deleteSchema(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToDeleteSchemaRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
+	err := h.pubsub.DeleteSchema(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+	ctx.SetBody([]byte{})
+}
+
+// getUsage serves the usage rollups; ?format=csv switches the body to the
+// billing export.
+func (h *handler) getUsage(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToGetUsageRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
+	res, err := h.pubsub.GetUsage(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	if string(ctx.QueryArgs().Peek("format")) == "csv" {
+		ctx.SetContentType("text/csv; charset=utf-8")
+		ctx.SetStatusCode(fasthttp.StatusOK)
+		ctx.SetBody(pubsubmapper.FromGetUsageResponseToCsv(*res))
+		return
+	}
+
+	ctx.SetContentType("application/json")
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody(pubsubmapper.FromGetUsageResponseToHttpResponse(*res))
+}
+
+func (h *handler) setScript(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToSetScriptRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
+	err := h.pubsub.SetScript(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+	ctx.SetBody([]byte{})
+}
+
+func (h *handler) getScript(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToGetScriptRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
+	res, err := h.pubsub.GetScript(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody(pubsubmapper.FromGetScriptResponseToHttpResponse(*res))
+}
+
+func (h *handler) deleteScript(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToDeleteScriptRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
+	err := h.pubsub.DeleteScript(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+	ctx.SetBody([]byte{})
+}
+
 func (h *handler) subscribeToPubSub(ctx *fasthttp.RequestCtx) {
 	req := pubsubmapper.FromHttpRequestToSubscribeRequest(ctx)
 	if req == nil {
@@ -242,7 +868,7 @@ func (h *handler) subscribeToPubSub(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	freshCtx := context.Background()
+	freshCtx := requestContext(ctx)
 	res, err := h.pubsub.Subscribe(freshCtx, *req)
 	if err != nil {
 		msg, code := errmapper.FromErrorToHttpResponse(err)
@@ -251,25 +877,100 @@ func (h *handler) subscribeToPubSub(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	origin := string(ctx.Request.Header.Peek("origin"))
-	if origin == "" {
-		origin = "*"
+	// a Last-Event-ID means the client is resuming a previous stream rather
+	// than starting fresh; counted per topic so churn is visible
+	if req.LastEventID != "" {
+		h.streams.reconnected(req.PubSubID)
 	}
+
+	// NDJSON fallback for clients and proxies that can't speak SSE
+	if strings.Contains(string(ctx.Request.Header.Peek("Accept")), "application/x-ndjson") {
+		h.streamNDJSON(ctx, req, res)
+		return
+	}
+
+	origin, allowCredentials := h.resolveOrigin(ctx)
 	ctx.SetContentType("text/event-stream")
 	ctx.SetConnectionClose()
+	if res.ResumptionToken != "" {
+		ctx.Response.Header.Set(headerResumptionToken, res.ResumptionToken)
+	}
 	ctx.Response.Header.Set("cache-control", "no-cache")
 	ctx.Response.Header.Set("connection", "keep-alive")
 	ctx.Response.Header.Set("transfer-encoding", "chunked")
-	ctx.Response.Header.Set("access-control-allow-origin", origin)
-	ctx.Response.Header.Set("access-control-allow-headers", "cache-control")
-	ctx.Response.Header.Set("access-control-allow-credentials", "true")
+	if origin != "" {
+		ctx.Response.Header.Set("access-control-allow-origin", origin)
+		ctx.Response.Header.Set("access-control-allow-headers", "cache-control")
+		if allowCredentials {
+			ctx.Response.Header.Set("access-control-allow-credentials", "true")
+		}
+	}
 
 	ctx.SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
 		zlog.Info().Int64("id", res.ID).Dur("tickFrequency", res.TickFrequency).Msg("sse conn opened by user")
+		h.streams.opened(req.PubSubID)
+		defer h.streams.closed(req.PubSubID)
 		ticker := time.NewTicker(res.TickFrequency)
 		defer ticker.Stop()
+
+		// the retry hint is set up front so even clients dropped before any
+		// event arrives back off at the server's pace instead of the
+		// browser default
+		if res.ReconnectRetryHint > 0 {
+			fmt.Fprintf(w, "retry: %d\n\n", res.ReconnectRetryHint.Milliseconds())
+		}
+		// the handshake event tells the client who it is and what the stream
+		// supports before any topic event arrives
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", entity.EventTypeReady,
+			pubsubmapper.FromSubscribeResponseToReadyEventData(req.PubSubID, *res))
+		if res.ResumptionToken != "" {
+			fmt.Fprintf(w, "event: %s\ndata: {\"token\": \"%s\"}\n\n", entity.EventTypeResume, res.ResumptionToken)
+		}
+		if err := w.Flush(); err != nil {
+			zlog.Warn().Err(err).Int64("pubsubID", req.PubSubID).Msg("failed to flush handshake events")
+			err := h.pubsub.Unsubscribe(freshCtx, entity.UnsubscribeRequest{
+				PubSubID: req.PubSubID,
+				ID:       res.ID,
+				Token:    req.Token,
+			})
+			if err != nil {
+				zlog.Warn().Err(err).Int64("pubsubID", req.PubSubID).Int64("id", res.ID).Msg("failed to unsubscribe on handshake flush failure")
+			}
+			return
+		}
+		if res.MarkFlush != nil {
+			res.MarkFlush()
+		}
+
+		// streams with a max lifetime get asked to gracefully re-subscribe,
+		// spreading load over fresh connections after deploys
+		var lifetimeCh <-chan time.Time
+		if res.MaxStreamLifetime > 0 {
+			lifetime := time.NewTimer(res.MaxStreamLifetime)
+			defer lifetime.Stop()
+			lifetimeCh = lifetime.C
+		}
+
 		for {
 			select {
+			case <-lifetimeCh:
+				zlog.Info().Int64("pubsubID", req.PubSubID).Int64("id", res.ID).Msg("sse conn hit max lifetime; asking client to re-subscribe")
+				if res.ReconnectRetryHint > 0 {
+					fmt.Fprintf(w, "retry: %d\n", res.ReconnectRetryHint.Milliseconds())
+				}
+				fmt.Fprintf(w, "event: %s\ndata: {\"reason\": \"max-lifetime\"}\n\n", entity.EventTypeReconnect)
+				if err := w.Flush(); err != nil {
+					zlog.Warn().Err(err).Int64("pubsubID", req.PubSubID).Msg("failed to flush on reconnect event")
+				}
+				err := h.pubsub.Unsubscribe(freshCtx, entity.UnsubscribeRequest{
+					PubSubID: req.PubSubID,
+					ID:       res.ID,
+					Token:    req.Token,
+				})
+				if err != nil {
+					zlog.Warn().Err(err).Int64("pubsubID", req.PubSubID).Int64("id", res.ID).Msg("failed to unsubscribe on max lifetime")
+				}
+				return
 			case <-ctx.Done():
 				zlog.Info().Int64("pubsubID", req.PubSubID).Int64("id", res.ID).Msg("sse conn closed by user")
 				err := h.pubsub.Unsubscribe(freshCtx, entity.UnsubscribeRequest{
@@ -282,8 +983,13 @@ func (h *handler) subscribeToPubSub(ctx *fasthttp.RequestCtx) {
 				}
 				return
 			case <-ticker.C:
-				// commenting for keep alive
-				fmt.Fprintf(w, ": {\"status\": \"tick\"}\n\n")
+				// standard SSE comment keepalive; the legacy event style stays
+				// available for clients that depended on tick events
+				if res.KeepaliveStyle == entity.KeepaliveStyleEvent {
+					fmt.Fprintf(w, "data: {\"status\": \"tick\"}\n\n")
+				} else {
+					fmt.Fprintf(w, ": ping\n\n")
+				}
 				if err := w.Flush(); err != nil {
 					zlog.Warn().Err(err).Int64("pubsubID", req.PubSubID).Msg("failed to flush on tick")
 					err := h.pubsub.Unsubscribe(freshCtx, entity.UnsubscribeRequest{
@@ -296,11 +1002,15 @@ func (h *handler) subscribeToPubSub(ctx *fasthttp.RequestCtx) {
 					}
 					return
 				}
+				if res.MarkFlush != nil {
+					res.MarkFlush()
+				}
 			case event, ok := <-res.Events:
 				if !ok {
 					zlog.Info().Int64("id", res.ID).Msg("sse conn closed")
 					// letting the client know about server closed the conn
-					fmt.Fprintf(w, "data: {\"status\": \"closed\"}\n\n")
+					// without a differentiated reason
+					fmt.Fprintf(w, "event: %s\ndata: {\"reason\": \"%s\"}\n\n", entity.EventTypeClose, entity.CloseReasonServerShutdown)
 					if err := w.Flush(); err != nil {
 						zlog.Warn().Err(err).Int64("pubsubID", req.PubSubID).Msg("failed to flush on closed event")
 						return
@@ -308,15 +1018,30 @@ func (h *handler) subscribeToPubSub(ctx *fasthttp.RequestCtx) {
 					return
 				}
 
-				// check id not null and has value
-				if len(event.ID) > 0 {
-					fmt.Fprintf(w, "id: %s\n", event.ID)
+				if event.Type == entity.EventTypeClose {
+					zlog.Info().Int64("id", res.ID).Str("data", string(event.Data)).Msg("sse conn closing with reason")
+					fmt.Fprintf(w, "event: %s\ndata: %s\n\n", entity.EventTypeClose, string(event.Data))
+					if err := w.Flush(); err != nil {
+						zlog.Warn().Err(err).Int64("pubsubID", req.PubSubID).Msg("failed to flush on close event")
+					}
+					return
 				}
-				if len(event.Type) > 0 {
-					fmt.Fprintf(w, "event: %s\n", event.Type)
+
+				// coalesce whatever is already queued into one flush; the
+				// throughput profile additionally lingers for fuller batches
+				linger := time.Duration(0)
+				if res.Profile == entity.ProfileThroughput {
+					linger = throughputLinger
 				}
-				fmt.Fprintf(w, "data: %s\n\n", string(event.Data))
-				if err := w.Flush(); err != nil {
+				result, batched := writeEventBatch(w, event, res.Events, linger)
+				if result == batchCloseEvent {
+					if err := w.Flush(); err != nil {
+						zlog.Warn().Err(err).Int64("pubsubID", req.PubSubID).Msg("failed to flush on close event")
+					}
+					return
+				}
+
+				if err := flushBatch(w, h.streams, req.PubSubID, res.ID, batched); err != nil {
 					zlog.Error().Err(err).Int64("pubsubID", req.PubSubID).Msg("failed to flush on event")
 					err := h.pubsub.Unsubscribe(freshCtx, entity.UnsubscribeRequest{
 						PubSubID: req.PubSubID,
@@ -328,11 +1053,169 @@ func (h *handler) subscribeToPubSub(ctx *fasthttp.RequestCtx) {
 					}
 					return
 				}
+				if res.MarkFlush != nil {
+					res.MarkFlush()
+				}
+
+				// the first event of the batch is the oldest, so its
+				// publish-to-flush time bounds the whole batch
+				if !event.PublishedAt.IsZero() {
+					h.flushLatency.Observe(time.Since(event.PublishedAt))
+				}
 			}
 		}
 	}))
 }
 
+// streamNDJSON streams events as one JSON envelope per line; keepalive ticks
+// are written as empty lines which NDJSON consumers are expected to skip
+func (h *handler) streamNDJSON(ctx *fasthttp.RequestCtx, req *entity.SubscribeRequest, res *entity.SubscribeResponse) {
+	ctx.SetContentType("application/x-ndjson")
+	ctx.SetConnectionClose()
+	ctx.Response.Header.Set("cache-control", "no-cache")
+	ctx.Response.Header.Set("transfer-encoding", "chunked")
+
+	freshCtx := requestContext(ctx)
+	unsubscribe := func() {
+		err := h.pubsub.Unsubscribe(freshCtx, entity.UnsubscribeRequest{
+			PubSubID: req.PubSubID,
+			ID:       res.ID,
+			Token:    req.Token,
+		})
+		if err != nil {
+			zlog.Warn().Err(err).Int64("pubsubID", req.PubSubID).Int64("id", res.ID).Msg("failed to unsubscribe ndjson stream")
+		}
+	}
+
+	ctx.SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		zlog.Info().Int64("id", res.ID).Msg("ndjson conn opened by user")
+		h.streams.opened(req.PubSubID)
+		defer h.streams.closed(req.PubSubID)
+		ticker := time.NewTicker(res.TickFrequency)
+		defer ticker.Stop()
+
+		var lifetimeCh <-chan time.Time
+		if res.MaxStreamLifetime > 0 {
+			lifetime := time.NewTimer(res.MaxStreamLifetime)
+			defer lifetime.Stop()
+			lifetimeCh = lifetime.C
+		}
+
+		for {
+			select {
+			case <-lifetimeCh:
+				zlog.Info().Int64("pubsubID", req.PubSubID).Int64("id", res.ID).Msg("ndjson conn hit max lifetime")
+				unsubscribe()
+				return
+			case <-ctx.Done():
+				zlog.Info().Int64("pubsubID", req.PubSubID).Int64("id", res.ID).Msg("ndjson conn closed by user")
+				unsubscribe()
+				return
+			case <-ticker.C:
+				fmt.Fprint(w, "\n")
+				if err := w.Flush(); err != nil {
+					zlog.Warn().Err(err).Int64("pubsubID", req.PubSubID).Msg("failed to flush ndjson tick")
+					unsubscribe()
+					return
+				}
+				if res.MarkFlush != nil {
+					res.MarkFlush()
+				}
+			case event, ok := <-res.Events:
+				if !ok {
+					zlog.Info().Int64("id", res.ID).Msg("ndjson conn closed")
+					return
+				}
+				fmt.Fprintf(w, "%s\n", string(event.Data))
+				h.streams.wrote(req.PubSubID, w.Buffered())
+				if err := w.Flush(); err != nil {
+					zlog.Error().Err(err).Int64("pubsubID", req.PubSubID).Msg("failed to flush ndjson event")
+					unsubscribe()
+					return
+				}
+				if res.MarkFlush != nil {
+					res.MarkFlush()
+				}
+				if event.Type == entity.EventTypeClose {
+					return
+				}
+			}
+		}
+	}))
+}
+
+func (h *handler) pollPubSub(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToPollRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	res, err := h.pubsub.Poll(ctx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	body := pubsubmapper.FromPollResponseToHttpResponse(*res)
+
+	ctx.SetContentType("application/json")
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody(body)
+}
+
+// writeEventFrame writes a single SSE frame without flushing so callers can
+// choose the flush strategy per latency profile; it writes straight into the
+// stream's bufio.Writer rather than formatting through fmt, since this is the
+// hottest write in the server
+func writeEventFrame(w *bufio.Writer, event *entity.Event) {
+	if len(event.ID) > 0 {
+		w.WriteString("id: ")
+		w.WriteString(event.ID)
+		w.WriteByte('\n')
+	}
+	if len(event.Type) > 0 {
+		w.WriteString("event: ")
+		w.WriteString(event.Type)
+		w.WriteByte('\n')
+	}
+	w.WriteString("data: ")
+	w.Write(event.Data)
+	w.WriteString("\n\n")
+}
+
+func (h *handler) getTopicRates(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToGetTopicRatesRequest(ctx)
+	if req == nil {
+		badrequest(ctx)
+		return
+	}
+
+	freshCtx := requestContext(ctx)
+	res, err := h.pubsub.GetTopicRates(freshCtx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	body := pubsubmapper.FromGetTopicRatesResponseToHttpResponse(*res)
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody(body)
+}
+
+// listErrorCatalog documents the stable error kinds so SDK authors don't have
+// to reverse-engineer them from responses; the body is static per build
+func (h *handler) listErrorCatalog(ctx *fasthttp.RequestCtx) {
+	ctx.SetContentType("application/json")
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody(errmapper.FromErrorCatalogToHttpResponse(entity.ErrorCatalog()))
+}
+
 func (h *handler) getMetrics(ctx *fasthttp.RequestCtx) {
 	req := pubsubmapper.FromHttpRequestToGetMetricsRequest(ctx)
 	if req == nil {
@@ -340,7 +1223,7 @@ func (h *handler) getMetrics(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	freshCtx := context.Background()
+	freshCtx := requestContext(ctx)
 	res, err := h.pubsub.GetMetrics(freshCtx, *req)
 	if err != nil {
 		msg, code := errmapper.FromErrorToHttpResponse(err)
@@ -349,6 +1232,20 @@ func (h *handler) getMetrics(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	res.Metrics = append(res.Metrics, h.requests.snapshot()...)
+	res.Metrics = append(res.Metrics, h.streams.snapshot()...)
+	res.Metrics = append(res.Metrics, h.publishLatency.Snapshot()...)
+	res.Metrics = append(res.Metrics, h.flushLatency.Snapshot()...)
+
+	// connection saturation against the RLIMIT_NOFILE-derived ceiling
+	if open, limit, rejected := server.ConnectionStats(); limit > 0 {
+		res.Metrics = append(res.Metrics,
+			entity.Metric{Name: "server.open_connections", Value: float64(open)},
+			entity.Metric{Name: "server.max_connections", Value: float64(limit)},
+			entity.Metric{Name: "server.rejected_connections", Value: float64(rejected)},
+		)
+	}
+
 	body := pubsubmapper.FromGetMetricsResponseToHttpResponse(*res)
 
 	ctx.SetStatusCode(fasthttp.StatusOK)
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"github.com/hasmcp/sser/internal/_data/entity"
+	errmapper "github.com/hasmcp/sser/internal/mapper/err"
+	pubsubmapper "github.com/hasmcp/sser/internal/mapper/pubsub"
+	"github.com/valyala/fasthttp"
+)
+
+// The v2 tree serves the same controller surface as v1 so the two stay in
+// lockstep; only the wire shapes that needed to evolve differ. Poll responses
+// carry each event as its structured envelope instead of re-wrapping it, the
+// history cursor is an opaque token instead of a bare number, and fallback
+// errors carry the stable kind field like every mapped error does. v1 keeps
+// its own mappers untouched, so deployed EventSource clients never see the
+// difference.
+const (
+	pathBaseV2          string = "/api/v2"
+	pathPubSubsV2       string = pathBaseV2 + "/pubsubs"
+	pathPubSubsByNameV2 string = pathPubSubsV2 + "/by-name"
+)
+
+// registerRoutesV2 declares the v2 surface through the same add function the
+// v1 routes use, so each route lands on the right public/admin router.
+func (h *handler) registerRoutesV2(add func(adminOnly bool, method, name, pattern string, handle func(ctx *fasthttp.RequestCtx))) {
+	add(true, fasthttp.MethodPost, "v2_create", pathPubSubsV2, h.createPubSub)
+	// by-name aliases go first for the same shadowing reason as in v1
+	add(true, fasthttp.MethodDelete, "v2_delete_by_name", pathPubSubsByNameV2+"/:name", h.byName(h.deletePubSub))
+	add(true, fasthttp.MethodPatch, "v2_update_by_name", pathPubSubsByNameV2+"/:name", h.byName(h.updatePubSub))
+	add(true, fasthttp.MethodPost, "v2_pause_by_name", pathPubSubsByNameV2+"/:name/pause", h.byName(h.pausePubSub))
+	add(true, fasthttp.MethodPost, "v2_resume_by_name", pathPubSubsByNameV2+"/:name/resume", h.byName(h.resumePubSub))
+	add(true, fasthttp.MethodPut, "v2_set_schema_by_name", pathPubSubsByNameV2+"/:name/schema", h.byName(h.setSchema))
+	add(true, fasthttp.MethodGet, "v2_get_schema_by_name", pathPubSubsByNameV2+"/:name/schema", h.byName(h.getSchema))
+	add(true, fasthttp.MethodDelete, "v2_delete_schema_by_name", pathPubSubsByNameV2+"/:name/schema", h.byName(h.deleteSchema))
+	add(true, fasthttp.MethodPut, "v2_set_script_by_name", pathPubSubsByNameV2+"/:name/script", h.byName(h.setScript))
+	add(true, fasthttp.MethodGet, "v2_get_script_by_name", pathPubSubsByNameV2+"/:name/script", h.byName(h.getScript))
+	add(true, fasthttp.MethodDelete, "v2_delete_script_by_name", pathPubSubsByNameV2+"/:name/script", h.byName(h.deleteScript))
+	add(false, fasthttp.MethodPost, "v2_publish_by_name", pathPubSubsByNameV2+"/:name/events", h.byName(h.publishToPubSub))
+	add(false, fasthttp.MethodGet, "v2_subscribe_by_name", pathPubSubsByNameV2+"/:name/events", h.byName(h.subscribeToPubSub))
+	add(false, fasthttp.MethodGet, "v2_poll_by_name", pathPubSubsByNameV2+"/:name/events/poll", h.byName(h.pollPubSubV2))
+	add(false, fasthttp.MethodGet, "v2_rates_by_name", pathPubSubsByNameV2+"/:name/rates", h.byName(h.getTopicRates))
+	add(false, fasthttp.MethodGet, "v2_subscribers_by_name", pathPubSubsByNameV2+"/:name/subscribers", h.byName(h.getSubscribers))
+	add(true, fasthttp.MethodDelete, "v2_delete", pathPubSubsV2+"/:id", h.deletePubSub)
+	add(true, fasthttp.MethodPatch, "v2_update", pathPubSubsV2+"/:id", h.updatePubSub)
+	add(true, fasthttp.MethodPost, "v2_pause", pathPubSubsV2+"/:id/pause", h.pausePubSub)
+	add(true, fasthttp.MethodPost, "v2_resume", pathPubSubsV2+"/:id/resume", h.resumePubSub)
+	add(true, fasthttp.MethodPut, "v2_set_schema", pathPubSubsV2+"/:id/schema", h.setSchema)
+	add(true, fasthttp.MethodGet, "v2_get_schema", pathPubSubsV2+"/:id/schema", h.getSchema)
+	add(true, fasthttp.MethodDelete, "v2_delete_schema", pathPubSubsV2+"/:id/schema", h.deleteSchema)
+	add(true, fasthttp.MethodPut, "v2_set_script", pathPubSubsV2+"/:id/script", h.setScript)
+	add(true, fasthttp.MethodGet, "v2_get_script", pathPubSubsV2+"/:id/script", h.getScript)
+	add(true, fasthttp.MethodDelete, "v2_delete_script", pathPubSubsV2+"/:id/script", h.deleteScript)
+	add(false, fasthttp.MethodPost, "v2_publish", pathPubSubsV2+"/:id/events", h.publishToPubSub)
+	add(false, fasthttp.MethodGet, "v2_subscribe", pathPubSubsV2+"/:id/events", h.subscribeToPubSub)
+	add(false, fasthttp.MethodGet, "v2_poll", pathPubSubsV2+"/:id/events/poll", h.pollPubSubV2)
+	add(false, fasthttp.MethodGet, "v2_rates", pathPubSubsV2+"/:id/rates", h.getTopicRates)
+	add(false, fasthttp.MethodGet, "v2_subscribers", pathPubSubsV2+"/:id/subscribers", h.getSubscribers)
+	add(true, fasthttp.MethodGet, "v2_metrics", pathBaseV2+"/metrics", h.getMetrics)
+	add(true, fasthttp.MethodGet, "v2_usage", pathBaseV2+"/usage", h.getUsage)
+	add(false, fasthttp.MethodGet, "v2_errors", pathBaseV2+"/errors", h.listErrorCatalog)
+	add(true, fasthttp.MethodGet, "v2_admin_pubsubs", pathBaseV2+"/admin/pubsubs", h.listPubSubs)
+	add(true, fasthttp.MethodGet, "v2_admin_export", pathBaseV2+"/admin/export", h.exportTopics)
+	add(true, fasthttp.MethodPost, "v2_admin_import", pathBaseV2+"/admin/import", h.importTopics)
+	add(true, fasthttp.MethodGet, "v2_admin_backup", pathBaseV2+"/admin/backup", h.backupStore)
+	add(true, fasthttp.MethodPut, "v2_static_upsert", pathBaseV2+"/admin/static-pubsubs", h.upsertStaticPubSub)
+	add(true, fasthttp.MethodDelete, "v2_static_delete", pathBaseV2+"/admin/static-pubsubs/:id", h.deleteStaticPubSub)
+	add(true, fasthttp.MethodGet, "v2_archive_query", pathBaseV2+"/admin/archive/:id", h.queryArchive)
+}
+
+// pollPubSubV2 serves cursor-based history in the v2 shape: each event comes
+// back as its structured envelope and the cursor is an opaque token.
+func (h *handler) pollPubSubV2(ctx *fasthttp.RequestCtx) {
+	req := pubsubmapper.FromHttpRequestToPollRequestV2(ctx)
+	if req == nil {
+		badrequestV2(ctx)
+		return
+	}
+
+	res, err := h.pubsub.Poll(ctx, *req)
+	if err != nil {
+		msg, code := errmapper.FromErrorToHttpResponse(err)
+		ctx.SetStatusCode(code)
+		ctx.SetBody(msg)
+		return
+	}
+
+	body := pubsubmapper.FromPollResponseToHttpResponseV2(*res)
+
+	ctx.SetContentType("application/json")
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody(body)
+}
+
+// badrequestV2 rejects a malformed request with the same kind-bearing error
+// shape every mapped error uses, instead of the bare v1 literal.
+func badrequestV2(ctx *fasthttp.RequestCtx) {
+	ctx.SetConnectionClose()
+	ctx.SetContentType("application/json")
+	ctx.SetStatusCode(fasthttp.StatusBadRequest)
+	ctx.SetBody(errmapper.FromErrorEntityToHttpResponse(entity.Err{
+		Code:    entity.ErrorCodeBadRequest,
+		Kind:    entity.ErrorKindBadRequest,
+		Message: "Invalid request payload",
+	}))
+}
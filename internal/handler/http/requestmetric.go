@@ -0,0 +1,50 @@
+package http
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+)
+
+type (
+	// requestMetrics counts responses by route and status so error rates are
+	// visible without log scraping.
+	requestMetrics struct {
+		mutex sync.RWMutex
+		vals  map[string]int64
+	}
+)
+
+func newRequestMetrics() *requestMetrics {
+	return &requestMetrics{
+		vals: make(map[string]int64),
+	}
+}
+
+// record bumps both the exact status counter and its status-class counter for
+// the route (e.g. requests.publish.401 and requests.publish.4xx)
+func (m *requestMetrics) record(route string, status int) {
+	exact := fmt.Sprintf("requests.%s.%d", route, status)
+	class := fmt.Sprintf("requests.%s.%dxx", route, status/100)
+
+	m.mutex.Lock()
+	m.vals[exact]++
+	m.vals[class]++
+	m.mutex.Unlock()
+}
+
+// snapshot flattens the counters into metric entities
+func (m *requestMetrics) snapshot() []entity.Metric {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	out := make([]entity.Metric, 0, len(m.vals))
+	for k, v := range m.vals {
+		out = append(out, entity.Metric{
+			Name:  k,
+			Value: float64(v),
+		})
+	}
+	return out
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+type (
+	// router dispatches requests on method plus path pattern. Patterns are
+	// split into literal and `:param` segments at registration time; matched
+	// params are stored as request user values so mappers can read them
+	// without re-splitting the path. A path that matches with the wrong
+	// method yields a 405 with an Allow header instead of a 404.
+	router struct {
+		routes []*route
+		// preflight serves OPTIONS requests whose path matches a registered
+		// route but has no explicit OPTIONS registration, so every endpoint
+		// answers CORS preflights without wiring them one by one
+		preflight func(ctx *fasthttp.RequestCtx)
+	}
+
+	route struct {
+		method   string
+		name     string
+		segments []segment
+		handle   func(ctx *fasthttp.RequestCtx)
+	}
+
+	// segment is one path element: a literal to compare against, or a param
+	// name to capture.
+	segment struct {
+		literal string
+		param   string
+	}
+)
+
+var _httpPayloadMethodNotAllowed = []byte(`{"error": {"code": 405, "message": "Method not allowed"}}`)
+
+func newRouter() *router {
+	return &router{routes: make([]*route, 0)}
+}
+
+// add registers a handler under a method and pattern; name is the stable
+// label used for request metrics.
+func (r *router) add(method, name, pattern string, handle func(ctx *fasthttp.RequestCtx)) {
+	parts := splitPath(pattern)
+	segments := make([]segment, len(parts))
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") {
+			segments[i] = segment{param: part[1:]}
+			continue
+		}
+		segments[i] = segment{literal: part}
+	}
+	r.routes = append(r.routes, &route{
+		method:   method,
+		name:     name,
+		segments: segments,
+		handle:   handle,
+	})
+}
+
+// serve routes the request; the returned label feeds request metrics. A false
+// return means no registered pattern matched the path at all.
+func (r *router) serve(ctx *fasthttp.RequestCtx) (string, bool) {
+	method := string(ctx.Method())
+	parts := splitPath(string(ctx.Path()))
+
+	var allowed []string
+	for _, rt := range r.routes {
+		if !rt.match(parts) {
+			continue
+		}
+		if rt.method != method {
+			allowed = append(allowed, rt.method)
+			continue
+		}
+		for i, seg := range rt.segments {
+			if seg.param != "" {
+				ctx.SetUserValue(seg.param, parts[i])
+			}
+		}
+		rt.handle(ctx)
+		return rt.name, true
+	}
+
+	if len(allowed) > 0 {
+		if method == fasthttp.MethodOptions && r.preflight != nil {
+			r.preflight(ctx)
+			return "preflight", true
+		}
+		methodnotallowed(ctx, allowed)
+		return "method_not_allowed", true
+	}
+	return "", false
+}
+
+func (rt *route) match(parts []string) bool {
+	if len(parts) != len(rt.segments) {
+		return false
+	}
+	for i, seg := range rt.segments {
+		if seg.param != "" {
+			continue
+		}
+		if seg.literal != parts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func methodnotallowed(ctx *fasthttp.RequestCtx, allowed []string) {
+	ctx.Response.Header.Set("Allow", strings.Join(allowed, ", "))
+	ctx.SetContentType("application/json")
+	ctx.SetStatusCode(fasthttp.StatusMethodNotAllowed)
+	ctx.SetBody(_httpPayloadMethodNotAllowed)
+}
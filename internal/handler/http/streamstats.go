@@ -0,0 +1,115 @@
+package http
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	"github.com/mustafaturan/monoflake"
+)
+
+type (
+	// topicStreamStats tracks one topic's live connection data; counters are
+	// cumulative while open is a gauge maintained by the stream writers.
+	topicStreamStats struct {
+		open       int64
+		bytes      int64
+		reconnects int64
+	}
+
+	// streamStats aggregates connection-level data across all streams, so
+	// capacity planning can lean on real connections and bytes on the wire
+	// instead of the controller's subscriber counters alone.
+	streamStats struct {
+		open   int64
+		mutex  sync.RWMutex
+		topics map[int64]*topicStreamStats
+	}
+)
+
+func newStreamStats() *streamStats {
+	return &streamStats{
+		topics: make(map[int64]*topicStreamStats),
+	}
+}
+
+// topic returns the per-topic record, creating it on first use
+func (s *streamStats) topic(pubsubID int64) *topicStreamStats {
+	s.mutex.RLock()
+	t := s.topics[pubsubID]
+	s.mutex.RUnlock()
+	if t != nil {
+		return t
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if t = s.topics[pubsubID]; t == nil {
+		t = &topicStreamStats{}
+		s.topics[pubsubID] = t
+	}
+	return t
+}
+
+// opened bumps the global and per-topic open-stream gauges when a stream
+// writer starts serving a connection
+func (s *streamStats) opened(pubsubID int64) {
+	if s == nil {
+		return
+	}
+	atomic.AddInt64(&s.open, 1)
+	atomic.AddInt64(&s.topic(pubsubID).open, 1)
+}
+
+// closed is the counterpart of opened; stream writers defer it so the gauges
+// stay honest on every exit path
+func (s *streamStats) closed(pubsubID int64) {
+	if s == nil {
+		return
+	}
+	atomic.AddInt64(&s.open, -1)
+	atomic.AddInt64(&s.topic(pubsubID).open, -1)
+}
+
+// wrote accounts n bytes flushed to one of the topic's subscribers
+func (s *streamStats) wrote(pubsubID int64, n int) {
+	if s == nil || n <= 0 {
+		return
+	}
+	atomic.AddInt64(&s.topic(pubsubID).bytes, int64(n))
+}
+
+// reconnected counts a client resuming a previous stream, which is how churn
+// from deploys and flaky networks becomes visible
+func (s *streamStats) reconnected(pubsubID int64) {
+	if s == nil {
+		return
+	}
+	atomic.AddInt64(&s.topic(pubsubID).reconnects, 1)
+}
+
+// snapshot flattens the gauges and counters into metric entities; topic ids
+// are rendered in base62 to match the public API
+func (s *streamStats) snapshot() []entity.Metric {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	out := make([]entity.Metric, 0, len(s.topics)*3+1)
+	out = append(out, entity.Metric{
+		Name:  "streams.open",
+		Value: float64(atomic.LoadInt64(&s.open)),
+	})
+	for id, t := range s.topics {
+		prefix := "streams." + monoflake.ID(id).String()
+		out = append(out,
+			entity.Metric{Name: prefix + ".open", Value: float64(atomic.LoadInt64(&t.open))},
+			entity.Metric{Name: prefix + ".bytes_written", Value: float64(atomic.LoadInt64(&t.bytes))},
+			entity.Metric{Name: prefix + ".reconnects", Value: float64(atomic.LoadInt64(&t.reconnects))},
+		)
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"bufio"
+	"fmt"
+	"time"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	zlog "github.com/rs/zerolog/log"
+)
+
+const (
+	// maxEventBatch bounds how many queued events are coalesced into a single
+	// flush so a deep subscriber queue cannot starve the keepalive tick
+	maxEventBatch = 128
+
+	// slowWriteThreshold flags subscribers whose connection cannot keep up
+	// with the topic before their queue backs up far enough to drop events
+	slowWriteThreshold = 250 * time.Millisecond
+
+	// throughputLinger is how long throughput-profile streams wait for more
+	// events before flushing a partially filled batch
+	throughputLinger = 2 * time.Millisecond
+)
+
+// batchResult tells the stream loop how a batch of events ended.
+type batchResult int
+
+const (
+	// batchFlushable means the batch is written and ready to flush
+	batchFlushable batchResult = iota
+	// batchCloseEvent means a close event ended the batch; the stream should
+	// flush and terminate
+	batchCloseEvent
+)
+
+// writeEventBatch writes first plus whatever is already queued — up to
+// maxEventBatch frames — without flushing, so the whole batch goes out in a
+// single syscall. A positive linger keeps the batch open briefly once the
+// queue runs dry, trading a little latency for fuller batches.
+func writeEventBatch(w *bufio.Writer, first *entity.Event, events chan *entity.Event, linger time.Duration) (batchResult, int) {
+	writeEventFrame(w, first)
+	batched := 1
+
+	var lingerCh <-chan time.Time
+	if linger > 0 {
+		timer := time.NewTimer(linger)
+		defer timer.Stop()
+		lingerCh = timer.C
+	}
+
+	for batched < maxEventBatch {
+		var event *entity.Event
+		var ok bool
+		if lingerCh != nil {
+			select {
+			case event, ok = <-events:
+			case <-lingerCh:
+				return batchFlushable, batched
+			}
+		} else {
+			select {
+			case event, ok = <-events:
+			default:
+				return batchFlushable, batched
+			}
+		}
+		if !ok {
+			// channel closed mid-batch; the outer loop picks it up after the
+			// flush
+			return batchFlushable, batched
+		}
+		if event.Type == entity.EventTypeClose {
+			zlog.Info().Str("data", string(event.Data)).Msg("sse conn closing with reason")
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", entity.EventTypeClose, string(event.Data))
+			return batchCloseEvent, batched
+		}
+		writeEventFrame(w, event)
+		batched++
+	}
+	return batchFlushable, batched
+}
+
+// flushBatch flushes the writer and measures how long the write takes, so
+// slow subscribers show up in the logs before their queues overflow; the
+// buffered size is accounted against the topic's bytes-written gauge
+func flushBatch(w *bufio.Writer, stats *streamStats, pubsubID, subscriberID int64, batched int) error {
+	stats.wrote(pubsubID, w.Buffered())
+	start := time.Now()
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if latency := time.Since(start); latency > slowWriteThreshold {
+		zlog.Warn().
+			Int64("pubsubID", pubsubID).
+			Int64("id", subscriberID).
+			Int("batched", batched).
+			Dur("writeLatency", latency).
+			Msg("slow sse write; subscriber connection is not keeping up")
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package mqtt
+
+import (
+	"context"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/hasmcp/sser/internal/_data/entity"
+	"github.com/hasmcp/sser/internal/controller/pubsub"
+	"github.com/hasmcp/sser/internal/servicer/config"
+	zlog "github.com/rs/zerolog/log"
+)
+
+type (
+	// Handler bridges MQTT topics into sser pubsubs so that IoT publishers
+	// can feed SSE subscribers without speaking authenticated HTTP.
+	Handler interface {
+		Start() error
+		Stop() error
+	}
+
+	handler struct {
+		cfg    mqttConfig
+		pubsub pubsub.Controller
+		client paho.Client
+	}
+
+	Params struct {
+		Config config.Servicer
+		PubSub pubsub.Controller
+	}
+
+	mqttConfig struct {
+		Enabled        bool          `yaml:"enabled"`
+		BrokerURL      string        `yaml:"brokerUrl"`
+		ClientID       string        `yaml:"clientId"`
+		Username       string        `yaml:"username"`
+		Password       string        `yaml:"password"`
+		QOS            byte          `yaml:"qos"`
+		ConnectTimeout time.Duration `yaml:"connectTimeout"`
+		ApiAccessToken string        `yaml:"apiAccessToken"`
+		Routes         []RouteConfig `yaml:"routes"`
+	}
+
+	RouteConfig struct {
+		Topic    string `yaml:"topic"`
+		PubSubID int64  `yaml:"pubsubId"`
+	}
+
+	err string
+)
+
+const (
+	cfgKey = "mqtt"
+
+	logPrefix = "[mqtt] "
+
+	ErrNotEnabled     err = "mqtt is not enabled"
+	errConnectTimeout err = "mqtt connect timed out"
+)
+
+func New(p Params) (Handler, error) {
+	var cfg mqttConfig
+	err := p.Config.Populate(cfgKey, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cfg.Enabled {
+		return nil, ErrNotEnabled
+	}
+
+	if cfg.ConnectTimeout <= 0 {
+		cfg.ConnectTimeout = 10 * time.Second
+	}
+
+	opts := paho.NewClientOptions().
+		AddBroker(cfg.BrokerURL).
+		SetClientID(cfg.ClientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetConnectTimeout(cfg.ConnectTimeout).
+		SetAutoReconnect(true)
+
+	h := &handler{
+		cfg:    cfg,
+		pubsub: p.PubSub,
+	}
+	h.client = paho.NewClient(opts)
+
+	return h, nil
+}
+
+func (h *handler) Start() error {
+	token := h.client.Connect()
+	if !token.WaitTimeout(h.cfg.ConnectTimeout) {
+		return errConnectTimeout
+	}
+	if err := token.Error(); err != nil {
+		return err
+	}
+
+	for _, r := range h.cfg.Routes {
+		route := r
+		token := h.client.Subscribe(route.Topic, h.cfg.QOS, func(_ paho.Client, msg paho.Message) {
+			h.republish(route.PubSubID, msg)
+		})
+		token.Wait()
+		if err := token.Error(); err != nil {
+			return err
+		}
+		zlog.Info().Str("topic", route.Topic).Int64("pubsubId", route.PubSubID).Msg(logPrefix + "route subscribed")
+	}
+
+	zlog.Info().Str("broker", h.cfg.BrokerURL).Msg(logPrefix + "connected")
+	return nil
+}
+
+func (h *handler) Stop() error {
+	h.client.Disconnect(uint(h.cfg.ConnectTimeout.Milliseconds()))
+	return nil
+}
+
+func (h *handler) republish(pubsubID int64, msg paho.Message) {
+	_, err := h.pubsub.Publish(context.Background(), entity.PublishRequest{
+		ApiAccessToken: h.cfg.ApiAccessToken,
+		PubSubID:       pubsubID,
+		EventType:      msg.Topic(),
+		Message:        msg.Payload(),
+	})
+	if err != nil {
+		zlog.Error().Err(err).Str("topic", msg.Topic()).Int64("pubsubId", pubsubID).
+			Msg(logPrefix + "failed to republish mqtt message to pubsub")
+	}
+}
+
+func (e err) Error() string {
+	return string(e)
+}
@@ -0,0 +1,111 @@
+// Package histogram provides a fixed-bucket latency histogram cheap enough
+// for hot paths: observations are lock-free atomic increments, and snapshots
+// expose the count, mean, and approximate quantiles as plain metrics.
+// Counters alone can't reveal slow-subscriber tail latency, which is what
+// these exist to surface.
+package histogram
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+)
+
+// _bounds are the bucket upper bounds; the log-ish spacing covers sub-ms
+// publish paths up to multi-second stalled subscribers.
+var _bounds = []time.Duration{
+	500 * time.Microsecond,
+	time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+}
+
+// Histogram counts duration observations into fixed buckets; the zero value
+// is not usable, construct with New.
+type Histogram struct {
+	name    string
+	count   int64
+	sum     int64 // nanoseconds
+	buckets []int64
+}
+
+// New returns a histogram whose snapshot metrics are prefixed with the given
+// name (e.g. "latency.publish").
+func New(name string) *Histogram {
+	return &Histogram{
+		name:    name,
+		buckets: make([]int64, len(_bounds)+1),
+	}
+}
+
+// Observe records one duration; a nil histogram is a no-op so callers can
+// leave instrumentation unconditional.
+func (h *Histogram) Observe(d time.Duration) {
+	if h == nil {
+		return
+	}
+	atomic.AddInt64(&h.count, 1)
+	atomic.AddInt64(&h.sum, int64(d))
+	i := 0
+	for i < len(_bounds) && d > _bounds[i] {
+		i++
+	}
+	atomic.AddInt64(&h.buckets[i], 1)
+}
+
+// Snapshot renders the histogram as metrics: total count, mean, and the
+// approximate p50/p95/p99 (reported as the upper bound of the bucket holding
+// the quantile).
+func (h *Histogram) Snapshot() []entity.Metric {
+	if h == nil {
+		return nil
+	}
+
+	count := atomic.LoadInt64(&h.count)
+	sum := atomic.LoadInt64(&h.sum)
+	buckets := make([]int64, len(h.buckets))
+	for i := range h.buckets {
+		buckets[i] = atomic.LoadInt64(&h.buckets[i])
+	}
+
+	avg := 0.0
+	if count > 0 {
+		avg = float64(sum) / float64(count) / float64(time.Millisecond)
+	}
+
+	return []entity.Metric{
+		{Name: h.name + ".count", Value: float64(count)},
+		{Name: h.name + ".avg_ms", Value: avg},
+		{Name: h.name + ".p50_ms", Value: quantile(buckets, count, 0.50)},
+		{Name: h.name + ".p95_ms", Value: quantile(buckets, count, 0.95)},
+		{Name: h.name + ".p99_ms", Value: quantile(buckets, count, 0.99)},
+	}
+}
+
+func quantile(buckets []int64, count int64, q float64) float64 {
+	if count == 0 {
+		return 0
+	}
+	rank := int64(q*float64(count-1)) + 1
+	cumulative := int64(0)
+	for i, c := range buckets {
+		cumulative += c
+		if cumulative >= rank {
+			if i < len(_bounds) {
+				return float64(_bounds[i]) / float64(time.Millisecond)
+			}
+			// overflow bucket: everything above the largest bound
+			return float64(_bounds[len(_bounds)-1]) / float64(time.Millisecond)
+		}
+	}
+	return float64(_bounds[len(_bounds)-1]) / float64(time.Millisecond)
+}
@@ -8,10 +8,15 @@ import (
 )
 
 func FromErrorEntityToErrorView(e entity.Err) view.Err {
+	kind := e.Kind
+	if kind == "" {
+		kind = entity.KindForCode(e.Code)
+	}
 	return view.Err{
 		Code:    int(e.Code),
+		Kind:    string(kind),
 		Message: e.Message,
-		Details: e.Details,
+		Details: redactDetails(e.Details),
 	}
 }
 
@@ -37,3 +42,20 @@ func FromErrorToHttpResponse(err error) ([]byte, int) {
 	}
 	return FromErrorEntityToHttpResponse(e), int(e.Code)
 }
+
+// FromErrorCatalogToHttpResponse renders the catalog of stable error kinds
+// for the documentation endpoint.
+func FromErrorCatalogToHttpResponse(catalog []entity.ErrorCatalogEntry) []byte {
+	res := view.ErrorCatalogResponse{
+		Errors: make([]view.ErrorCatalogEntry, 0, len(catalog)),
+	}
+	for _, e := range catalog {
+		res.Errors = append(res.Errors, view.ErrorCatalogEntry{
+			Kind:        string(e.Kind),
+			Status:      e.Status,
+			Description: e.Description,
+		})
+	}
+	payload, _ := json.Marshal(res)
+	return payload
+}
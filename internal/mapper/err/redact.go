@@ -0,0 +1,73 @@
+package err
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync/atomic"
+)
+
+// _verboseDetails controls whether sensitive detail values are rendered
+// as-is; it stays off everywhere except explicitly opted-in debugging
+// environments.
+var _verboseDetails int32
+
+// _sensitiveKeys are detail keys whose values never belong in a response
+// verbatim; controllers echo the presented credentials under these keys.
+var _sensitiveKeys = map[string]struct{}{
+	"token":              {},
+	"apiAccessToken":     {},
+	"metricsAccessToken": {},
+}
+
+// SetVerboseDetails toggles redaction off for debugging environments; call it
+// once during startup, before the server accepts requests.
+func SetVerboseDetails(verbose bool) {
+	v := int32(0)
+	if verbose {
+		v = 1
+	}
+	atomic.StoreInt32(&_verboseDetails, v)
+}
+
+// redactDetails returns a copy of the details with sensitive values replaced
+// by a short hash, so responses can still be correlated against a known
+// credential without ever echoing it back.
+func redactDetails(details map[string]any) map[string]any {
+	if details == nil || atomic.LoadInt32(&_verboseDetails) == 1 {
+		return details
+	}
+
+	redacted := false
+	out := details
+	for k, v := range details {
+		if _, ok := _sensitiveKeys[k]; !ok {
+			continue
+		}
+		if !redacted {
+			out = make(map[string]any, len(details))
+			for k2, v2 := range details {
+				out[k2] = v2
+			}
+			redacted = true
+		}
+		out[k] = redactSecret(v)
+	}
+	return out
+}
+
+func redactSecret(v any) string {
+	var raw []byte
+	switch s := v.(type) {
+	case string:
+		raw = []byte(s)
+	case []byte:
+		raw = s
+	default:
+		return "redacted"
+	}
+	if len(raw) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(raw)
+	return "sha256:" + hex.EncodeToString(sum[:])[:12]
+}
@@ -1,8 +1,12 @@
 package pubsub
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hasmcp/sser/internal/_data/entity"
 	"github.com/hasmcp/sser/internal/_data/view"
@@ -23,17 +27,96 @@ func FromHttpRequestToCreatePubSubRequest(ctx *fasthttp.RequestCtx) *entity.Crea
 	if err != nil {
 		return nil
 	}
+
+	params := req[payloadPubSubNamespace]
+
+	var tokenTTL time.Duration
+	if params.TokenTTL != "" {
+		tokenTTL, err = time.ParseDuration(params.TokenTTL)
+		if err != nil || tokenTTL < 0 {
+			zlog.Error().Err(err).Str("tokenTTL", params.TokenTTL).Msg("failed to parse token ttl for create pubsub")
+			return nil
+		}
+	}
+
+	var ttl time.Duration
+	if params.TTL != "" {
+		ttl, err = time.ParseDuration(params.TTL)
+		if err != nil || ttl < 0 {
+			zlog.Error().Err(err).Str("ttl", params.TTL).Msg("failed to parse ttl for create pubsub")
+			return nil
+		}
+	}
+
+	var idleTTL time.Duration
+	if params.IdleTTL != "" {
+		idleTTL, err = time.ParseDuration(params.IdleTTL)
+		if err != nil || idleTTL < 0 {
+			zlog.Error().Err(err).Str("idleTTL", params.IdleTTL).Msg("failed to parse idle ttl for create pubsub")
+			return nil
+		}
+	}
+
+	var tickFrequency time.Duration
+	if params.TickFrequency != "" {
+		tickFrequency, err = time.ParseDuration(params.TickFrequency)
+		if err != nil || tickFrequency < 0 {
+			zlog.Error().Err(err).Str("tickFrequency", params.TickFrequency).Msg("failed to parse tick frequency for create pubsub")
+			return nil
+		}
+	}
+
+	var retryHint time.Duration
+	if params.RetryHint != "" {
+		retryHint, err = time.ParseDuration(params.RetryHint)
+		if err != nil || retryHint < 0 {
+			zlog.Error().Err(err).Str("retryHint", params.RetryHint).Msg("failed to parse retry hint for create pubsub")
+			return nil
+		}
+	}
+
+	if params.Retention < 0 {
+		zlog.Error().Int("retention", params.Retention).Msg("negative retention buffer size for create pubsub")
+		return nil
+	}
+
+	profile := params.Profile
+	switch profile {
+	case "":
+		profile = entity.ProfileLowLatency
+	case entity.ProfileLowLatency, entity.ProfileThroughput:
+	default:
+		zlog.Error().Str("profile", params.Profile).Msg("unknown latency profile for create pubsub")
+		return nil
+	}
+
 	return &entity.CreatePubSubRequest{
 		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
-		Persist:        req[payloadPubSubNamespace].Persist,
+		Name:           params.Name,
+		Persist:        params.Persist,
+		Presence:       params.Presence,
+		TokenTTL:       tokenTTL,
+		TTL:            ttl,
+		IdleTTL:        idleTTL,
+		TickFrequency:  tickFrequency,
+		RetryHint:      retryHint,
+		Profile:        profile,
+
+		RetentionBufferSize: params.Retention,
+		Token:               params.Token,
 	}
 }
 
 func FromCreatePubSubResponseToHttpResponse(res entity.CreatePubSubResponse) []byte {
+	tokenExpiresAt := ""
+	if !res.TokenExpiresAt.IsZero() {
+		tokenExpiresAt = res.TokenExpiresAt.UTC().Format(time.RFC3339)
+	}
 	payload := map[string]view.CreatePubSubResponse{
 		payloadPubSubNamespace: {
-			ID:    monoflake.ID(res.ID).String(),
-			Token: string(res.Token[:]),
+			ID:             monoflake.ID(res.ID).String(),
+			Token:          string(res.Token[:]),
+			TokenExpiresAt: tokenExpiresAt,
 		},
 	}
 
@@ -41,15 +124,182 @@ func FromCreatePubSubResponseToHttpResponse(res entity.CreatePubSubResponse) []b
 	return data
 }
 
+func FromHttpRequestToUpdatePubSubRequest(ctx *fasthttp.RequestCtx) *entity.UpdatePubSubRequest {
+	var req map[string]view.UpdatePubSubRequest
+
+	err := json.Unmarshal(ctx.Request.Body(), &req)
+	if err != nil {
+		return nil
+	}
+
+	params := req[payloadPubSubNamespace]
+
+	var tickFrequency *time.Duration
+	if params.TickFrequency != "" {
+		d, err := time.ParseDuration(params.TickFrequency)
+		if err != nil || d < 0 {
+			zlog.Error().Err(err).Str("tickFrequency", params.TickFrequency).Msg("failed to parse tick frequency for update pubsub")
+			return nil
+		}
+		tickFrequency = &d
+	}
+
+	var retryHint *time.Duration
+	if params.RetryHint != "" {
+		d, err := time.ParseDuration(params.RetryHint)
+		if err != nil || d < 0 {
+			zlog.Error().Err(err).Str("retryHint", params.RetryHint).Msg("failed to parse retry hint for update pubsub")
+			return nil
+		}
+		retryHint = &d
+	}
+
+	return &entity.UpdatePubSubRequest{
+		ApiAccessToken:      fromHttpRequestToAccessToken(ctx),
+		ID:                  fromHttpRequestToPubSubID(ctx),
+		TickFrequency:       tickFrequency,
+		RetryHint:           retryHint,
+		RetentionBufferSize: params.RetentionBufferSize,
+		MaxSubscribers:      params.MaxSubscribers,
+		Persist:             params.Persist,
+	}
+}
+
+func FromHttpRequestToPausePubSubRequest(ctx *fasthttp.RequestCtx) *entity.PausePubSubRequest {
+	return &entity.PausePubSubRequest{
+		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+		ID:             fromHttpRequestToPubSubID(ctx),
+	}
+}
+
+func FromHttpRequestToResumePubSubRequest(ctx *fasthttp.RequestCtx) *entity.ResumePubSubRequest {
+	return &entity.ResumePubSubRequest{
+		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+		ID:             fromHttpRequestToPubSubID(ctx),
+	}
+}
+
+func FromHttpRequestToSetSchemaRequest(ctx *fasthttp.RequestCtx) *entity.SetSchemaRequest {
+	body := ctx.Request.Body()
+	if len(body) == 0 {
+		return nil
+	}
+	schema := make([]byte, len(body))
+	copy(schema, body)
+
+	return &entity.SetSchemaRequest{
+		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+		ID:             fromHttpRequestToPubSubID(ctx),
+		Schema:         schema,
+	}
+}
+
+func FromHttpRequestToGetSchemaRequest(ctx *fasthttp.RequestCtx) *entity.GetSchemaRequest {
+	return &entity.GetSchemaRequest{
+		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+		ID:             fromHttpRequestToPubSubID(ctx),
+	}
+}
+
+// FromGetSchemaResponseToHttpResponse returns the schema document verbatim;
+// it is already JSON.
+func FromGetSchemaResponseToHttpResponse(res entity.GetSchemaResponse) []byte {
+	return res.Schema
+}
+
+func FromHttpRequestToDeleteSchemaRequest(ctx *fasthttp.RequestCtx) *entity.DeleteSchemaRequest {
+	return &entity.DeleteSchemaRequest{
+		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+		ID:             fromHttpRequestToPubSubID(ctx),
+	}
+}
+
+func FromHttpRequestToSetScriptRequest(ctx *fasthttp.RequestCtx) *entity.SetScriptRequest {
+	body := ctx.Request.Body()
+	if len(body) == 0 {
+		return nil
+	}
+	script := make([]byte, len(body))
+	copy(script, body)
+
+	return &entity.SetScriptRequest{
+		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+		ID:             fromHttpRequestToPubSubID(ctx),
+		Script:         script,
+	}
+}
+
+func FromHttpRequestToGetScriptRequest(ctx *fasthttp.RequestCtx) *entity.GetScriptRequest {
+	return &entity.GetScriptRequest{
+		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+		ID:             fromHttpRequestToPubSubID(ctx),
+	}
+}
+
+// FromGetScriptResponseToHttpResponse returns the script document verbatim;
+// it is already JSON.
+func FromGetScriptResponseToHttpResponse(res entity.GetScriptResponse) []byte {
+	return res.Script
+}
+
+func FromHttpRequestToDeleteScriptRequest(ctx *fasthttp.RequestCtx) *entity.DeleteScriptRequest {
+	return &entity.DeleteScriptRequest{
+		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+		ID:             fromHttpRequestToPubSubID(ctx),
+	}
+}
+
 func FromHttpRequestToDeletePubSubRequest(ctx *fasthttp.RequestCtx) *entity.DeletePubSubRequest {
 	return &entity.DeletePubSubRequest{
 		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
 		ID:             fromHttpRequestToPubSubID(ctx),
+		// ?redirect_to= names a replacement topic for the deletion event
+		RedirectTo: string(ctx.QueryArgs().Peek("redirect_to")),
 	}
 }
 
 func FromHttpRequestToPublishRequest(ctx *fasthttp.RequestCtx) *entity.PublishRequest {
 	id := fromHttpRequestToPubSubID(ctx)
+
+	// ?wait=delivered defers the response until every enqueued delivery is
+	// confirmed (or ?timeout= elapses)
+	waitDelivered := string(ctx.QueryArgs().Peek("wait")) == "delivered"
+	var waitTimeout time.Duration
+	if raw := string(ctx.QueryArgs().Peek("timeout")); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			zlog.Error().Err(err).Str("timeout", raw).Msg("failed to parse wait timeout for publish event")
+			return nil
+		}
+		waitTimeout = d
+	}
+
+	// raw bodies skip the JSON wrapper: octet-stream payloads are stored as-is
+	// and base64-encoded into the SSE frame, text/plain payloads are embedded
+	// as a JSON string by the envelope
+	contentType := string(ctx.Request.Header.ContentType())
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	if mediaType = strings.TrimSpace(mediaType); mediaType == "application/octet-stream" || mediaType == "text/plain" {
+		encoding := ""
+		if mediaType == "application/octet-stream" {
+			encoding = entity.EncodingBase64
+		}
+		return &entity.PublishRequest{
+			ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+			PubSubID:       id,
+			EventID:        string(ctx.Request.Header.Peek("X-Event-ID")),
+			EventType:      string(ctx.Request.Header.Peek("X-Event-Type")),
+			IdempotencyKey: string(ctx.Request.Header.Peek("Idempotency-Key")),
+			Key:            string(ctx.Request.Header.Peek("X-Event-Key")),
+			Encoding:       encoding,
+			ContentType:    mediaType,
+			KeyID:          string(ctx.Request.Header.Peek("X-Encryption-Key-Id")),
+			Message:        append([]byte(nil), ctx.Request.Body()...),
+			WaitDelivered:  waitDelivered,
+			WaitTimeout:    waitTimeout,
+		}
+	}
+
 	var req map[string]view.PublishRequest
 
 	err := json.Unmarshal(ctx.Request.Body(), &req)
@@ -65,21 +315,57 @@ func FromHttpRequestToPublishRequest(ctx *fasthttp.RequestCtx) *entity.PublishRe
 		PubSubID:       id,
 		EventID:        params.ID,
 		EventType:      params.Type,
+		IdempotencyKey: string(ctx.Request.Header.Peek("Idempotency-Key")),
+		Key:            params.Key,
+		ContentType:    params.ContentType,
+		KeyID:          string(ctx.Request.Header.Peek("X-Encryption-Key-Id")),
+		Headers:        params.Headers,
 		Message:        []byte(params.Message),
+		WaitDelivered:  waitDelivered,
+		WaitTimeout:    waitTimeout,
 	}
 }
 
 func FromPublishResponseToHttpResponse(res entity.PublishResponse) []byte {
+	event := view.PublishResponse{
+		ID:          monoflake.ID(res.ID).String(),
+		Subscribers: res.Subscribers,
+		Enqueued:    res.Enqueued,
+		Dropped:     res.Dropped,
+	}
+	if res.Filtered {
+		// the topic script stopped the publish before an id was generated
+		event.ID = ""
+		event.Filtered = true
+	}
+	if res.Waited {
+		event.Outcome = &view.PublishOutcome{
+			Delivered: res.Delivered,
+			Failed:    res.Failed,
+			Pending:   res.Pending,
+		}
+	}
 	payload := map[string]view.PublishResponse{
-		payloadPubSubEventNamespace: {
-			ID: monoflake.ID(res.ID).String(),
-		},
+		payloadPubSubEventNamespace: event,
 	}
 
 	data, _ := json.Marshal(payload)
 	return data
 }
 
+// FromSubscribeResponseToReadyEventData renders the data payload of the
+// stream's initial `ready` handshake event.
+func FromSubscribeResponseToReadyEventData(pubsubID int64, res entity.SubscribeResponse) []byte {
+	data, _ := json.Marshal(view.ReadyEvent{
+		SubscriberID:  monoflake.ID(res.ID).String(),
+		PubSubID:      monoflake.ID(pubsubID).String(),
+		ServerTime:    time.Now().UTC().Format(time.RFC3339),
+		TickFrequency: res.TickFrequency.String(),
+		Retention:     res.RetentionAvailable,
+	})
+	return data
+}
+
 func FromHttpRequestToSubscribeRequest(ctx *fasthttp.RequestCtx) *entity.SubscribeRequest {
 	id := fromHttpRequestToPubSubID(ctx)
 	token := fromHttpRequestToAccessToken(ctx)
@@ -87,16 +373,454 @@ func FromHttpRequestToSubscribeRequest(ctx *fasthttp.RequestCtx) *entity.Subscri
 		token = string(ctx.QueryArgs().Peek("access_token"))
 	}
 
+	var types []string
+	if raw := string(ctx.QueryArgs().Peek("types")); raw != "" {
+		for _, t := range strings.Split(raw, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				types = append(types, t)
+			}
+		}
+	}
+
+	var attrs map[string]string
+	for _, raw := range ctx.QueryArgs().PeekMulti("match") {
+		parts := strings.SplitN(string(raw), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			continue
+		}
+		if attrs == nil {
+			attrs = make(map[string]string)
+		}
+		attrs[parts[0]] = parts[1]
+	}
+
+	resumption := string(ctx.Request.Header.Peek("X-Resumption-Token"))
+	if resumption == "" {
+		resumption = string(ctx.QueryArgs().Peek("resumption_token"))
+	}
+
 	return &entity.SubscribeRequest{
-		PubSubID: id,
+		PubSubID:        id,
+		Token:           []byte(token),
+		Types:           types,
+		Attrs:           attrs,
+		Transform:       string(ctx.QueryArgs().Peek("transform")),
+		LastEventID:     string(ctx.Request.Header.Peek("Last-Event-ID")),
+		ResumptionToken: resumption,
+		Snapshot:        string(ctx.QueryArgs().Peek("snapshot")) == "true",
+	}
+}
+
+func FromHttpRequestToPollRequest(ctx *fasthttp.RequestCtx) *entity.PollRequest {
+	token := fromHttpRequestToAccessToken(ctx)
+	if token == "" {
+		token = string(ctx.QueryArgs().Peek("access_token"))
+	}
+
+	var cursor int64
+	if raw := string(ctx.QueryArgs().Peek("cursor")); raw != "" {
+		var err error
+		cursor, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || cursor < 0 {
+			zlog.Error().Str("cursor", raw).Msg("failed to parse cursor for poll")
+			return nil
+		}
+	}
+
+	return &entity.PollRequest{
+		PubSubID: fromHttpRequestToPubSubID(ctx),
+		Token:    []byte(token),
+		Cursor:   cursor,
+	}
+}
+
+func FromPollResponseToHttpResponse(res entity.PollResponse) []byte {
+	events := make([]view.PollEvent, len(res.Events))
+	for i, ev := range res.Events {
+		events[i] = view.PollEvent{
+			ID:   ev.ID,
+			Type: ev.Type,
+			Data: json.RawMessage(ev.Data),
+		}
+	}
+
+	payload := view.PollResponse{
+		Events: events,
+		Cursor: res.Cursor,
+	}
+
+	data, _ := json.Marshal(payload)
+	return data
+}
+
+// FromHttpRequestToPollRequestV2 is the v2 variant of the poll mapper: the
+// cursor arrives as the opaque token a previous v2 response handed out, so
+// clients can't build assumptions on its numeric form.
+func FromHttpRequestToPollRequestV2(ctx *fasthttp.RequestCtx) *entity.PollRequest {
+	token := fromHttpRequestToAccessToken(ctx)
+	if token == "" {
+		token = string(ctx.QueryArgs().Peek("access_token"))
+	}
+
+	var cursor int64
+	if raw := string(ctx.QueryArgs().Peek("cursor")); raw != "" {
+		var err error
+		cursor, err = decodePollCursorV2(raw)
+		if err != nil {
+			zlog.Error().Str("cursor", raw).Msg("failed to parse v2 cursor for poll")
+			return nil
+		}
+	}
+
+	return &entity.PollRequest{
+		PubSubID: fromHttpRequestToPubSubID(ctx),
 		Token:    []byte(token),
+		Cursor:   cursor,
+	}
+}
+
+// FromPollResponseToHttpResponseV2 renders history in the v2 shape: each
+// event is its structured envelope verbatim — no re-wrapping, no duplicated
+// id/type — and the cursor is handed out as an opaque token.
+func FromPollResponseToHttpResponseV2(res entity.PollResponse) []byte {
+	events := make([]json.RawMessage, len(res.Events))
+	for i, ev := range res.Events {
+		events[i] = json.RawMessage(ev.Data)
+	}
+
+	payload := view.PollResponseV2{
+		Events: events,
+		Cursor: encodePollCursorV2(res.Cursor),
+	}
+
+	data, _ := json.Marshal(payload)
+	return data
+}
+
+// encodePollCursorV2 wraps the numeric history cursor in an opaque token; the
+// numeric form stays a v1 compatibility detail.
+func encodePollCursorV2(cursor int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(cursor, 10)))
+}
+
+func decodePollCursorV2(raw string) (int64, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return 0, err
+	}
+	cursor, err := strconv.ParseInt(string(decoded), 10, 64)
+	if err != nil || cursor < 0 {
+		return 0, fmt.Errorf("cursor is not a valid token")
 	}
+	return cursor, nil
+}
+
+func FromHttpRequestToGetSubscribersRequest(ctx *fasthttp.RequestCtx) *entity.GetSubscribersRequest {
+	token := fromHttpRequestToAccessToken(ctx)
+	if token == "" {
+		token = string(ctx.QueryArgs().Peek("access_token"))
+	}
+
+	return &entity.GetSubscribersRequest{
+		PubSubID: fromHttpRequestToPubSubID(ctx),
+		Token:    []byte(token),
+	}
+}
+
+func FromGetSubscribersResponseToHttpResponse(res entity.GetSubscribersResponse) []byte {
+	subscribers := make([]view.Subscriber, len(res.Subscribers))
+	for i, s := range res.Subscribers {
+		subscribers[i] = view.Subscriber{
+			ID:          monoflake.ID(s.ID).String(),
+			ConnectedAt: s.ConnectedAt.UTC().Format(time.RFC3339),
+		}
+		if !s.LastFlushAt.IsZero() {
+			subscribers[i].LastFlushAt = s.LastFlushAt.UTC().Format(time.RFC3339)
+		}
+	}
+
+	payload := view.GetSubscribersResponse{
+		Subscribers: subscribers,
+		Count:       len(subscribers),
+	}
+
+	data, _ := json.Marshal(payload)
+	return data
+}
+
+func FromHttpRequestToQueryArchiveRequest(ctx *fasthttp.RequestCtx) *entity.QueryArchiveRequest {
+	var from, to time.Time
+	if raw := string(ctx.QueryArgs().Peek("from")); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			zlog.Error().Err(err).Str("from", raw).Msg("failed to parse from time for query archive")
+			return nil
+		}
+		from = parsed
+	}
+	if raw := string(ctx.QueryArgs().Peek("to")); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			zlog.Error().Err(err).Str("to", raw).Msg("failed to parse to time for query archive")
+			return nil
+		}
+		to = parsed
+	}
+
+	limit := 0
+	if raw := string(ctx.QueryArgs().Peek("limit")); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			zlog.Error().Err(err).Str("limit", raw).Msg("failed to parse limit for query archive")
+			return nil
+		}
+		limit = parsed
+	}
+
+	return &entity.QueryArchiveRequest{
+		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+		PubSubID:       fromHttpRequestToPubSubID(ctx),
+		From:           from,
+		To:             to,
+		Limit:          limit,
+	}
+}
+
+func FromQueryArchiveResponseToHttpResponse(res entity.QueryArchiveResponse) []byte {
+	events := make([]view.ArchivedEvent, len(res.Events))
+	for i, ev := range res.Events {
+		events[i] = view.ArchivedEvent{
+			ID:          ev.ID,
+			Type:        ev.Type,
+			Data:        json.RawMessage(ev.Data),
+			PublishedAt: ev.PublishedAt.UTC().Format(time.RFC3339),
+		}
+	}
+
+	payload := view.QueryArchiveResponse{
+		Events: events,
+		Count:  len(events),
+	}
+
+	data, _ := json.Marshal(payload)
+	return data
+}
+
+func FromHttpRequestToListPubSubsRequest(ctx *fasthttp.RequestCtx) *entity.ListPubSubsRequest {
+	return &entity.ListPubSubsRequest{
+		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+	}
+}
+
+func FromListPubSubsResponseToHttpResponse(res entity.ListPubSubsResponse) []byte {
+	pubsubs := make([]view.PubSubSummary, 0, len(res.PubSubs))
+	for _, ps := range res.PubSubs {
+		pubsubs = append(pubsubs, view.PubSubSummary{
+			ID:          monoflake.ID(ps.ID).String(),
+			Name:        ps.Name,
+			Tenant:      ps.Tenant,
+			Static:      ps.Static,
+			Persist:     ps.Persist,
+			Paused:      ps.Paused,
+			Profile:     ps.Profile,
+			Subscribers: ps.Subscribers,
+		})
+	}
+
+	data, _ := json.Marshal(view.ListPubSubsResponse{
+		PubSubs: pubsubs,
+		Count:   len(pubsubs),
+	})
+	return data
+}
+
+// FromHttpRequestToExportTopicsRequest reads the bundle key off the
+// X-Bundle-Key header so the secret stays out of access logs and URLs.
+func FromHttpRequestToExportTopicsRequest(ctx *fasthttp.RequestCtx) *entity.ExportTopicsRequest {
+	return &entity.ExportTopicsRequest{
+		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+		Key:            string(ctx.Request.Header.Peek("X-Bundle-Key")),
+	}
+}
+
+func FromHttpRequestToImportTopicsRequest(ctx *fasthttp.RequestCtx) *entity.ImportTopicsRequest {
+	body := ctx.Request.Body()
+	bundle := make([]byte, len(body))
+	copy(bundle, body)
+	return &entity.ImportTopicsRequest{
+		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+		Key:            string(ctx.Request.Header.Peek("X-Bundle-Key")),
+		Bundle:         bundle,
+	}
+}
+
+func FromImportTopicsResponseToHttpResponse(res entity.ImportTopicsResponse) []byte {
+	data, _ := json.Marshal(view.ImportTopicsResponse{
+		Imported: res.Imported,
+		Skipped:  res.Skipped,
+	})
+	return data
+}
+
+func FromHttpRequestToBackupStoreRequest(ctx *fasthttp.RequestCtx) *entity.BackupStoreRequest {
+	return &entity.BackupStoreRequest{
+		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+	}
+}
+
+// FromHttpRequestToGetUsageRequest parses the optional from/to bounds; an
+// unparseable bound rejects the request rather than silently widening the
+// billing range.
+func FromHttpRequestToGetUsageRequest(ctx *fasthttp.RequestCtx) *entity.GetUsageRequest {
+	req := &entity.GetUsageRequest{
+		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+	}
+	if raw := string(ctx.QueryArgs().Peek("from")); raw != "" {
+		from, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil
+		}
+		req.From = from.UTC()
+	}
+	if raw := string(ctx.QueryArgs().Peek("to")); raw != "" {
+		to, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil
+		}
+		req.To = to.UTC()
+	}
+	return req
+}
+
+func FromGetUsageResponseToHttpResponse(res entity.GetUsageResponse) []byte {
+	payload := view.UsageResponse{
+		Usage: make([]view.UsageRow, len(res.Rows)),
+	}
+	for i, row := range res.Rows {
+		payload.Usage[i] = view.UsageRow{
+			Tenant:            row.Tenant,
+			PeriodStart:       row.PeriodStart.UTC().Format(time.RFC3339),
+			Publishes:         row.Publishes,
+			Delivered:         row.Delivered,
+			BytesIn:           row.BytesIn,
+			BytesOut:          row.BytesOut,
+			ConnectionSeconds: row.ConnectionSeconds,
+		}
+	}
+
+	data, _ := json.Marshal(payload)
+	return data
+}
+
+// FromGetUsageResponseToCsv renders the rows as the billing export; one line
+// per tenant and window with a fixed header.
+func FromGetUsageResponseToCsv(res entity.GetUsageResponse) []byte {
+	var b strings.Builder
+	b.WriteString("tenant,period_start,publishes,delivered,bytes_in,bytes_out,connection_seconds\n")
+	for _, row := range res.Rows {
+		b.WriteString(row.Tenant)
+		b.WriteByte(',')
+		b.WriteString(row.PeriodStart.UTC().Format(time.RFC3339))
+		b.WriteByte(',')
+		b.WriteString(strconv.FormatInt(row.Publishes, 10))
+		b.WriteByte(',')
+		b.WriteString(strconv.FormatInt(row.Delivered, 10))
+		b.WriteByte(',')
+		b.WriteString(strconv.FormatInt(row.BytesIn, 10))
+		b.WriteByte(',')
+		b.WriteString(strconv.FormatInt(row.BytesOut, 10))
+		b.WriteByte(',')
+		b.WriteString(strconv.FormatInt(row.ConnectionSeconds, 10))
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+func FromHttpRequestToUpsertStaticPubSubRequest(ctx *fasthttp.RequestCtx) *entity.UpsertStaticPubSubRequest {
+	var req map[string]view.StaticPubSub
+
+	err := json.Unmarshal(ctx.Request.Body(), &req)
+	if err != nil {
+		zlog.Error().Err(err).Str("body", string(ctx.Request.Body())).Msg("failed to parse request for upsert static pubsub")
+		return nil
+	}
+
+	params := req[payloadPubSubNamespace]
+
+	return &entity.UpsertStaticPubSubRequest{
+		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+		ID:             params.ID,
+		Name:           params.Name,
+		Token:          params.Token,
+	}
+}
+
+func FromHttpRequestToDeleteStaticPubSubRequest(ctx *fasthttp.RequestCtx) *entity.DeleteStaticPubSubRequest {
+	raw, ok := ctx.UserValue("id").(string)
+	if !ok || raw == "" {
+		path := string(ctx.Path())
+		paths := strings.Split(path, "/")
+		if len(paths) < 6 {
+			return nil
+		}
+		raw = paths[5]
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	return &entity.DeleteStaticPubSubRequest{
+		ApiAccessToken: fromHttpRequestToAccessToken(ctx),
+		ID:             id,
+	}
+}
+
+func FromHttpRequestToGetTopicRatesRequest(ctx *fasthttp.RequestCtx) *entity.GetTopicRatesRequest {
+	return &entity.GetTopicRatesRequest{
+		MetricsAccessToken: fromHttpRequestToAccessToken(ctx),
+		PubSubID:           fromHttpRequestToPubSubID(ctx),
+	}
+}
+
+func FromGetTopicRatesResponseToHttpResponse(res entity.GetTopicRatesResponse) []byte {
+	payload := map[string]view.TopicRates{
+		payloadPubSubNamespace: {
+			ID:         monoflake.ID(res.PubSubID).String(),
+			LastSecond: res.LastSecond,
+			Last10Secs: res.Last10Secs,
+			LastMinute: res.LastMinute,
+		},
+	}
+
+	data, _ := json.Marshal(payload)
+	return data
 }
 
 func FromHttpRequestToGetMetricsRequest(ctx *fasthttp.RequestCtx) *entity.GetMetricsRequest {
-	return &entity.GetMetricsRequest{
+	req := &entity.GetMetricsRequest{
 		MetricsAccessToken: fromHttpRequestToAccessToken(ctx),
 	}
+
+	// ?since= (RFC3339) or ?window= (duration) ask for counter deltas
+	// instead of absolute values
+	if raw := string(ctx.QueryArgs().Peek("since")); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			zlog.Error().Err(err).Str("since", raw).Msg("failed to parse since for get metrics")
+			return nil
+		}
+		req.Since = t.UTC()
+	}
+	if raw := string(ctx.QueryArgs().Peek("window")); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			zlog.Error().Err(err).Str("window", raw).Msg("failed to parse window for get metrics")
+			return nil
+		}
+		req.Window = d
+	}
+	return req
 }
 
 func FromGetMetricsResponseToHttpResponse(res entity.GetMetricsResponse) []byte {
@@ -108,6 +832,9 @@ func FromGetMetricsResponseToHttpResponse(res entity.GetMetricsResponse) []byte
 	payload := view.GetMetricsResponse{
 		Metrics: metrics,
 	}
+	if !res.Since.IsZero() {
+		payload.Since = res.Since.UTC().Format(time.RFC3339)
+	}
 
 	data, _ := json.Marshal(payload)
 	return data
@@ -121,6 +848,11 @@ func fromMetricEntityMetricView(e entity.Metric) view.Metric {
 }
 
 func fromHttpRequestToPubSubID(ctx *fasthttp.RequestCtx) int64 {
+	// the router stores matched path params as user values
+	if id, ok := ctx.UserValue("id").(string); ok && id != "" {
+		return monoflake.IDFromBase62(id).Int64()
+	}
+
 	path := string(ctx.Path())
 	paths := strings.Split(path, "/")
 	if len(paths) < 5 {
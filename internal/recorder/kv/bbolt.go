@@ -0,0 +1,402 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+	"go.etcd.io/bbolt"
+)
+
+type bboltRecorder struct {
+	// mu lets Compact swap the handle under live traffic; regular
+	// operations share the read side
+	mu sync.RWMutex
+	db *bbolt.DB
+	// path is kept so recovery can reopen the file even when the dead
+	// handle no longer reports it
+	path string
+	// batching is kept so Compact can reapply it on the reopened handle
+	batching batchingCfg
+	// reopens counts automatic reopen recoveries since start
+	reopens int64
+}
+
+// schemaVersion is bumped whenever the on-disk layout changes; migrations run
+// once on open and record the new version in the meta bucket
+const schemaVersion uint64 = 1
+
+var (
+	_defaultBucket = []byte("_d")
+	_metaBucket    = []byte("_meta")
+	_keyVersion    = []byte("schema_version")
+)
+
+func newBbolt(cfg kvCfg) (Recorder, error) {
+	db, err := bbolt.Open(cfg.DSN, 0600, &bbolt.Options{
+		Timeout: time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(_defaultBucket)
+		if err != nil {
+			return err
+		}
+		if b == nil {
+			return errors.New("bucket is nil")
+		}
+		return migrate(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	applyBatching(db, cfg.Batching)
+
+	zlog.Info().Str("driver", DriverBbolt).Bool("batching", cfg.Batching.Enabled).Msg(logPrefix + "initialized")
+
+	return &bboltRecorder{db: db, path: cfg.DSN, batching: cfg.Batching}, nil
+}
+
+// Ping verifies the file still answers a read; a failure triggers one reopen
+// attempt so a transient filesystem issue doesn't leave persistence dead
+// until restart.
+func (r *bboltRecorder) Ping(ctx context.Context) error {
+	if r == nil {
+		return ErrNotEnabled
+	}
+	err := r.ping()
+	if err == nil {
+		return nil
+	}
+	zlog.Warn().Err(err).Msg(logPrefix + "ping failed; reopening the database")
+	if reopenErr := r.reopen(); reopenErr != nil {
+		return reopenErr
+	}
+	return r.ping()
+}
+
+// ping runs the cheapest possible read transaction
+func (r *bboltRecorder) ping() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.db.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket(_metaBucket) == nil {
+			return errors.New("meta bucket is missing")
+		}
+		return nil
+	})
+}
+
+// reopen swaps in a fresh handle for the same file
+func (r *bboltRecorder) reopen() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the old handle may already be unusable; its close error is the reason
+	// we're here, not something to act on
+	_ = r.db.Close()
+
+	db, err := bbolt.Open(r.path, 0600, &bbolt.Options{
+		Timeout: time.Second,
+	})
+	if err != nil {
+		return err
+	}
+	applyBatching(db, r.batching)
+	r.db = db
+
+	zlog.Info().Int64("reopens", atomic.AddInt64(&r.reopens, 1)).Msg(logPrefix + "reopened the database")
+	return nil
+}
+
+// applyBatching tunes bbolt's group-commit batcher; zero values keep bbolt's
+// own defaults (10ms window, 1000 ops)
+func applyBatching(db *bbolt.DB, cfg batchingCfg) {
+	if !cfg.Enabled {
+		return
+	}
+	if cfg.Window > 0 {
+		db.MaxBatchDelay = cfg.Window
+	}
+	if cfg.MaxOps > 0 {
+		db.MaxBatchSize = cfg.MaxOps
+	}
+}
+
+// write runs fn in its own transaction, or hands it to bbolt's group-commit
+// batcher when batching is enabled; batched writes share one fsync with
+// whatever else lands inside the window, which is what keeps sustained
+// publish load with persistence from paying a transaction per event. fn may
+// be retried by the batcher, so it must stay idempotent.
+func (r *bboltRecorder) write(fn func(tx *bbolt.Tx) error) error {
+	if r.batching.Enabled {
+		return r.db.Batch(fn)
+	}
+	return r.db.Update(fn)
+}
+
+// migrate brings an existing database up to the current schema version; each
+// future layout change gets its own numbered step here
+func migrate(tx *bbolt.Tx) error {
+	meta, err := tx.CreateBucketIfNotExists(_metaBucket)
+	if err != nil {
+		return err
+	}
+
+	current := uint64(0)
+	if raw := meta.Get(_keyVersion); len(raw) == 8 {
+		current = binary.BigEndian.Uint64(raw)
+	}
+	if current >= schemaVersion {
+		return nil
+	}
+
+	// version 0 -> 1: no record rewrites; the meta bucket itself is the change
+
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, schemaVersion)
+	return meta.Put(_keyVersion, buf)
+}
+
+func (r *bboltRecorder) ListKeys(ctx context.Context) ([][]byte, error) {
+	return r.ListKeysIn(ctx, "")
+}
+
+func (r *bboltRecorder) Get(ctx context.Context, key []byte) ([]byte, error) {
+	return r.GetIn(ctx, "", key)
+}
+
+func (r *bboltRecorder) Set(ctx context.Context, key, val []byte) error {
+	return r.SetIn(ctx, "", key, val)
+}
+
+func (r *bboltRecorder) Delete(ctx context.Context, key []byte) error {
+	return r.DeleteIn(ctx, "", key)
+}
+
+func (r *bboltRecorder) ListKeysIn(ctx context.Context, bucket string) ([][]byte, error) {
+	if r == nil {
+		return nil, ErrNotEnabled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var keys [][]byte
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(bucketName(bucket))
+		if b == nil {
+			return nil
+		}
+		c := b.Cursor()
+
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			keys = append(keys, k)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *bboltRecorder) GetIn(ctx context.Context, bucket string, key []byte) ([]byte, error) {
+	if r == nil {
+		return nil, ErrNotFound
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var val []byte
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(bucketName(bucket))
+		if b == nil {
+			return nil
+		}
+		val = b.Get(key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if val == nil {
+		return nil, ErrNotFound
+	}
+	return val, nil
+}
+
+func (r *bboltRecorder) SetIn(ctx context.Context, bucket string, key, val []byte) error {
+	if r == nil {
+		return ErrNotEnabled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.write(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(bucketName(bucket))
+		if err != nil {
+			return err
+		}
+		return b.Put(key, val)
+	})
+}
+
+func (r *bboltRecorder) DeleteIn(ctx context.Context, bucket string, key []byte) error {
+	if r == nil {
+		return ErrNotEnabled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.write(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(bucketName(bucket))
+		if b == nil {
+			return nil
+		}
+		return b.Delete(key)
+	})
+}
+
+func (r *bboltRecorder) ListBuckets(ctx context.Context) ([]string, error) {
+	if r == nil {
+		return nil, ErrNotEnabled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var buckets []string
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		return tx.ForEach(func(name []byte, _ *bbolt.Bucket) error {
+			switch {
+			case bytes.Equal(name, _metaBucket):
+			case bytes.Equal(name, _defaultBucket):
+				buckets = append(buckets, "")
+			case bytes.HasPrefix(name, []byte("_t_")):
+				buckets = append(buckets, string(name[len("_t_"):]))
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// bucketName maps a tenant/bucket label to the underlying bbolt bucket; the
+// empty label keeps using the default bucket
+func bucketName(bucket string) []byte {
+	if bucket == "" {
+		return _defaultBucket
+	}
+	return []byte("_t_" + bucket)
+}
+
+// Backup streams a consistent snapshot of the whole database; bbolt's
+// WriteTo runs inside a read transaction, so writes keep flowing while the
+// copy is taken.
+func (r *bboltRecorder) Backup(ctx context.Context, w io.Writer) (int64, error) {
+	if r == nil {
+		return 0, ErrNotEnabled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var n int64
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		n, err = tx.WriteTo(w)
+		return err
+	})
+	return n, err
+}
+
+// Stats reports the file size, free pages and per-bucket key counts.
+func (r *bboltRecorder) Stats(ctx context.Context) (Stats, error) {
+	if r == nil {
+		return Stats{}, ErrNotEnabled
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s := r.db.Stats()
+	stats := Stats{
+		FreePages:  s.FreePageN + s.PendingPageN,
+		PageSize:   r.db.Info().PageSize,
+		Reopens:    atomic.LoadInt64(&r.reopens),
+		BucketKeys: map[string]int{},
+	}
+	if info, err := os.Stat(r.db.Path()); err == nil {
+		stats.SizeBytes = info.Size()
+	}
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bbolt.Bucket) error {
+			switch {
+			case bytes.Equal(name, _metaBucket):
+			case bytes.Equal(name, _defaultBucket):
+				stats.BucketKeys[""] = b.Stats().KeyN
+			case bytes.HasPrefix(name, []byte("_t_")):
+				stats.BucketKeys[string(name[len("_t_"):])] = b.Stats().KeyN
+			}
+			return nil
+		})
+	})
+	return stats, err
+}
+
+// Compact rewrites the database into a temp file and swaps it in, returning
+// the pages churny topics accumulated back to the filesystem; operations
+// block for the duration of the swap.
+func (r *bboltRecorder) Compact(ctx context.Context) error {
+	if r == nil {
+		return ErrNotEnabled
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path := r.db.Path()
+	tmpPath := path + ".compact"
+	os.Remove(tmpPath)
+
+	dst, err := bbolt.Open(tmpPath, 0600, &bbolt.Options{
+		Timeout: time.Second,
+	})
+	if err != nil {
+		return err
+	}
+	if err := bbolt.Compact(dst, r.db, 0); err != nil {
+		dst.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := r.db.Close(); err != nil {
+		return err
+	}
+	renameErr := os.Rename(tmpPath, path)
+	// reopen whichever file won the swap so the recorder stays usable even
+	// when the rename failed
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{
+		Timeout: time.Second,
+	})
+	if err != nil {
+		return err
+	}
+	applyBatching(db, r.batching)
+	r.db = db
+	return renameErr
+}
+
+func (r *bboltRecorder) Close() error {
+	zlog.Info().Msg(logPrefix + "closing")
+	return r.db.Close()
+}
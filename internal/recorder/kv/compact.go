@@ -0,0 +1,95 @@
+package kv
+
+import (
+	"context"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+)
+
+type (
+	// Stats reports file-level storage statistics for capacity planning and
+	// the compaction trigger.
+	Stats struct {
+		// SizeBytes is the on-disk size of the store
+		SizeBytes int64
+		// FreePages counts pages the backend holds but no longer uses
+		FreePages int
+		// PageSize is the backend's page size in bytes
+		PageSize int
+		// Reopens counts automatic reopen recoveries since start
+		Reopens int64
+		// BucketKeys maps each bucket label to its key count; the default
+		// bucket is reported as the empty string
+		BucketKeys map[string]int
+	}
+
+	// Compactor is implemented by backends that can report file statistics
+	// and rewrite themselves to reclaim free space.
+	Compactor interface {
+		Stats(ctx context.Context) (Stats, error)
+		Compact(ctx context.Context) error
+	}
+
+	// compactionCfg schedules threshold-driven compaction for backends that
+	// support it
+	compactionCfg struct {
+		Enabled bool `yaml:"enabled"`
+		// Frequency is how often the free-page share is checked; defaults to
+		// an hour
+		Frequency time.Duration `yaml:"frequency"`
+		// FreePageRatio triggers a compaction once free pages make up more
+		// than this share of the file; defaults to 0.5
+		FreePageRatio float64 `yaml:"freePageRatio"`
+	}
+)
+
+const (
+	// defaultCompactionFrequency applies when `compaction.frequency` is not
+	// set.
+	defaultCompactionFrequency = time.Hour
+	// defaultFreePageRatio applies when `compaction.freePageRatio` is not
+	// set.
+	defaultFreePageRatio = 0.5
+)
+
+const ErrCompactionUnsupported err = "storage backend does not support compaction"
+
+// runScheduledCompaction checks the free-page share on a ticker and rewrites
+// the store once it crosses the threshold; churny topics otherwise bloat the
+// file indefinitely since freed pages are reused but never returned to the
+// filesystem.
+func runScheduledCompaction(rec Compactor, cfg compactionCfg) {
+	freq := cfg.Frequency
+	if freq <= 0 {
+		freq = defaultCompactionFrequency
+	}
+	ratio := cfg.FreePageRatio
+	if ratio <= 0 {
+		ratio = defaultFreePageRatio
+	}
+	ticker := time.NewTicker(freq)
+	defer ticker.Stop()
+	for range ticker.C {
+		stats, err := rec.Stats(context.Background())
+		if err != nil {
+			zlog.Error().Err(err).Msg(logPrefix + "failed to read store stats; skipping compaction check")
+			continue
+		}
+		if stats.SizeBytes <= 0 || stats.PageSize <= 0 {
+			continue
+		}
+		free := float64(stats.FreePages) * float64(stats.PageSize) / float64(stats.SizeBytes)
+		if free < ratio {
+			continue
+		}
+
+		start := time.Now()
+		if err := rec.Compact(context.Background()); err != nil {
+			zlog.Error().Err(err).Msg(logPrefix + "compaction failed; retrying next tick")
+			continue
+		}
+		zlog.Info().Dur("took", time.Since(start)).Int("freePages", stats.FreePages).
+			Msg(logPrefix + "compacted the store")
+	}
+}
@@ -0,0 +1,241 @@
+package kv
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+
+	zlog "github.com/rs/zerolog/log"
+)
+
+type (
+	encryptionCfg struct {
+		Enabled bool `yaml:"enabled"`
+		// ActiveKey names the key used for new writes; the remaining keys
+		// stay available for decrypting records written before a rotation
+		ActiveKey string             `yaml:"activeKey"`
+		Keys      []encryptionKeyCfg `yaml:"keys"`
+	}
+
+	encryptionKeyCfg struct {
+		ID string `yaml:"id"`
+		// Secret is the hex-encoded AES key (16, 24 or 32 bytes decoded)
+		Secret string `yaml:"secret"`
+	}
+
+	// encryptedRecorder wraps any backend and encrypts values with AES-GCM
+	// before they reach the disk. Records written before encryption was
+	// enabled are read back as-is, so turning the feature on doesn't require
+	// downtime; the rekey command converts them in bulk.
+	encryptedRecorder struct {
+		inner    Recorder
+		activeID string
+		ciphers  map[string]cipher.AEAD
+	}
+)
+
+const (
+	ErrNoActiveKey   err = "encryption enabled without a usable active key"
+	ErrUnknownKeyID  err = "record encrypted with an unknown key id"
+	ErrMalformedSeal err = "malformed encrypted record"
+)
+
+// _sealMagic prefixes encrypted values so plaintext records written before
+// encryption was enabled remain distinguishable
+var _sealMagic = []byte{0x00, 'e', '1'}
+
+func newEncrypted(inner Recorder, cfg encryptionCfg) (Recorder, error) {
+	ciphers := make(map[string]cipher.AEAD, len(cfg.Keys))
+	for _, k := range cfg.Keys {
+		secret, err := hex.DecodeString(k.Secret)
+		if err != nil {
+			return nil, errors.New(logPrefix + "encryption key " + k.ID + " is not valid hex")
+		}
+		block, err := aes.NewCipher(secret)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		ciphers[k.ID] = aead
+	}
+
+	if _, ok := ciphers[cfg.ActiveKey]; !ok {
+		return nil, ErrNoActiveKey
+	}
+
+	zlog.Info().Str("activeKey", cfg.ActiveKey).Int("keys", len(ciphers)).
+		Msg(logPrefix + "encryption at rest enabled")
+
+	return &encryptedRecorder{
+		inner:    inner,
+		activeID: cfg.ActiveKey,
+		ciphers:  ciphers,
+	}, nil
+}
+
+// seal encrypts val with the active key; the key id travels with the record
+// so rotations only affect new writes
+func (r *encryptedRecorder) seal(val []byte) ([]byte, error) {
+	aead := r.ciphers[r.activeID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(_sealMagic)+1+len(r.activeID)+len(nonce)+len(val)+aead.Overhead())
+	out = append(out, _sealMagic...)
+	out = append(out, byte(len(r.activeID)))
+	out = append(out, r.activeID...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, val, nil), nil
+}
+
+// open decrypts a sealed record; plaintext records pass through untouched
+func (r *encryptedRecorder) open(val []byte) ([]byte, error) {
+	if len(val) < len(_sealMagic) || string(val[:len(_sealMagic)]) != string(_sealMagic) {
+		return val, nil
+	}
+
+	rest := val[len(_sealMagic):]
+	if len(rest) < 1 {
+		return nil, ErrMalformedSeal
+	}
+	idLen := int(rest[0])
+	rest = rest[1:]
+	if len(rest) < idLen {
+		return nil, ErrMalformedSeal
+	}
+	keyID := string(rest[:idLen])
+	rest = rest[idLen:]
+
+	aead, ok := r.ciphers[keyID]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	if len(rest) < aead.NonceSize() {
+		return nil, ErrMalformedSeal
+	}
+	nonce := rest[:aead.NonceSize()]
+	return aead.Open(nil, nonce, rest[aead.NonceSize():], nil)
+}
+
+func (r *encryptedRecorder) ListKeys(ctx context.Context) ([][]byte, error) {
+	return r.inner.ListKeys(ctx)
+}
+
+func (r *encryptedRecorder) Get(ctx context.Context, key []byte) ([]byte, error) {
+	return r.GetIn(ctx, "", key)
+}
+
+func (r *encryptedRecorder) Set(ctx context.Context, key, val []byte) error {
+	return r.SetIn(ctx, "", key, val)
+}
+
+func (r *encryptedRecorder) Delete(ctx context.Context, key []byte) error {
+	return r.inner.Delete(ctx, key)
+}
+
+func (r *encryptedRecorder) ListKeysIn(ctx context.Context, bucket string) ([][]byte, error) {
+	return r.inner.ListKeysIn(ctx, bucket)
+}
+
+func (r *encryptedRecorder) GetIn(ctx context.Context, bucket string, key []byte) ([]byte, error) {
+	val, err := r.inner.GetIn(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	return r.open(val)
+}
+
+func (r *encryptedRecorder) SetIn(ctx context.Context, bucket string, key, val []byte) error {
+	sealed, err := r.seal(val)
+	if err != nil {
+		return err
+	}
+	return r.inner.SetIn(ctx, bucket, key, sealed)
+}
+
+func (r *encryptedRecorder) DeleteIn(ctx context.Context, bucket string, key []byte) error {
+	return r.inner.DeleteIn(ctx, bucket, key)
+}
+
+func (r *encryptedRecorder) ListBuckets(ctx context.Context) ([]string, error) {
+	return r.inner.ListBuckets(ctx)
+}
+
+// Backup forwards to the wrapped backend when it supports snapshots; values
+// inside the snapshot stay sealed, so the copy is safe to ship off-host.
+func (r *encryptedRecorder) Backup(ctx context.Context, w io.Writer) (int64, error) {
+	b, ok := r.inner.(Backuper)
+	if !ok {
+		return 0, ErrBackupUnsupported
+	}
+	return b.Backup(ctx, w)
+}
+
+// Stats forwards to the wrapped backend when it supports statistics.
+func (r *encryptedRecorder) Stats(ctx context.Context) (Stats, error) {
+	c, ok := r.inner.(Compactor)
+	if !ok {
+		return Stats{}, ErrCompactionUnsupported
+	}
+	return c.Stats(ctx)
+}
+
+// Compact forwards to the wrapped backend when it supports compaction.
+func (r *encryptedRecorder) Compact(ctx context.Context) error {
+	c, ok := r.inner.(Compactor)
+	if !ok {
+		return ErrCompactionUnsupported
+	}
+	return c.Compact(ctx)
+}
+
+func (r *encryptedRecorder) Ping(ctx context.Context) error {
+	return r.inner.Ping(ctx)
+}
+
+func (r *encryptedRecorder) Close() error {
+	return r.inner.Close()
+}
+
+// Rekey rewrites every record under the active encryption key; it converts
+// plaintext records written before encryption was enabled and records sealed
+// with retired keys alike. The recorder must have encryption enabled.
+func Rekey(ctx context.Context, rec Recorder) (int, error) {
+	r, ok := rec.(*encryptedRecorder)
+	if !ok {
+		return 0, ErrNoActiveKey
+	}
+
+	buckets, err := r.ListBuckets(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	cnt := 0
+	for _, bucket := range buckets {
+		keys, err := r.ListKeysIn(ctx, bucket)
+		if err != nil {
+			return cnt, err
+		}
+		for _, key := range keys {
+			val, err := r.GetIn(ctx, bucket, key)
+			if err != nil {
+				return cnt, err
+			}
+			if err := r.SetIn(ctx, bucket, key, val); err != nil {
+				return cnt, err
+			}
+			cnt++
+		}
+	}
+	return cnt, nil
+}
@@ -2,12 +2,11 @@ package kv
 
 import (
 	"context"
-	"errors"
 	"time"
 
-	"github.com/hasmcp/sser/internal/servicer/config"
 	zlog "github.com/rs/zerolog/log"
-	"go.etcd.io/bbolt"
+
+	"github.com/hasmcp/sser/internal/servicer/config"
 )
 
 type (
@@ -16,22 +15,65 @@ type (
 		Get(ctx context.Context, key []byte) ([]byte, error)
 		Set(ctx context.Context, key, val []byte) error
 		Delete(ctx context.Context, key []byte) error
+		// bucket-scoped variants partition records (e.g. per tenant); an empty
+		// bucket name falls back to the default bucket
+		ListKeysIn(ctx context.Context, bucket string) ([][]byte, error)
+		GetIn(ctx context.Context, bucket string, key []byte) ([]byte, error)
+		SetIn(ctx context.Context, bucket string, key, val []byte) error
+		DeleteIn(ctx context.Context, bucket string, key []byte) error
+		// ListBuckets enumerates the bucket labels that hold records; the
+		// default bucket is reported as the empty string
+		ListBuckets(ctx context.Context) ([]string, error)
+		// Ping verifies the backend still answers; backends that can recover
+		// from transient failures (e.g. a reopened file handle) do so here
+		Ping(ctx context.Context) error
 		Close() error
 	}
 
-	recorder struct {
-		db *bbolt.DB
-	}
-
 	Params struct {
 		Config config.Servicer
 	}
 
-	bboltCfg struct {
+	kvCfg struct {
 		Enabled bool   `yaml:"enabled"`
+		Driver  string `yaml:"driver"`
 		DSN     string `yaml:"dsn"`
+		// SQLDriver picks the database/sql driver for the sql backend;
+		// defaults to postgres
+		SQLDriver string `yaml:"sqlDriver"`
+		// ArchiveRetention is how long archived events are kept by backends
+		// that support archival
+		ArchiveRetention time.Duration `yaml:"archiveRetention"`
+		// Encryption seals stored values with AES-GCM before they hit disk
+		Encryption encryptionCfg `yaml:"encryption"`
+		// Backup schedules periodic snapshots into a directory for backends
+		// that support online backup
+		Backup backupCfg `yaml:"backup"`
+		// Compaction periodically rewrites bloated backends to reclaim free
+		// pages
+		Compaction compactionCfg `yaml:"compaction"`
+		// Batching groups concurrent writes into shared transactions for
+		// backends that support group commit
+		Batching batchingCfg `yaml:"batching"`
 	}
 
+	// batchingCfg trades a little write latency for far fewer fsyncs under
+	// sustained publish load with persistence enabled
+	batchingCfg struct {
+		Enabled bool `yaml:"enabled"`
+		// Window is how long a write may linger waiting for peers to share
+		// its transaction; defaults to the backend's own (10ms for bbolt)
+		Window time.Duration `yaml:"window"`
+		// MaxOps caps how many writes share one transaction; defaults to
+		// the backend's own (1000 for bbolt)
+		MaxOps int `yaml:"maxOps"`
+	}
+
+	// opener constructs a backend from the shared config; backends register
+	// themselves in _drivers so new storage engines plug in without touching
+	// the dispatch below
+	opener func(cfg kvCfg) (Recorder, error)
+
 	err string
 )
 
@@ -40,16 +82,25 @@ const (
 
 	logPrefix = "[kv] "
 
-	ErrNotEnabled err = "kv is not enabled"
-	ErrNotFound   err = "not found"
-)
+	// DriverBbolt is the embedded single-node default.
+	DriverBbolt = "bbolt"
+	// DriverMemory keeps records in process memory only; useful for
+	// development and setups that don't need persistence across restarts.
+	DriverMemory = "memory"
 
-var (
-	_defaultBucket = []byte("_d")
+	ErrNotEnabled    err = "kv is not enabled"
+	ErrNotFound      err = "not found"
+	ErrUnknownDriver err = "unknown kv driver"
 )
 
+var _drivers = map[string]opener{
+	DriverBbolt:  newBbolt,
+	DriverMemory: newMemory,
+	DriverSQL:    newSQL,
+}
+
 func New(p Params) (Recorder, error) {
-	var cfg bboltCfg
+	var cfg kvCfg
 	err := p.Config.Populate(cfgKey, &cfg)
 	if err != nil {
 		return nil, err
@@ -59,98 +110,41 @@ func New(p Params) (Recorder, error) {
 		return nil, ErrNotEnabled
 	}
 
-	db, err := bbolt.Open(cfg.DSN, 0600, &bbolt.Options{
-		Timeout: time.Second,
-	})
-	if err != nil {
-		return nil, err
+	driver := cfg.Driver
+	if driver == "" {
+		driver = DriverBbolt
 	}
-
-	err = db.Update(func(tx *bbolt.Tx) error {
-		b := tx.Bucket(_defaultBucket)
-		if b == nil {
-			var err error
-			b, err = tx.CreateBucketIfNotExists(_defaultBucket)
-			if err != nil {
-				return err
-			}
-		}
-		if b == nil {
-			return errors.New("bucket is nil")
-		}
-		return nil
-	})
-	if err != nil {
-		return nil, err
+	open, ok := _drivers[driver]
+	if !ok {
+		return nil, ErrUnknownDriver
 	}
-
-	zlog.Info().Msg(logPrefix + "initialized")
-
-	return &recorder{db: db}, nil
-}
-
-func (r *recorder) ListKeys(ctx context.Context) ([][]byte, error) {
-	if r == nil {
-		return nil, ErrNotEnabled
-	}
-	var keys [][]byte
-	err := r.db.View(func(tx *bbolt.Tx) error {
-		b := tx.Bucket(_defaultBucket)
-		c := b.Cursor()
-
-		for k, _ := c.First(); k != nil; k, _ = c.Next() {
-			keys = append(keys, k)
-		}
-		return nil
-	})
+	rec, err := open(cfg)
 	if err != nil {
 		return nil, err
 	}
-	return keys, nil
-}
 
-func (r *recorder) Get(ctx context.Context, key []byte) ([]byte, error) {
-	if r == nil {
-		return nil, ErrNotFound
-	}
-	var val []byte
-	err := r.db.View(func(tx *bbolt.Tx) error {
-		b := tx.Bucket(_defaultBucket)
-		val = b.Get(key)
-		return nil
-	})
-	if err != nil {
-		return nil, err
-	}
-	if val == nil {
-		return nil, ErrNotFound
+	// the schedule snapshots the raw backend; the encryption wrapper seals
+	// values before they reach it, so the copies stay sealed either way
+	if cfg.Backup.Dir != "" {
+		if b, ok := rec.(Backuper); ok {
+			go runScheduledBackups(b, cfg.Backup)
+		} else {
+			zlog.Warn().Str("driver", driver).Msg(logPrefix + "scheduled backups configured but the backend doesn't support online backup")
+		}
 	}
-	return val, nil
-}
 
-func (r *recorder) Set(ctx context.Context, key, val []byte) error {
-	if r == nil {
-		return ErrNotEnabled
+	if cfg.Compaction.Enabled {
+		if comp, ok := rec.(Compactor); ok {
+			go runScheduledCompaction(comp, cfg.Compaction)
+		} else {
+			zlog.Warn().Str("driver", driver).Msg(logPrefix + "compaction configured but the backend doesn't support it")
+		}
 	}
-	return r.db.Update(func(tx *bbolt.Tx) error {
-		b := tx.Bucket(_defaultBucket)
-		return b.Put(key, val)
-	})
-}
 
-func (r *recorder) Delete(ctx context.Context, key []byte) error {
-	if r == nil {
-		return ErrNotEnabled
+	if cfg.Encryption.Enabled {
+		return newEncrypted(rec, cfg.Encryption)
 	}
-	return r.db.Update(func(tx *bbolt.Tx) error {
-		b := tx.Bucket(_defaultBucket)
-		return b.Delete(key)
-	})
-}
-
-func (r *recorder) Close() error {
-	zlog.Info().Msg(logPrefix + "closing")
-	return r.db.Close()
+	return rec, nil
 }
 
 func (e err) Error() string {
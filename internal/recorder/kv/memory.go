@@ -0,0 +1,121 @@
+package kv
+
+import (
+	"context"
+	"sync"
+
+	zlog "github.com/rs/zerolog/log"
+)
+
+// memoryRecorder keeps records in process memory only. It satisfies the same
+// contract as the persistent backends so deployments that don't need state to
+// survive restarts (or that test against the recorder) can run without a
+// database file.
+type memoryRecorder struct {
+	mutex   sync.RWMutex
+	buckets map[string]map[string][]byte
+}
+
+func newMemory(_ kvCfg) (Recorder, error) {
+	zlog.Info().Str("driver", DriverMemory).Msg(logPrefix + "initialized")
+	return &memoryRecorder{
+		buckets: make(map[string]map[string][]byte),
+	}, nil
+}
+
+func (r *memoryRecorder) ListKeys(ctx context.Context) ([][]byte, error) {
+	return r.ListKeysIn(ctx, "")
+}
+
+func (r *memoryRecorder) Get(ctx context.Context, key []byte) ([]byte, error) {
+	return r.GetIn(ctx, "", key)
+}
+
+func (r *memoryRecorder) Set(ctx context.Context, key, val []byte) error {
+	return r.SetIn(ctx, "", key, val)
+}
+
+func (r *memoryRecorder) Delete(ctx context.Context, key []byte) error {
+	return r.DeleteIn(ctx, "", key)
+}
+
+func (r *memoryRecorder) ListKeysIn(_ context.Context, bucket string) ([][]byte, error) {
+	if r == nil {
+		return nil, ErrNotEnabled
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	b := r.buckets[bucket]
+	keys := make([][]byte, 0, len(b))
+	for k := range b {
+		keys = append(keys, []byte(k))
+	}
+	return keys, nil
+}
+
+func (r *memoryRecorder) GetIn(_ context.Context, bucket string, key []byte) ([]byte, error) {
+	if r == nil {
+		return nil, ErrNotFound
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	val, ok := r.buckets[bucket][string(key)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return val, nil
+}
+
+func (r *memoryRecorder) SetIn(_ context.Context, bucket string, key, val []byte) error {
+	if r == nil {
+		return ErrNotEnabled
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	b, ok := r.buckets[bucket]
+	if !ok {
+		b = make(map[string][]byte)
+		r.buckets[bucket] = b
+	}
+	b[string(key)] = val
+	return nil
+}
+
+func (r *memoryRecorder) DeleteIn(_ context.Context, bucket string, key []byte) error {
+	if r == nil {
+		return ErrNotEnabled
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.buckets[bucket], string(key))
+	return nil
+}
+
+func (r *memoryRecorder) ListBuckets(_ context.Context) ([]string, error) {
+	if r == nil {
+		return nil, ErrNotEnabled
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	buckets := make([]string, 0, len(r.buckets))
+	for b := range r.buckets {
+		buckets = append(buckets, b)
+	}
+	return buckets, nil
+}
+
+func (r *memoryRecorder) Ping(_ context.Context) error {
+	if r == nil {
+		return ErrNotEnabled
+	}
+	return nil
+}
+
+func (r *memoryRecorder) Close() error {
+	return nil
+}
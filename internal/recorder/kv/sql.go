@@ -0,0 +1,267 @@
+package kv
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	// the sql driver supports Postgres out of the box; other database/sql
+	// drivers can be linked in by embedders
+	_ "github.com/lib/pq"
+	zlog "github.com/rs/zerolog/log"
+)
+
+type (
+	// Archiver is implemented by backends that can keep a queryable archive
+	// of published events next to the KV records. Callers must feature-check
+	// with a type assertion since not every driver supports it.
+	Archiver interface {
+		ArchiveEvent(ctx context.Context, ev ArchivedEvent) error
+		QueryArchive(ctx context.Context, pubsubID int64, from, to time.Time, limit int) ([]ArchivedEvent, error)
+	}
+
+	// ArchivedEvent is one row of the event archive.
+	ArchivedEvent struct {
+		PubSubID    int64
+		EventID     string
+		EventType   string
+		Data        []byte
+		PublishedAt time.Time
+	}
+
+	sqlRecorder struct {
+		db        *sql.DB
+		retention time.Duration
+	}
+)
+
+const (
+	// DriverSQL stores records and the event archive in a SQL database
+	// (Postgres by default), enabling deployments where bbolt's single-file
+	// model is not an option.
+	DriverSQL = "sql"
+
+	// defaultArchiveRetention applies when `archiveRetention` is unset
+	defaultArchiveRetention = 7 * 24 * time.Hour
+
+	// archiveSweepFrequency is how often expired archive rows are deleted
+	archiveSweepFrequency = time.Hour
+)
+
+func newSQL(cfg kvCfg) (Recorder, error) {
+	driver := cfg.SQLDriver
+	if driver == "" {
+		driver = "postgres"
+	}
+	db, err := sql.Open(driver, cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+
+	if err := migrateSQL(db); err != nil {
+		return nil, err
+	}
+
+	retention := cfg.ArchiveRetention
+	if retention <= 0 {
+		retention = defaultArchiveRetention
+	}
+
+	r := &sqlRecorder{db: db, retention: retention}
+	go r.runArchiveSweeper()
+
+	zlog.Info().Str("driver", DriverSQL).Str("sqlDriver", driver).Msg(logPrefix + "initialized")
+	return r, nil
+}
+
+// migrateSQL creates the KV and archive tables; statements are idempotent so
+// every start converges on the current schema
+func migrateSQL(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS kv_records (
+			bucket TEXT NOT NULL,
+			key BYTEA NOT NULL,
+			val BYTEA NOT NULL,
+			PRIMARY KEY (bucket, key)
+		)`,
+		`CREATE TABLE IF NOT EXISTS event_archive (
+			pubsub_id BIGINT NOT NULL,
+			event_id TEXT NOT NULL,
+			event_type TEXT NOT NULL DEFAULT '',
+			data BYTEA NOT NULL,
+			published_at TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (pubsub_id, event_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS event_archive_published_at
+			ON event_archive (pubsub_id, published_at)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *sqlRecorder) ListKeys(ctx context.Context) ([][]byte, error) {
+	return r.ListKeysIn(ctx, "")
+}
+
+func (r *sqlRecorder) Get(ctx context.Context, key []byte) ([]byte, error) {
+	return r.GetIn(ctx, "", key)
+}
+
+func (r *sqlRecorder) Set(ctx context.Context, key, val []byte) error {
+	return r.SetIn(ctx, "", key, val)
+}
+
+func (r *sqlRecorder) Delete(ctx context.Context, key []byte) error {
+	return r.DeleteIn(ctx, "", key)
+}
+
+func (r *sqlRecorder) ListKeysIn(ctx context.Context, bucket string) ([][]byte, error) {
+	if r == nil {
+		return nil, ErrNotEnabled
+	}
+	rows, err := r.db.QueryContext(ctx, `SELECT key FROM kv_records WHERE bucket = $1`, bucket)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys [][]byte
+	for rows.Next() {
+		var k []byte
+		if err := rows.Scan(&k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+func (r *sqlRecorder) GetIn(ctx context.Context, bucket string, key []byte) ([]byte, error) {
+	if r == nil {
+		return nil, ErrNotFound
+	}
+	var val []byte
+	err := r.db.QueryRowContext(ctx,
+		`SELECT val FROM kv_records WHERE bucket = $1 AND key = $2`, bucket, key).Scan(&val)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+func (r *sqlRecorder) SetIn(ctx context.Context, bucket string, key, val []byte) error {
+	if r == nil {
+		return ErrNotEnabled
+	}
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO kv_records (bucket, key, val) VALUES ($1, $2, $3)
+		ON CONFLICT (bucket, key) DO UPDATE SET val = EXCLUDED.val`, bucket, key, val)
+	return err
+}
+
+func (r *sqlRecorder) DeleteIn(ctx context.Context, bucket string, key []byte) error {
+	if r == nil {
+		return ErrNotEnabled
+	}
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM kv_records WHERE bucket = $1 AND key = $2`, bucket, key)
+	return err
+}
+
+func (r *sqlRecorder) ListBuckets(ctx context.Context) ([]string, error) {
+	if r == nil {
+		return nil, ErrNotEnabled
+	}
+	rows, err := r.db.QueryContext(ctx, `SELECT DISTINCT bucket FROM kv_records`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var buckets []string
+	for rows.Next() {
+		var b string
+		if err := rows.Scan(&b); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
+
+func (r *sqlRecorder) ArchiveEvent(ctx context.Context, ev ArchivedEvent) error {
+	if r == nil {
+		return ErrNotEnabled
+	}
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO event_archive (pubsub_id, event_id, event_type, data, published_at)
+		VALUES ($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING`,
+		ev.PubSubID, ev.EventID, ev.EventType, ev.Data, ev.PublishedAt)
+	return err
+}
+
+func (r *sqlRecorder) QueryArchive(ctx context.Context, pubsubID int64, from, to time.Time, limit int) ([]ArchivedEvent, error) {
+	if r == nil {
+		return nil, ErrNotEnabled
+	}
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT pubsub_id, event_id, event_type, data, published_at FROM event_archive
+		WHERE pubsub_id = $1 AND published_at >= $2 AND published_at <= $3
+		ORDER BY published_at ASC LIMIT $4`, pubsubID, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []ArchivedEvent
+	for rows.Next() {
+		var ev ArchivedEvent
+		if err := rows.Scan(&ev.PubSubID, &ev.EventID, &ev.EventType, &ev.Data, &ev.PublishedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
+
+// runArchiveSweeper deletes archive rows older than the retention window
+func (r *sqlRecorder) runArchiveSweeper() {
+	ticker := time.NewTicker(archiveSweepFrequency)
+	defer ticker.Stop()
+	for range ticker.C {
+		cutoff := time.Now().UTC().Add(-r.retention)
+		res, err := r.db.Exec(`DELETE FROM event_archive WHERE published_at < $1`, cutoff)
+		if err != nil {
+			zlog.Error().Err(err).Msg(logPrefix + "failed to sweep expired archive rows")
+			continue
+		}
+		if n, err := res.RowsAffected(); err == nil && n > 0 {
+			zlog.Info().Int64("rows", n).Msg(logPrefix + "swept expired archive rows")
+		}
+	}
+}
+
+// Ping defers to the connection pool, which already redials dead
+// connections on its own.
+func (r *sqlRecorder) Ping(ctx context.Context) error {
+	if r == nil {
+		return ErrNotEnabled
+	}
+	return r.db.PingContext(ctx)
+}
+
+func (r *sqlRecorder) Close() error {
+	zlog.Info().Msg(logPrefix + "closing")
+	return r.db.Close()
+}
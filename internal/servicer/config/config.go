@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"gopkg.in/yaml.v3"
 )
@@ -28,13 +29,18 @@ type (
 		Env() string
 		App() string
 		Version() string
+		Reload() error
+		OnReload(fn func())
 	}
 
 	servicer struct {
-		content map[string][]byte
-		env     string
-		app     string
-		version string
+		content   map[string][]byte
+		env       string
+		app       string
+		version   string
+		static    bool
+		mutex     sync.RWMutex
+		listeners []func()
 	}
 
 	appConfig struct {
@@ -45,6 +51,72 @@ type (
 
 // New inits a new Config based on env name
 func New() (Servicer, error) {
+	env := env()
+	content, err := load(env)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &servicer{
+		content: content,
+		env:     env,
+	}
+
+	// overlay the shared remote document (if configured) before any section
+	// is read, so startup fails fast when the source of truth is unreachable
+	if merged, err := s.applyRemote(content); err != nil {
+		return nil, err
+	} else {
+		s.content = merged
+	}
+	if rc := s.remoteCfg(content); rc.URL != "" && rc.RefreshInterval > 0 {
+		s.startRemoteRefresh(rc.RefreshInterval)
+	}
+
+	var appCfg appConfig
+	err = s.Populate(cfgKey, &appCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if appCfg.Name == "" || appCfg.Version == "" {
+		return nil, ErrMissingAppConfig
+	}
+
+	s.app = appCfg.Name
+	s.version = appCfg.Version
+
+	return s, nil
+}
+
+// NewStatic builds a Servicer from programmatic values so that sser can be
+// embedded without yaml files; sections map section keys (e.g. "pubsub",
+// "server") to values shaped like their yaml counterparts
+func NewStatic(app, version, env string, sections map[string]interface{}) (Servicer, error) {
+	if app == "" || version == "" {
+		return nil, ErrMissingAppConfig
+	}
+
+	content := make(map[string][]byte, len(sections))
+	for k, v := range sections {
+		body, err := yaml.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		content[k] = body
+	}
+
+	return &servicer{
+		content: content,
+		env:     env,
+		app:     app,
+		version: version,
+		static:  true,
+	}, nil
+}
+
+// load reads and merges the base and env yaml files into per-section contents
+func load(env string) (map[string][]byte, error) {
 	// read base yaml file
 	basefilename, err := filepath.Abs(configPath + "/base.yaml")
 	if err != nil {
@@ -61,7 +133,6 @@ func New() (Servicer, error) {
 	}
 
 	// read env yaml file
-	env := env()
 	envfilename, err := filepath.Abs(fmt.Sprintf(configPath+"/%s.yaml", env))
 	if err != nil {
 		return nil, err
@@ -82,22 +153,7 @@ func New() (Servicer, error) {
 	}
 
 	// expand env vars
-	mapper := func(placeholderName string) string {
-		split := strings.Split(placeholderName, ":")
-		defValue := ""
-		if len(split) == 2 {
-			placeholderName = split[0]
-			defValue = split[1]
-		}
-
-		val, ok := os.LookupEnv(placeholderName)
-		if !ok {
-			return defValue
-		}
-
-		return val
-	}
-	body := []byte(os.Expand(string(yamlFile), mapper))
+	body := []byte(os.Expand(string(yamlFile), envMapper))
 
 	// parse into a generic map
 	var cfg map[string]interface{}
@@ -110,35 +166,89 @@ func New() (Servicer, error) {
 		content[k], _ = yaml.Marshal(v)
 	}
 
-	s := &servicer{
-		content: content,
-		env:     env,
-	}
+	return content, nil
+}
 
-	var appCfg appConfig
-	err = s.Populate(cfgKey, &appCfg)
-	if err != nil {
-		return nil, err
+// envMapper resolves ${NAME:default} placeholders against the environment
+func envMapper(placeholderName string) string {
+	split := strings.Split(placeholderName, ":")
+	defValue := ""
+	if len(split) == 2 {
+		placeholderName = split[0]
+		defValue = split[1]
 	}
 
-	if appCfg.Name == "" || appCfg.Version == "" {
-		return nil, ErrMissingAppConfig
+	val, ok := os.LookupEnv(placeholderName)
+	if !ok {
+		return defValue
 	}
 
-	s.app = appCfg.Name
-	s.version = appCfg.Version
-
-	return s, nil
+	return val
 }
 
 // Populate populates configuration
 func (s *servicer) Populate(key string, cfg interface{}) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	if err := yaml.Unmarshal(s.content[key], cfg); err != nil {
 		return err
 	}
 	return nil
 }
 
+// Reload re-reads the yaml files and notifies registered listeners so that
+// subsystems can apply the changes without a restart
+func (s *servicer) Reload() error {
+	if s.static {
+		// programmatic configs have no files backing them
+		return nil
+	}
+
+	content, err := load(s.Env())
+	if err != nil {
+		return err
+	}
+	content, err = s.applyRemote(content)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	if contentEqual(s.content, content) {
+		// periodic remote refreshes land here most of the time; don't churn
+		// the listeners when nothing changed
+		s.mutex.Unlock()
+		return nil
+	}
+	s.content = content
+	listeners := s.listeners
+	s.mutex.Unlock()
+
+	for _, fn := range listeners {
+		fn()
+	}
+	return nil
+}
+
+func contentEqual(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if string(b[k]) != string(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// OnReload registers a listener that runs after each successful Reload
+func (s *servicer) OnReload(fn func()) {
+	s.mutex.Lock()
+	s.listeners = append(s.listeners, fn)
+	s.mutex.Unlock()
+}
+
 // Env return current config environment
 func (s *servicer) Env() string {
 	if s.env != "" {
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	remoteCfgKey = "remote"
+
+	// defaultRemoteTimeout bounds a single fetch so a hung config backend
+	// cannot stall startup or a refresh tick indefinitely
+	defaultRemoteTimeout = 10 * time.Second
+
+	logPrefix = "[config] "
+)
+
+// remoteConfig is the local bootstrap block pointing at a shared config
+// document; the URL form covers etcd and consul KV HTTP endpoints as well as
+// S3 objects (presigned or public), so fleets share one source of truth for
+// static pubsubs and tokens without baking yaml into images.
+type remoteConfig struct {
+	// URL of a yaml document shaped like the local config files; empty
+	// disables remote loading
+	URL string `yaml:"url"`
+	// Headers are sent with every fetch, e.g. an Authorization header
+	Headers map[string]string `yaml:"headers"`
+	// RefreshInterval re-fetches and re-merges periodically; 0 fetches only
+	// at startup and on SIGHUP reloads
+	RefreshInterval time.Duration `yaml:"refreshInterval"`
+	// Timeout bounds one fetch; defaults to 10s
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// fetchRemote downloads and parses the remote yaml document; env var
+// placeholders are expanded the same way as in the local files.
+func fetchRemote(rc remoteConfig) (map[string]interface{}, error) {
+	timeout := rc.Timeout
+	if timeout <= 0 {
+		timeout = defaultRemoteTimeout
+	}
+	client := &http.Client{Timeout: timeout}
+
+	req, err := http.NewRequest(http.MethodGet, rc.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range rc.Headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote config fetch returned status %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	body = []byte(os.Expand(string(body), envMapper))
+
+	cfg := map[string]interface{}{}
+	if err := yaml.Unmarshal(body, &cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// mergeRemote overlays the remote document on the local per-section contents;
+// remote values win, section by section, with nested maps merged the same way
+// the env yaml overlays the base yaml.
+func mergeRemote(content map[string][]byte, remote map[string]interface{}) map[string][]byte {
+	out := make(map[string][]byte, len(content))
+	for k, v := range content {
+		out[k] = v
+	}
+	for k, v := range remote {
+		rv, ok := v.(map[string]interface{})
+		if !ok {
+			out[k], _ = yaml.Marshal(v)
+			continue
+		}
+		local := map[string]interface{}{}
+		if body, exists := out[k]; exists {
+			_ = yaml.Unmarshal(body, &local)
+		}
+		out[k], _ = yaml.Marshal(mergeMaps(local, rv))
+	}
+	return out
+}
+
+// applyRemote fetches and merges the remote document into the given local
+// contents; it is called from New, Reload and the refresh loop.
+func (s *servicer) applyRemote(content map[string][]byte) (map[string][]byte, error) {
+	rc := s.remoteCfg(content)
+	if rc.URL == "" {
+		return content, nil
+	}
+	remote, err := fetchRemote(rc)
+	if err != nil {
+		return nil, err
+	}
+	return mergeRemote(content, remote), nil
+}
+
+// remoteCfg reads the bootstrap block out of freshly loaded local contents
+func (s *servicer) remoteCfg(content map[string][]byte) remoteConfig {
+	var rc remoteConfig
+	_ = yaml.Unmarshal(content[remoteCfgKey], &rc)
+	return rc
+}
+
+// startRemoteRefresh periodically re-fetches the remote document and notifies
+// reload listeners when it changes; fetch failures keep the previous config.
+func (s *servicer) startRemoteRefresh(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			if err := s.Reload(); err != nil {
+				zlog.Error().Err(err).Msg(logPrefix + "remote config refresh failed; keeping the previous configuration")
+			}
+		}
+	}()
+	zlog.Info().Dur("interval", interval).Msg(logPrefix + "remote config refresh started")
+}
@@ -0,0 +1,256 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type (
+	// ValidationIssue is one problem found in the merged configuration.
+	ValidationIssue struct {
+		Section string
+		Field   string
+		Reason  string
+	}
+
+	// ValidationReport collects everything Validate found; an empty report
+	// means the configuration is safe to boot with.
+	ValidationReport struct {
+		Issues []ValidationIssue
+	}
+
+	// the structs below mirror only the fields the cross-checks need; each
+	// subsystem keeps owning its full config shape and yaml decoding ignores
+	// whatever is not mirrored here
+
+	validationServerConfig struct {
+		Addr string    `yaml:"addr"`
+		SSL  sslConfig `yaml:"ssl"`
+	}
+
+	sslConfig struct {
+		Enabled          bool   `yaml:"enabled"`
+		DomainName       string `yaml:"domainName"`
+		LetsencryptEmail string `yaml:"letsencryptEmail"`
+		CertFile         string `yaml:"certFile"`
+		KeyFile          string `yaml:"keyFile"`
+	}
+
+	validationPubSubConfig struct {
+		ApiAccessToken     string `yaml:"apiAccessToken"`
+		MetricsAccessToken string `yaml:"metricsAccessToken"`
+		StaticPubSubs      []struct {
+			ID    int64  `yaml:"id"`
+			Name  string `yaml:"name"`
+			Token string `yaml:"token"`
+		} `yaml:"staticPubSubs"`
+		Tenants []struct {
+			Name           string `yaml:"name"`
+			ApiAccessToken string `yaml:"apiAccessToken"`
+		} `yaml:"tenants"`
+		Routes []struct {
+			From int64 `yaml:"from"`
+			To   int64 `yaml:"to"`
+		} `yaml:"routes"`
+	}
+
+	validationKvConfig struct {
+		Enabled    bool   `yaml:"enabled"`
+		Driver     string `yaml:"driver"`
+		DSN        string `yaml:"dsn"`
+		Encryption struct {
+			Enabled   bool   `yaml:"enabled"`
+			ActiveKey string `yaml:"activeKey"`
+			Keys      []struct {
+				ID     string `yaml:"id"`
+				Secret string `yaml:"secret"`
+			} `yaml:"keys"`
+		} `yaml:"encryption"`
+	}
+
+	validationIdgenConfig struct {
+		EpochTimeInSeconds int64 `yaml:"epochTimeInSeconds"`
+		NodeBits           int   `yaml:"nodeBits"`
+	}
+)
+
+// OK reports whether the configuration passed every check.
+func (r ValidationReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// String renders the report one issue per line for the startup log or the
+// -validate-config console output.
+func (r ValidationReport) String() string {
+	if r.OK() {
+		return "configuration ok"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d configuration issue(s):\n", len(r.Issues))
+	for _, i := range r.Issues {
+		field := i.Section
+		if i.Field != "" {
+			field += "." + i.Field
+		}
+		fmt.Fprintf(&b, "  %s: %s\n", field, i.Reason)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (r *ValidationReport) add(section, field, reason string) {
+	r.Issues = append(r.Issues, ValidationIssue{Section: section, Field: field, Reason: reason})
+}
+
+// Validate parses every known section and cross-checks them, so broken
+// configuration fails fast with a readable report instead of panicking deep
+// inside a subsystem at runtime.
+func Validate(s Servicer) ValidationReport {
+	var report ValidationReport
+
+	validateServer(s, &report)
+	validatePubSub(s, &report)
+	validateKv(s, &report)
+	validateIdgen(s, &report)
+
+	return report
+}
+
+func validateServer(s Servicer, report *ValidationReport) {
+	var cfg validationServerConfig
+	if err := s.Populate("server", &cfg); err != nil {
+		report.add("server", "", err.Error())
+		return
+	}
+	if cfg.Addr == "" {
+		report.add("server", "addr", "listen address must be set")
+	}
+	if cfg.SSL.Enabled {
+		letsencrypt := cfg.SSL.DomainName != "" && cfg.SSL.LetsencryptEmail != ""
+		files := cfg.SSL.CertFile != "" && cfg.SSL.KeyFile != ""
+		if !letsencrypt && !files {
+			report.add("server", "ssl", "either domainName+letsencryptEmail or certFile+keyFile must be set when ssl is enabled")
+		}
+	}
+}
+
+func validatePubSub(s Servicer, report *ValidationReport) {
+	var cfg validationPubSubConfig
+	if err := s.Populate("pubsub", &cfg); err != nil {
+		report.add("pubsub", "", err.Error())
+		return
+	}
+	if cfg.ApiAccessToken == "" {
+		report.add("pubsub", "apiAccessToken", "must be set; topic management would be open to anyone")
+	}
+	if cfg.MetricsAccessToken == "" {
+		report.add("pubsub", "metricsAccessToken", "must be set; metrics would be unreadable")
+	}
+
+	ids := make(map[int64]int, len(cfg.StaticPubSubs))
+	names := make(map[string]int, len(cfg.StaticPubSubs))
+	for i, sp := range cfg.StaticPubSubs {
+		field := fmt.Sprintf("staticPubSubs[%d]", i)
+		if sp.ID <= 0 {
+			report.add("pubsub", field+".id", "must be a positive integer")
+		}
+		if sp.Token == "" {
+			report.add("pubsub", field+".token", "must be set")
+		}
+		if prev, ok := ids[sp.ID]; ok {
+			report.add("pubsub", field+".id", fmt.Sprintf("collides with staticPubSubs[%d]", prev))
+		}
+		ids[sp.ID] = i
+		if sp.Name != "" {
+			if prev, ok := names[sp.Name]; ok {
+				report.add("pubsub", field+".name", fmt.Sprintf("collides with staticPubSubs[%d]", prev))
+			}
+			names[sp.Name] = i
+		}
+	}
+
+	tokens := make(map[string]int, len(cfg.Tenants))
+	for i, t := range cfg.Tenants {
+		field := fmt.Sprintf("tenants[%d]", i)
+		if t.Name == "" {
+			report.add("pubsub", field+".name", "must be set")
+		}
+		if t.ApiAccessToken == "" {
+			report.add("pubsub", field+".apiAccessToken", "must be set")
+		} else {
+			if prev, ok := tokens[t.ApiAccessToken]; ok {
+				report.add("pubsub", field+".apiAccessToken", fmt.Sprintf("collides with tenants[%d]", prev))
+			}
+			tokens[t.ApiAccessToken] = i
+			if t.ApiAccessToken == cfg.ApiAccessToken {
+				report.add("pubsub", field+".apiAccessToken", "collides with the root apiAccessToken")
+			}
+		}
+	}
+
+	for i, rt := range cfg.Routes {
+		field := fmt.Sprintf("routes[%d]", i)
+		if rt.From <= 0 || rt.To <= 0 {
+			report.add("pubsub", field, "from and to must be positive pubsub ids")
+		}
+		if rt.From == rt.To {
+			report.add("pubsub", field, "routes a topic into itself")
+		}
+	}
+}
+
+func validateKv(s Servicer, report *ValidationReport) {
+	var cfg validationKvConfig
+	if err := s.Populate("kv", &cfg); err != nil {
+		report.add("kv", "", err.Error())
+		return
+	}
+	if !cfg.Enabled {
+		return
+	}
+	switch cfg.Driver {
+	case "", "bbolt", "memory", "sql":
+	default:
+		report.add("kv", "driver", fmt.Sprintf("unknown driver %q", cfg.Driver))
+	}
+	if cfg.Driver == "sql" && cfg.DSN == "" {
+		report.add("kv", "dsn", "must be set for the sql driver")
+	}
+	if cfg.Encryption.Enabled {
+		if len(cfg.Encryption.Keys) == 0 {
+			report.add("kv", "encryption.keys", "at least one key must be configured when encryption is enabled")
+		}
+		found := cfg.Encryption.ActiveKey == ""
+		for i, k := range cfg.Encryption.Keys {
+			field := fmt.Sprintf("encryption.keys[%d]", i)
+			if k.ID == "" {
+				report.add("kv", field+".id", "must be set")
+			}
+			if k.Secret == "" {
+				report.add("kv", field+".secret", "must be set")
+			}
+			if k.ID == cfg.Encryption.ActiveKey {
+				found = true
+			}
+		}
+		if !found {
+			report.add("kv", "encryption.activeKey", fmt.Sprintf("%q is not among the configured keys", cfg.Encryption.ActiveKey))
+		}
+	}
+}
+
+func validateIdgen(s Servicer, report *ValidationReport) {
+	var cfg validationIdgenConfig
+	if err := s.Populate("idgen", &cfg); err != nil {
+		report.add("idgen", "", err.Error())
+		return
+	}
+	if cfg.EpochTimeInSeconds <= 0 {
+		report.add("idgen", "epochTimeInSeconds", "must be a positive unix timestamp")
+	} else if cfg.EpochTimeInSeconds > time.Now().Unix() {
+		report.add("idgen", "epochTimeInSeconds", "lies in the future; generated ids would not be monotonic")
+	}
+	if cfg.NodeBits < 0 || cfg.NodeBits > 16 {
+		report.add("idgen", "nodeBits", "must be between 0 and 16")
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"net"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+type (
+	// deadlineListener hands out connections whose write deadline is bumped
+	// on every write. The server-wide WriteTimeout is a single deadline per
+	// response, which either kills long-lived SSE streams or has to be so
+	// large it never catches a stalled client; the per-write deadline gives
+	// each flush its own budget instead.
+	deadlineListener struct {
+		net.Listener
+		timeout time.Duration
+	}
+
+	deadlineConn struct {
+		net.Conn
+		timeout time.Duration
+	}
+)
+
+// newDeadlineListener wraps ln so every write refreshes the connection's
+// write deadline; a zero timeout returns ln unchanged
+func newDeadlineListener(ln net.Listener, timeout time.Duration) net.Listener {
+	if timeout <= 0 {
+		return ln
+	}
+	return &deadlineListener{
+		Listener: ln,
+		timeout:  timeout,
+	}
+}
+
+func (l *deadlineListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &deadlineConn{Conn: conn, timeout: l.timeout}, nil
+}
+
+func (c *deadlineConn) Write(b []byte) (int, error) {
+	_ = c.Conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	return c.Conn.Write(b)
+}
+
+// withAPITimeout bounds handler execution for plain API calls. Streaming
+// endpoints install their body writer and return immediately, so long-lived
+// SSE streams never see this timeout — only stalled request handling does.
+func withAPITimeout(h fasthttp.RequestHandler, timeout time.Duration) fasthttp.RequestHandler {
+	if timeout <= 0 || h == nil {
+		return h
+	}
+	return fasthttp.TimeoutWithCodeHandler(h,
+		timeout,
+		`{"error": {"code": 408, "message": "Request timeout"}}`,
+		fasthttp.StatusRequestTimeout)
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
@@ -20,20 +21,33 @@ type (
 	}
 
 	servicer struct {
-		cfg     serverConfig
-		server  *fasthttp.Server
-		acmesrv *http.Server
+		cfg      serverConfig
+		server   *fasthttp.Server
+		adminsrv *fasthttp.Server
+		netsrv   *http.Server
+		acmesrv  *http.Server
 	}
 
 	Params struct {
 		Config  config.Servicer
 		Handler fasthttp.RequestHandler
+		// PublicHandler and AdminHandler back the split-listener mode: when
+		// adminAddr is configured the main listener serves PublicHandler and
+		// a second plain listener serves AdminHandler, keeping management
+		// endpoints off the exposed port
+		PublicHandler fasthttp.RequestHandler
+		AdminHandler  fasthttp.RequestHandler
 	}
 
 	serverConfig struct {
 		Name                         string        `yaml:"name"`
+		Mode                         string        `yaml:"mode"`
+		Network                      string        `yaml:"network"`
 		Addr                         string        `yaml:"addr"`
 		SSLAddr                      string        `yaml:"sslAddr"`
+		AdminAddr                    string        `yaml:"adminAddr"`
+		APITimeout                   time.Duration `yaml:"apiTimeout"`
+		StreamWriteTimeout           time.Duration `yaml:"streamWriteTimeout"`
 		IdleTimeout                  time.Duration `yaml:"idleTimeout"`
 		ReadTimeout                  time.Duration `yaml:"readTimeout"`
 		WriteTimeout                 time.Duration `yaml:"writeTimeout"`
@@ -43,14 +57,21 @@ type (
 		NoDefaultDate                bool          `yaml:"noDefaultDate"`
 		CloseOnShutdown              bool          `yaml:"closeOnShutdown"`
 		DisablePreParseMultipartForm bool          `yaml:"disablePreParseMultipartForm"`
+		MaxConcurrentConnections     int           `yaml:"maxConcurrentConnections"`
+		RlimitHeadroom               int           `yaml:"rlimitHeadroom"`
 		SSL                          SslConfig     `yaml:"ssl"`
 	}
 
 	SslConfig struct {
-		Enabled          bool   `yaml:"enabled"`
-		DomainName       string `yaml:"domainName"`
-		LetsencryptEmail string `yaml:"letsencryptEmail"`
-		CacheDir         string `yaml:"cacheDir"`
+		Enabled          bool          `yaml:"enabled"`
+		DomainName       string        `yaml:"domainName"`
+		LetsencryptEmail string        `yaml:"letsencryptEmail"`
+		CacheDir         string        `yaml:"cacheDir"`
+		CertFile         string        `yaml:"certFile"`
+		KeyFile          string        `yaml:"keyFile"`
+		CertReloadFreq   time.Duration `yaml:"certReloadFrequency"`
+		ClientCAFile     string        `yaml:"clientCAFile"`
+		ClientAuth       string        `yaml:"clientAuth"`
 	}
 )
 
@@ -58,6 +79,11 @@ const (
 	cfgKey = "server"
 
 	logPrefix = "[httpserver] "
+
+	// modeFastHTTP is the default HTTP/1.1 fasthttp listener; modeNetHTTP
+	// serves through net/http, which negotiates HTTP/2 over TLS via ALPN
+	modeFastHTTP = "fasthttp"
+	modeNetHTTP  = "nethttp"
 )
 
 func New(p Params) (Servicer, error) {
@@ -66,9 +92,38 @@ func New(p Params) (Servicer, error) {
 		return nil, err
 	}
 
+	switch cfg.Mode {
+	case "", modeFastHTTP, modeNetHTTP:
+	default:
+		return nil, fmt.Errorf("%sunknown server mode: %s", logPrefix, cfg.Mode)
+	}
+
+	switch cfg.Network {
+	case "", networkTCP, networkUnix, networkSystemd:
+	default:
+		return nil, fmt.Errorf("%sunknown network: %s", logPrefix, cfg.Network)
+	}
+
+	handler := p.Handler
+	var adminsrv *fasthttp.Server
+	if cfg.AdminAddr != "" {
+		if p.PublicHandler == nil || p.AdminHandler == nil {
+			return nil, fmt.Errorf("%sadminAddr is set but the handler does not provide split public/admin entry points", logPrefix)
+		}
+		handler = p.PublicHandler
+		adminsrv = &fasthttp.Server{
+			Handler:      withAPITimeout(p.AdminHandler, cfg.APITimeout),
+			Name:         cfg.Name,
+			IdleTimeout:  cfg.IdleTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+		}
+	}
+	handler = withAPITimeout(handler, cfg.APITimeout)
+
 	var srv *http.Server
 	server := &fasthttp.Server{
-		Handler:                      p.Handler,
+		Handler:                      withClientCertSubject(handler),
 		Name:                         cfg.Name,
 		IdleTimeout:                  cfg.IdleTimeout,
 		ReadTimeout:                  cfg.ReadTimeout,
@@ -81,48 +136,111 @@ func New(p Params) (Servicer, error) {
 		DisablePreParseMultipartForm: cfg.DisablePreParseMultipartForm,
 	}
 	if cfg.SSL.Enabled {
-		m := autocert.Manager{
-			Prompt:     autocert.AcceptTOS, // Automatically agree to the Let's Encrypt TOS
-			HostPolicy: autocert.HostWhitelist(cfg.SSL.DomainName),
-			Cache:      autocert.DirCache(cfg.SSL.CacheDir), // This enables certificate persistence and initial validity check
-			Email:      cfg.SSL.LetsencryptEmail,
-		}
-
 		addr := cfg.Addr
 		if !strings.HasPrefix(addr, ":") {
 			addr = ":" + addr
 		}
-		srv = &http.Server{
-			Addr:    addr,
-			Handler: m.HTTPHandler(http.HandlerFunc(redirectHTTP)), // nil means default redirect to HTTPS
+
+		tlsConfig := baseTLSConfig()
+
+		if cfg.SSL.CertFile != "" && cfg.SSL.KeyFile != "" {
+			// statically issued certificates (internal/private CAs); the files
+			// are watched so re-issued certs are picked up without a restart
+			reloader, err := newCertReloader(cfg.SSL.CertFile, cfg.SSL.KeyFile)
+			if err != nil {
+				return nil, err
+			}
+			freq := cfg.SSL.CertReloadFreq
+			if freq <= 0 {
+				freq = time.Minute
+			}
+			go reloader.watch(freq)
+
+			tlsConfig.GetCertificate = reloader.getCertificate
+			srv = &http.Server{
+				Addr:    addr,
+				Handler: http.HandlerFunc(redirectHTTP),
+			}
+		} else {
+			m := autocert.Manager{
+				Prompt:     autocert.AcceptTOS, // Automatically agree to the Let's Encrypt TOS
+				HostPolicy: autocert.HostWhitelist(cfg.SSL.DomainName),
+				Cache:      autocert.DirCache(cfg.SSL.CacheDir), // This enables certificate persistence and initial validity check
+				Email:      cfg.SSL.LetsencryptEmail,
+			}
+
+			srv = &http.Server{
+				Addr:    addr,
+				Handler: m.HTTPHandler(http.HandlerFunc(redirectHTTP)), // nil means default redirect to HTTPS
+			}
+
+			tlsConfig.GetCertificate = m.GetCertificate
 		}
 
-		tlsConfig := &tls.Config{
-			GetCertificate: m.GetCertificate,
-			// Secure configuration recommended by Mozilla:
-			MinVersion: tls.VersionTLS12,
-			CurvePreferences: []tls.CurveID{
-				tls.CurveP521,
-				tls.CurveP384,
-				tls.CurveP256,
-			},
-			PreferServerCipherSuites: true,
-			CipherSuites: []uint16{
-				tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-				tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
-				tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
-				tls.TLS_RSA_WITH_AES_256_CBC_SHA,
-			},
+		if cfg.SSL.ClientCAFile != "" {
+			pool, err := loadClientCAs(cfg.SSL.ClientCAFile)
+			if err != nil {
+				return nil, err
+			}
+			authType, err := clientAuthType(cfg.SSL.ClientAuth)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.ClientCAs = pool
+			tlsConfig.ClientAuth = authType
 		}
 
 		server.TLSConfig = tlsConfig
 	}
 
-	return &servicer{
-		cfg:     cfg,
-		server:  server,
-		acmesrv: srv,
-	}, nil
+	var netsrv *http.Server
+	if cfg.Mode == modeNetHTTP {
+		netsrv = &http.Server{
+			Handler:      newNetHTTPHandler(handler),
+			IdleTimeout:  cfg.IdleTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+		}
+		if server.TLSConfig != nil {
+			tlsConfig := server.TLSConfig.Clone()
+			tlsConfig.NextProtos = append([]string{"h2", "http/1.1"}, tlsConfig.NextProtos...)
+			netsrv.TLSConfig = tlsConfig
+		}
+	}
+
+	s := &servicer{
+		cfg:      cfg,
+		server:   server,
+		adminsrv: adminsrv,
+		netsrv:   netsrv,
+		acmesrv:  srv,
+	}
+
+	p.Config.OnReload(func() { s.reloadConfig(p.Config) })
+
+	return s, nil
+}
+
+// reloadConfig re-populates the server section on config reload; listener
+// level settings are bound at startup, so changes there only log a warning
+func (s *servicer) reloadConfig(cfgsrv config.Servicer) {
+	var cfg serverConfig
+	if err := cfgsrv.Populate(cfgKey, &cfg); err != nil {
+		zlog.Error().Err(err).Msg(logPrefix + "failed to re-populate config on reload; keeping the previous one")
+		return
+	}
+
+	if cfg.Addr != s.cfg.Addr || cfg.SSLAddr != s.cfg.SSLAddr || cfg.SSL != s.cfg.SSL {
+		zlog.Warn().Msg(logPrefix + "listener configuration changed; a restart is required to apply it")
+	}
+
+	s.server.ReadTimeout = cfg.ReadTimeout
+	s.server.WriteTimeout = cfg.WriteTimeout
+	s.server.IdleTimeout = cfg.IdleTimeout
+	s.server.MaxRequestBodySize = cfg.MaxRequestBodySize
+	s.cfg = cfg
+
+	zlog.Info().Msg(logPrefix + "configuration reloaded")
 }
 
 func (s *servicer) ListenAndServe() error {
@@ -130,6 +248,24 @@ func (s *servicer) ListenAndServe() error {
 	if !strings.HasPrefix(addr, ":") {
 		addr = ":" + addr
 	}
+	if s.adminsrv != nil {
+		adminAddr := s.cfg.AdminAddr
+		if !strings.HasPrefix(adminAddr, ":") && !strings.Contains(adminAddr, ":") {
+			adminAddr = ":" + adminAddr
+		}
+		go func() {
+			// the admin listener is plain HTTP on an internal port or
+			// interface; TLS termination for management traffic belongs to
+			// the deployment's internal network
+			zlog.Info().Str("addr", adminAddr).Msg(logPrefix + "starting admin listener")
+			if err := s.adminsrv.ListenAndServe(adminAddr); err != nil {
+				zlog.Fatal().Err(err).Msg(logPrefix + "admin listener failed")
+			}
+		}()
+	}
+	if s.netsrv != nil {
+		return s.listenAndServeNetHTTP(addr)
+	}
 	if s.cfg.SSL.Enabled {
 		zlog.Info().Msg(logPrefix + "ssl enabled")
 		sslAddr := s.cfg.SSLAddr
@@ -155,7 +291,64 @@ func (s *servicer) ListenAndServe() error {
 			return err
 		}
 	}
-	return s.server.ListenAndServe(addr)
+
+	ln, err := s.listener(addr)
+	if err != nil {
+		return err
+	}
+	return s.server.Serve(newLimitedListener(newDeadlineListener(ln, s.cfg.StreamWriteTimeout), connectionCeiling(s.cfg)))
+}
+
+// listenAndServeNetHTTP serves through net/http; with SSL enabled the ACME
+// challenge listener stays on the plain port and clients negotiate HTTP/2
+// over the TLS one
+func (s *servicer) listenAndServeNetHTTP(addr string) error {
+	if s.cfg.SSL.Enabled {
+		zlog.Info().Msg(logPrefix + "ssl enabled (nethttp mode)")
+		sslAddr := s.cfg.SSLAddr
+		if !strings.HasPrefix(sslAddr, ":") {
+			sslAddr = ":" + sslAddr
+		}
+
+		go func() {
+			zlog.Info().Str("domainName", s.cfg.SSL.DomainName).Str("addr", addr).
+				Msg(logPrefix + "starting ACME challenge HTTP listener")
+
+			if err := s.acmesrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				zlog.Fatal().Err(err).Msg(logPrefix + "ACME HTTP listener failed")
+			}
+			zlog.Info().Msg(logPrefix + "ACME HTTP listener shut down.")
+		}()
+
+		s.netsrv.Addr = sslAddr
+		return s.netsrv.ListenAndServeTLS("", "")
+	}
+
+	ln, err := s.listener(addr)
+	if err != nil {
+		return err
+	}
+	return s.netsrv.Serve(newLimitedListener(newDeadlineListener(ln, s.cfg.StreamWriteTimeout), connectionCeiling(s.cfg)))
+}
+
+// baseTLSConfig is the secure configuration recommended by Mozilla; the
+// certificate source is filled in by the caller
+func baseTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		CurvePreferences: []tls.CurveID{
+			tls.CurveP521,
+			tls.CurveP384,
+			tls.CurveP256,
+		},
+		PreferServerCipherSuites: true,
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+			tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+		},
+	}
 }
 
 func redirectHTTP(w http.ResponseWriter, r *http.Request) {
@@ -170,5 +363,13 @@ func (s *servicer) Shutdown() error {
 	if s.acmesrv != nil {
 		s.acmesrv.Shutdown(context.Background())
 	}
+	if s.adminsrv != nil {
+		if err := s.adminsrv.Shutdown(); err != nil {
+			zlog.Warn().Err(err).Msg(logPrefix + "admin listener shutdown failed")
+		}
+	}
+	if s.netsrv != nil {
+		return s.netsrv.Shutdown(context.Background())
+	}
 	return s.server.Shutdown()
 }
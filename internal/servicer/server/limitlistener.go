@@ -0,0 +1,102 @@
+package server
+
+import (
+	"net"
+	"sync/atomic"
+	"syscall"
+
+	zlog "github.com/rs/zerolog/log"
+)
+
+// connection accounting is package-level so the metrics endpoint can report
+// saturation without holding a reference to the servicer
+var (
+	openConns     int64
+	connCeiling   int64
+	rejectedConns int64
+)
+
+// ConnectionStats reports the open connection count, the configured ceiling
+// (0 when unlimited) and how many connections were rejected at the ceiling
+func ConnectionStats() (open, limit, rejected int64) {
+	return atomic.LoadInt64(&openConns), atomic.LoadInt64(&connCeiling), atomic.LoadInt64(&rejectedConns)
+}
+
+// connectionCeiling resolves the max concurrent connections: an explicit
+// setting wins, otherwise it is derived from RLIMIT_NOFILE minus headroom so
+// the process degrades with 503s instead of dying on accept errors
+func connectionCeiling(cfg serverConfig) int64 {
+	limit := int64(cfg.MaxConcurrentConnections)
+	if limit <= 0 {
+		var rLimit syscall.Rlimit
+		if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rLimit); err != nil {
+			zlog.Warn().Err(err).Msg(logPrefix + "failed to get rlimit; connection ceiling disabled")
+			return 0
+		}
+		headroom := int64(cfg.RlimitHeadroom)
+		if headroom <= 0 {
+			headroom = 256
+		}
+		limit = int64(rLimit.Cur) - headroom
+	}
+	if limit <= 0 {
+		return 0
+	}
+	return limit
+}
+
+type (
+	limitedListener struct {
+		net.Listener
+		limit int64
+	}
+
+	trackedConn struct {
+		net.Conn
+		closed int32
+	}
+)
+
+// newLimitedListener caps concurrent connections; above the ceiling new
+// connections get an immediate 503 with a Retry-After hint
+func newLimitedListener(ln net.Listener, limit int64) net.Listener {
+	if limit <= 0 {
+		return ln
+	}
+	atomic.StoreInt64(&connCeiling, limit)
+	zlog.Info().Int64("limit", limit).Msg(logPrefix + "connection ceiling enabled")
+	return &limitedListener{
+		Listener: ln,
+		limit:    limit,
+	}
+}
+
+func (l *limitedListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if atomic.LoadInt64(&openConns) >= l.limit {
+			atomic.AddInt64(&rejectedConns, 1)
+			go rejectConn(conn)
+			continue
+		}
+		atomic.AddInt64(&openConns, 1)
+		return &trackedConn{Conn: conn}, nil
+	}
+}
+
+func (c *trackedConn) Close() error {
+	if atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		atomic.AddInt64(&openConns, -1)
+	}
+	return c.Conn.Close()
+}
+
+// rejectConn answers with a minimal 503 so well-behaved clients back off
+// instead of retrying immediately
+func rejectConn(conn net.Conn) {
+	defer conn.Close()
+	_, _ = conn.Write([]byte("HTTP/1.1 503 Service Unavailable\r\nRetry-After: 5\r\nConnection: close\r\nContent-Length: 0\r\n\r\n"))
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	zlog "github.com/rs/zerolog/log"
+)
+
+const (
+	// networkTCP listens on a TCP address (the default); networkUnix listens
+	// on a unix domain socket at the configured addr, and networkSystemd
+	// accepts on the socket inherited from systemd socket activation
+	networkTCP     = "tcp"
+	networkUnix    = "unix"
+	networkSystemd = "systemd"
+)
+
+// listener builds the accept socket for the configured network; tcpAddr is
+// the normalized ":port" form used for TCP listeners
+func (s *servicer) listener(tcpAddr string) (net.Listener, error) {
+	switch s.cfg.Network {
+	case "", networkTCP:
+		return net.Listen("tcp4", tcpAddr)
+	case networkUnix:
+		path := s.cfg.Addr
+		if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+			// a previous run may have left a stale socket behind
+			if err := os.Remove(path); err != nil {
+				return nil, fmt.Errorf("%sfailed to remove stale unix socket: %w", logPrefix, err)
+			}
+		}
+		zlog.Info().Str("path", path).Msg(logPrefix + "listening on unix socket")
+		return net.Listen("unix", path)
+	case networkSystemd:
+		return systemdListener()
+	}
+	return nil, fmt.Errorf("%sunknown network: %s", logPrefix, s.cfg.Network)
+}
+
+// systemdListener adopts the first socket passed via systemd socket
+// activation (LISTEN_FDS protocol, fds start at 3)
+func systemdListener() (net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, fmt.Errorf("%sLISTEN_PID doesn't match this process; not socket activated", logPrefix)
+	}
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || fds < 1 {
+		return nil, fmt.Errorf("%sLISTEN_FDS reports no inherited sockets", logPrefix)
+	}
+
+	f := os.NewFile(3, "systemd-socket")
+	defer f.Close()
+	ln, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("%sfailed to adopt systemd socket: %w", logPrefix, err)
+	}
+	zlog.Info().Str("addr", ln.Addr().String()).Msg(logPrefix + "listening on systemd-activated socket")
+	return ln, nil
+}
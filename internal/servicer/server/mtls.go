@@ -0,0 +1,58 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/valyala/fasthttp"
+)
+
+// HeaderClientCertSubject carries the verified client certificate subject to
+// the handler, so topic access can be granted by certificate identity. The
+// server strips any client-supplied value before setting it.
+const HeaderClientCertSubject = "X-Client-Cert-Subject"
+
+const (
+	clientAuthRequired = "required"
+	clientAuthOptional = "optional"
+)
+
+// clientAuthType maps the configured mutual TLS mode to the crypto/tls
+// policy; the empty mode defaults to required since configuring a client CA
+// without enforcement is almost always a mistake
+func clientAuthType(mode string) (tls.ClientAuthType, error) {
+	switch mode {
+	case "", clientAuthRequired:
+		return tls.RequireAndVerifyClientCert, nil
+	case clientAuthOptional:
+		return tls.VerifyClientCertIfGiven, nil
+	}
+	return tls.NoClientCert, fmt.Errorf("%sunknown client auth mode: %s", logPrefix, mode)
+}
+
+// loadClientCAs reads the PEM bundle used to verify client certificates
+func loadClientCAs(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("%sfailed to read client CA bundle: %w", logPrefix, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%sno certificates found in client CA bundle: %s", logPrefix, file)
+	}
+	return pool, nil
+}
+
+// withClientCertSubject exposes the verified peer certificate subject to the
+// handler as a trusted request header
+func withClientCertSubject(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		ctx.Request.Header.Del(HeaderClientCertSubject)
+		if state := ctx.TLSConnectionState(); state != nil && len(state.PeerCertificates) > 0 {
+			ctx.Request.Header.Set(HeaderClientCertSubject, state.PeerCertificates[0].Subject.String())
+		}
+		h(ctx)
+	}
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"io"
+	"net"
+	"net/http"
+
+	zlog "github.com/rs/zerolog/log"
+	"github.com/valyala/fasthttp"
+)
+
+// newNetHTTPHandler adapts the fasthttp request handler to net/http so the
+// server can run in nethttp mode, where the standard library negotiates
+// HTTP/2 over TLS via ALPN. Browsers multiplex SSE streams over a single
+// HTTP/2 connection, lifting the 6-connections-per-host limit.
+func newNetHTTPHandler(h fasthttp.RequestHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req fasthttp.Request
+		req.Header.SetMethod(r.Method)
+		req.SetRequestURI(r.RequestURI)
+		req.Header.SetHost(r.Host)
+		for k, vv := range r.Header {
+			for _, v := range vv {
+				req.Header.Add(k, v)
+			}
+		}
+		req.Header.Del(HeaderClientCertSubject)
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			req.Header.Set(HeaderClientCertSubject, r.TLS.PeerCertificates[0].Subject.String())
+		}
+		if r.Body != nil {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			req.SetBody(body)
+		}
+
+		remoteAddr, _ := net.ResolveTCPAddr("tcp", r.RemoteAddr)
+
+		var ctx fasthttp.RequestCtx
+		ctx.Init(&req, remoteAddr, nil)
+
+		h(&ctx)
+
+		header := w.Header()
+		ctx.Response.Header.VisitAll(func(k, v []byte) {
+			header.Add(string(k), string(v))
+		})
+		// connection-level headers are managed by net/http and are illegal in
+		// HTTP/2 responses
+		header.Del("Connection")
+		header.Del("Transfer-Encoding")
+		w.WriteHeader(ctx.Response.StatusCode())
+
+		if ctx.IsBodyStream() {
+			copyBodyStream(w, r, &ctx)
+			return
+		}
+		_, _ = w.Write(ctx.Response.Body())
+	})
+}
+
+// copyBodyStream pipes a streaming response (SSE, NDJSON) to the client,
+// flushing after every read and shutting the stream down when the client
+// disconnects so the handler's writes start failing and it unsubscribes
+func copyBodyStream(w http.ResponseWriter, r *http.Request, ctx *fasthttp.RequestCtx) {
+	flusher, _ := w.(http.Flusher)
+	reader := ctx.Response.BodyStream()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+		if err := ctx.Response.CloseBodyStream(); err != nil {
+			zlog.Debug().Err(err).Msg(logPrefix + "failed to close response body stream")
+		}
+	}()
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+)
+
+// certReloader serves a cert/key pair loaded from disk and transparently
+// picks up re-issued files, so internally issued certificates can rotate
+// without a restart
+type certReloader struct {
+	certFile string
+	keyFile  string
+	mutex    sync.RWMutex
+	cert     *tls.Certificate
+	modTime  time.Time
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// getCertificate implements tls.Config.GetCertificate
+func (r *certReloader) getCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.cert, nil
+}
+
+// reload parses the keypair from disk and records the cert file's mtime
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	var modTime time.Time
+	if info, err := os.Stat(r.certFile); err == nil {
+		modTime = info.ModTime()
+	}
+
+	r.mutex.Lock()
+	r.cert = &cert
+	r.modTime = modTime
+	r.mutex.Unlock()
+	return nil
+}
+
+// maybeReload reloads the keypair when the cert file changed on disk; a
+// broken replacement keeps the previously loaded pair
+func (r *certReloader) maybeReload() {
+	info, err := os.Stat(r.certFile)
+	if err != nil {
+		zlog.Warn().Err(err).Str("certFile", r.certFile).Msg(logPrefix + "failed to stat certificate file")
+		return
+	}
+
+	r.mutex.RLock()
+	changed := info.ModTime().After(r.modTime)
+	r.mutex.RUnlock()
+	if !changed {
+		return
+	}
+
+	if err := r.reload(); err != nil {
+		zlog.Error().Err(err).Str("certFile", r.certFile).Msg(logPrefix + "failed to reload certificate; keeping the previous one")
+		return
+	}
+	zlog.Info().Str("certFile", r.certFile).Msg(logPrefix + "reloaded TLS certificate")
+}
+
+// watch polls the certificate file for changes at the given frequency
+func (r *certReloader) watch(freq time.Duration) {
+	ticker := time.NewTicker(freq)
+	defer ticker.Stop()
+	for range ticker.C {
+		r.maybeReload()
+	}
+}
@@ -0,0 +1,118 @@
+// Package validator checks well-formed request payloads against field-level
+// rules and reports every violation in a structured 422 error, so clients
+// learn which field failed instead of a generic bad-request response.
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+)
+
+// Violation names one field that failed validation and why.
+type Violation struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// maxEventIDLength bounds SSE event ids; anything longer bloats every frame
+// and the Last-Event-ID header on reconnects.
+const maxEventIDLength = 256
+
+// PublishRequest validates a mapped publish request.
+func PublishRequest(req entity.PublishRequest) error {
+	var violations []Violation
+
+	if len(req.Message) == 0 {
+		violations = append(violations, Violation{Field: "event.message", Reason: "is required"})
+	} else if req.Encoding != entity.EncodingBase64 && !utf8.Valid(req.Message) {
+		violations = append(violations, Violation{Field: "event.message", Reason: "must be valid UTF-8; publish binary data as application/octet-stream"})
+	}
+
+	violations = append(violations, eventIDViolations("event.id", req.EventID)...)
+	violations = append(violations, eventTypeViolations("event.type", req.EventType)...)
+
+	return toErr(violations)
+}
+
+// UpdatePubSubRequest validates a mapped topic settings update.
+func UpdatePubSubRequest(req entity.UpdatePubSubRequest) error {
+	var violations []Violation
+
+	if req.RetentionBufferSize != nil && *req.RetentionBufferSize < 0 {
+		violations = append(violations, Violation{Field: "pubsub.retention_buffer_size", Reason: "must be zero or a positive integer"})
+	}
+	if req.MaxSubscribers != nil && *req.MaxSubscribers < 0 {
+		violations = append(violations, Violation{Field: "pubsub.max_subscribers", Reason: "must be zero or a positive integer"})
+	}
+
+	return toErr(violations)
+}
+
+// UpsertStaticPubSubRequest validates a mapped static pubsub upsert.
+func UpsertStaticPubSubRequest(req entity.UpsertStaticPubSubRequest) error {
+	var violations []Violation
+
+	if req.ID <= 0 {
+		violations = append(violations, Violation{Field: "pubsub.id", Reason: "must be a positive integer"})
+	}
+	if req.Token == "" {
+		violations = append(violations, Violation{Field: "pubsub.token", Reason: "is required"})
+	}
+	if !utf8.ValidString(req.Name) {
+		violations = append(violations, Violation{Field: "pubsub.name", Reason: "must be valid UTF-8"})
+	}
+
+	return toErr(violations)
+}
+
+// eventIDViolations enforces the SSE id constraints: single line, sane length.
+func eventIDViolations(field, id string) []Violation {
+	if id == "" {
+		return nil
+	}
+	var violations []Violation
+	if strings.ContainsAny(id, "\r\n") {
+		violations = append(violations, Violation{Field: field, Reason: "must not contain line breaks"})
+	}
+	if len(id) > maxEventIDLength {
+		violations = append(violations, Violation{Field: field, Reason: fmt.Sprintf("must be at most %d bytes", maxEventIDLength)})
+	}
+	if !utf8.ValidString(id) {
+		violations = append(violations, Violation{Field: field, Reason: "must be valid UTF-8"})
+	}
+	return violations
+}
+
+// eventTypeViolations enforces the SSE event name constraints.
+func eventTypeViolations(field, eventType string) []Violation {
+	if eventType == "" {
+		return nil
+	}
+	var violations []Violation
+	if strings.ContainsAny(eventType, "\r\n") {
+		violations = append(violations, Violation{Field: field, Reason: "must not contain line breaks"})
+	}
+	if !utf8.ValidString(eventType) {
+		violations = append(violations, Violation{Field: field, Reason: "must be valid UTF-8"})
+	}
+	return violations
+}
+
+// toErr folds the collected violations into a structured 422; a clean run
+// returns nil.
+func toErr(violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return entity.Err{
+		Code:    entity.ErrorCodeUnprocessableEntity,
+		Kind:    entity.ErrorKindValidationFailed,
+		Message: "request validation failed",
+		Details: map[string]any{
+			"fields": violations,
+		},
+	}
+}
@@ -0,0 +1,148 @@
+package ssergo
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrPublisherClosed is returned by enqueue calls after Close.
+var ErrPublisherClosed = errors.New("async publisher is closed")
+
+// PublishJob is one queued publish. EventID and EventType are optional, as
+// with PublishEvent.
+type PublishJob struct {
+	PubSubID  string
+	Message   string
+	EventID   string
+	EventType string
+}
+
+// AsyncPublisherParams configures NewAsyncPublisher. Zero values pick
+// defaults.
+type AsyncPublisherParams struct {
+	// QueueSize is the enqueue buffer; Publish blocks (and TryPublish
+	// fails) when it is full. 0 picks 1024.
+	QueueSize int
+	// Workers is how many publishes run concurrently. 0 picks 4.
+	Workers int
+	// OnError receives jobs that failed after the client's own retries;
+	// when nil, failures are dropped silently.
+	OnError func(job PublishJob, err error)
+}
+
+// AsyncPublisher decouples producers from the publish round-trip: jobs are
+// queued in memory and flushed by a pool of workers, so high-frequency
+// producers don't serialize on a single blocking HTTP call. Combine it with
+// NewHTTPClient so the workers reuse warm connections.
+type AsyncPublisher struct {
+	cli     SSERClient
+	jobs    chan PublishJob
+	onError func(job PublishJob, err error)
+
+	inflight  sync.WaitGroup
+	workers   sync.WaitGroup
+	closeOnce sync.Once
+
+	mutex  sync.RWMutex
+	closed bool
+}
+
+// NewAsyncPublisher starts the worker pool; callers must Close it to drain
+// the queue and release the workers.
+func NewAsyncPublisher(cli SSERClient, p AsyncPublisherParams) *AsyncPublisher {
+	if p.QueueSize <= 0 {
+		p.QueueSize = 1024
+	}
+	if p.Workers <= 0 {
+		p.Workers = 4
+	}
+
+	a := &AsyncPublisher{
+		cli:     cli,
+		jobs:    make(chan PublishJob, p.QueueSize),
+		onError: p.OnError,
+	}
+	a.workers.Add(p.Workers)
+	for i := 0; i < p.Workers; i++ {
+		go a.run()
+	}
+	return a
+}
+
+func (a *AsyncPublisher) run() {
+	defer a.workers.Done()
+	for job := range a.jobs {
+		_, err := a.cli.PublishEvent(job.PubSubID, job.Message, job.EventID, job.EventType)
+		if err != nil && a.onError != nil {
+			a.onError(job, err)
+		}
+		a.inflight.Done()
+	}
+}
+
+// Publish enqueues a job, blocking while the queue is full until ctx is
+// done.
+func (a *AsyncPublisher) Publish(ctx context.Context, job PublishJob) error {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	if a.closed {
+		return ErrPublisherClosed
+	}
+	// count the job before handing it over so a fast worker can't Done()
+	// ahead of the Add
+	a.inflight.Add(1)
+	select {
+	case a.jobs <- job:
+		return nil
+	case <-ctx.Done():
+		a.inflight.Done()
+		return ctx.Err()
+	}
+}
+
+// TryPublish enqueues a job without blocking; it reports false when the
+// queue is full or the publisher is closed.
+func (a *AsyncPublisher) TryPublish(job PublishJob) bool {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	if a.closed {
+		return false
+	}
+	a.inflight.Add(1)
+	select {
+	case a.jobs <- job:
+		return true
+	default:
+		a.inflight.Done()
+		return false
+	}
+}
+
+// Flush blocks until every job enqueued so far has been attempted, or ctx
+// is done.
+func (a *AsyncPublisher) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		a.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting jobs, drains the queue and waits for the workers to
+// finish. It is safe to call more than once.
+func (a *AsyncPublisher) Close() {
+	a.closeOnce.Do(func() {
+		a.mutex.Lock()
+		a.closed = true
+		a.mutex.Unlock()
+		close(a.jobs)
+		a.workers.Wait()
+	})
+}
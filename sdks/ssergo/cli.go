@@ -1,8 +1,8 @@
 package ssergo
 
 import (
-	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,36 +10,100 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 )
 
-// EventCallback defines the function signature for processing a single event line from the SSE stream.
-type EventCallback func(line string)
+// EventCallback processes a single assembled SSE event. Multi-line data is
+// joined before dispatch; the event name, id, and retry hint are available
+// as typed fields on Event.
+type EventCallback = EventHandler
 
 // SSERClient defines the interface for interacting with the PubSub API.
+// Every method has a Ctx variant honoring cancellation and per-call
+// deadlines; the plain variants use context.Background().
 type SSERClient interface {
-	// Updated method signature to accept optional eventID and eventType.
-	CreatePubSub(opts ...CreateOption) error
+	// CreatePubSub returns the created topic's ID and access token so callers
+	// can programmatically hand out subscription credentials.
+	CreatePubSub(opts ...CreateOption) (*PubSub, error)
+	CreatePubSubCtx(ctx context.Context, opts ...CreateOption) (*PubSub, error)
 	DeletePubSub(id string) error
-	// PublishEvent now accepts optional eventID and eventType strings.
-	PublishEvent(id string, message string, eventID string, eventType string) error
-	SubscribeToTopic(id string, topicAccessToken string, callback EventCallback) error
+	DeletePubSubCtx(ctx context.Context, id string) error
+	// PublishEvent returns the server-assigned event ID.
+	PublishEvent(id string, message string, eventID string, eventType string) (*PublishResult, error)
+	PublishEventCtx(ctx context.Context, id string, message string, eventID string, eventType string) (*PublishResult, error)
+	// PublishEncryptedEvent encrypts the message with the cipher before it
+	// leaves the process; the broker only ever sees ciphertext plus the
+	// cipher's key id.
+	PublishEncryptedEvent(id string, message string, eventID string, eventType string, cipher *PayloadCipher) (*PublishResult, error)
+	PublishEncryptedEventCtx(ctx context.Context, id string, message string, eventID string, eventType string, cipher *PayloadCipher) (*PublishResult, error)
+	// SubscribeToTopic returns the close reason announced by the server via a
+	// terminal `event: close` frame, or CloseReasonUnknown when the stream
+	// ended without one. Cancelling the context of the Ctx variant tears the
+	// stream down and returns the context's error.
+	SubscribeToTopic(id string, topicAccessToken string, callback EventCallback) (CloseReason, error)
+	SubscribeToTopicCtx(ctx context.Context, id string, topicAccessToken string, callback EventCallback) (CloseReason, error)
+	// Subscribe is the channel-based alternative to the callback API; the
+	// returned channel is closed when the stream ends or the context is
+	// cancelled.
+	Subscribe(ctx context.Context, id string, topicAccessToken string) (<-chan Event, error)
+	// Stream is the pull-based alternative: it returns an iterator whose
+	// Next blocks for the next event, which fits for-loop and errgroup
+	// patterns better than callbacks. WithTransport selects the wire
+	// protocol; the default is SSE.
+	Stream(ctx context.Context, id string, topicAccessToken string, opts ...StreamOption) (EventIterator, error)
+	// GetMetrics fetches the server's counters; it requires a
+	// MetricsAccessToken on the client.
+	GetMetrics(ctx context.Context) (*MetricsReport, error)
+	// SubscribeToMetrics streams typed metric snapshots from the reserved
+	// metrics topic; it requires a MetricsAccessToken on the client.
+	SubscribeToMetrics(ctx context.Context, topicID string) (<-chan MetricEvent, error)
+	// SubscribeWithReconnect keeps the subscription alive across connection
+	// drops, resuming from the last received event ID.
+	SubscribeWithReconnect(id string, topicAccessToken string, callback EventCallback) (CloseReason, error)
+	SubscribeWithReconnectCtx(ctx context.Context, id string, topicAccessToken string, callback EventCallback) (CloseReason, error)
 }
 
+// CloseReason mirrors the server's vocabulary of terminal SSE close reasons.
+type CloseReason string
+
+// Close reasons the server may announce before terminating a stream.
+const (
+	CloseReasonUnknown        CloseReason = ""
+	CloseReasonDeleted        CloseReason = "deleted"
+	CloseReasonExpired        CloseReason = "expired"
+	CloseReasonRevoked        CloseReason = "revoked"
+	CloseReasonServerShutdown CloseReason = "server-shutdown"
+	CloseReasonIdleTimeout    CloseReason = "idle-timeout"
+	CloseReasonSuperseded     CloseReason = "superseded"
+)
+
 // Params holds configuration parameters for the New constructor.
 type Params struct {
 	BaseURL        string
 	APIAccessToken string
-	Logger         *log.Logger
-	HTTPClient     *http.Client
+	// MetricsAccessToken authenticates GetMetrics and the reserved metrics
+	// topic; it is optional and only required for those calls.
+	MetricsAccessToken string
+	Logger             *log.Logger
+	HTTPClient         *http.Client
+	// PublishMaxRetries caps the retries after a failed publish attempt; 0
+	// picks the default, a negative value disables retries entirely.
+	PublishMaxRetries int
+	// PublishRetryBackoff is the initial wait before the first retry; it
+	// doubles on each subsequent attempt. 0 picks the default.
+	PublishRetryBackoff time.Duration
 }
 
 // sserClient holds the base configuration for API interaction.
 type sserClient struct {
-	baseURL    string
-	apiToken   string
-	logger     *log.Logger
-	httpClient *http.Client
+	baseURL        string
+	apiToken       string
+	metricsToken   string
+	logger         *log.Logger
+	httpClient     *http.Client
+	publishRetries int
+	publishBackoff time.Duration
 }
 
 // New creates a new instance of SSERClient and returns it as the interface.
@@ -59,11 +123,26 @@ func New(p Params) (SSERClient, error) {
 		p.Logger = log.New(os.Stdout, "PUBSUB_SDK: ", log.LstdFlags)
 	}
 
+	retries := p.PublishMaxRetries
+	switch {
+	case retries == 0:
+		retries = defaultPublishMaxRetries
+	case retries < 0:
+		retries = 0
+	}
+	backoff := p.PublishRetryBackoff
+	if backoff <= 0 {
+		backoff = defaultPublishRetryBackoff
+	}
+
 	return &sserClient{
-		baseURL:    p.BaseURL,
-		apiToken:   p.APIAccessToken,
-		logger:     p.Logger,
-		httpClient: p.HTTPClient,
+		baseURL:        p.BaseURL,
+		apiToken:       p.APIAccessToken,
+		metricsToken:   p.MetricsAccessToken,
+		logger:         p.Logger,
+		httpClient:     p.HTTPClient,
+		publishRetries: retries,
+		publishBackoff: backoff,
 	}, nil
 }
 
@@ -71,6 +150,60 @@ func New(p Params) (SSERClient, error) {
 // STRUCTS FOR JSON PAYLOADS AND RESPONSES
 // =============================================================================
 
+// PubSub is the typed result of CreatePubSub.
+type PubSub struct {
+	ID             string `json:"id"`
+	Token          string `json:"token"`
+	TokenExpiresAt string `json:"token_expires_at,omitempty"`
+}
+
+// PublishResult is the typed result of PublishEvent. Subscribers, Enqueued
+// and Dropped are the server's enqueue-time delivery counts: how many
+// subscribers were connected, how many deliveries were queued to them and
+// how many subscribers were skipped by their filters. Servers predating
+// these fields leave them zero.
+type PublishResult struct {
+	ID          string `json:"id"`
+	Subscribers int    `json:"subscribers"`
+	Enqueued    int    `json:"enqueued"`
+	Dropped     int    `json:"dropped"`
+}
+
+// APIError is returned when the server answers with a non-2xx status; it
+// carries the HTTP status along with the error payload's code and message.
+type APIError struct {
+	StatusCode int
+	Code       int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("sser: server returned %d (code: %d): %s", e.StatusCode, e.Code, e.Message)
+}
+
+// decodeAPIError builds an APIError from an error response body of the form
+// {"error": {"code": ..., "message": ...}}; unparsable bodies fall back to
+// the raw text.
+func decodeAPIError(statusCode int, body []byte) *APIError {
+	var payload struct {
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Error.Message == "" {
+		return &APIError{
+			StatusCode: statusCode,
+			Message:    strings.TrimSpace(string(body)),
+		}
+	}
+	return &APIError{
+		StatusCode: statusCode,
+		Code:       payload.Error.Code,
+		Message:    payload.Error.Message,
+	}
+}
+
 // PublishPayload matches the expected body for the publish endpoint.
 type PublishPayload struct {
 	Event EventPayload `json:"event"`
@@ -91,12 +224,20 @@ type CreatePubSubPayload struct {
 
 // PubSubSettings holds the optional settings for a new topic.
 type PubSubSettings struct {
-	Persist bool `json:"persist"`
+	Persist   bool   `json:"persist"`
+	Name      string `json:"name,omitempty"`
+	TTL       string `json:"ttl,omitempty"`
+	Retention int    `json:"retention_buffer_size,omitempty"`
+	Token     string `json:"token,omitempty"`
 }
 
 // createConfig holds the configuration state for a CreatePubSub request.
 type createConfig struct {
-	Persist bool
+	Persist   bool
+	Name      string
+	TTL       time.Duration
+	Retention int
+	Token     string
 }
 
 // =============================================================================
@@ -115,6 +256,48 @@ func WithPersist(persist bool) CreateOption {
 	}
 }
 
+// WithName gives the new topic a human-readable name, making it addressable
+// by name in addition to its generated id.
+func WithName(name string) CreateOption {
+	return func(cfg *createConfig) error {
+		cfg.Name = name
+		return nil
+	}
+}
+
+// WithTTL makes the new topic expire after the given lifetime.
+func WithTTL(ttl time.Duration) CreateOption {
+	return func(cfg *createConfig) error {
+		if ttl < 0 {
+			return fmt.Errorf("ttl must not be negative, got %s", ttl)
+		}
+		cfg.TTL = ttl
+		return nil
+	}
+}
+
+// WithRetention overrides the server's default replay buffer size for the
+// new topic, controlling how many events reconnecting subscribers can
+// catch up on.
+func WithRetention(size int) CreateOption {
+	return func(cfg *createConfig) error {
+		if size < 0 {
+			return fmt.Errorf("retention size must not be negative, got %d", size)
+		}
+		cfg.Retention = size
+		return nil
+	}
+}
+
+// WithStaticToken pins the topic access token instead of letting the server
+// generate a random one, so it can be shared with subscribers out of band.
+func WithStaticToken(token string) CreateOption {
+	return func(cfg *createConfig) error {
+		cfg.Token = token
+		return nil
+	}
+}
+
 // =============================================================================
 // CORE API METHODS
 // =============================================================================
@@ -122,9 +305,14 @@ func WithPersist(persist bool) CreateOption {
 // CreatePubSub sends a POST request to create a new PubSub topic, configurable via options.
 //
 // Example usage:
-// client.CreatePubSub() // Default topic
-// client.CreatePubSub(WithPersist(true)) // Persistent topic
-func (c *sserClient) CreatePubSub(opts ...CreateOption) error {
+// topic, err := client.CreatePubSub() // Default topic
+// topic, err := client.CreatePubSub(WithPersist(true)) // Persistent topic
+func (c *sserClient) CreatePubSub(opts ...CreateOption) (*PubSub, error) {
+	return c.CreatePubSubCtx(context.Background(), opts...)
+}
+
+// CreatePubSubCtx is CreatePubSub with cancellation and deadline support.
+func (c *sserClient) CreatePubSubCtx(ctx context.Context, opts ...CreateOption) (*PubSub, error) {
 	// Initialize default configuration
 	cfg := &createConfig{
 		Persist: false,
@@ -133,35 +321,40 @@ func (c *sserClient) CreatePubSub(opts ...CreateOption) error {
 	// Apply options to the configuration
 	for _, opt := range opts {
 		if err := opt(cfg); err != nil {
-			return fmt.Errorf("failed to apply create option: %w", err)
+			return nil, fmt.Errorf("failed to apply create option: %w", err)
 		}
 	}
 
 	url := fmt.Sprintf("%s/api/v1/pubsubs", c.baseURL)
 	c.logger.Printf("Attempting to create a new PubSub topic (Persist: %t)...", cfg.Persist)
 
+	settings := PubSubSettings{
+		Persist:   cfg.Persist,
+		Name:      cfg.Name,
+		Retention: cfg.Retention,
+		Token:     cfg.Token,
+	}
+	if cfg.TTL > 0 {
+		settings.TTL = cfg.TTL.String()
+	}
+
 	var body []byte
 	var err error
 
-	if cfg.Persist {
-		// Construct the persistence payload: {"pubsub": {"persist": true}}
-		payload := CreatePubSubPayload{
-			PubSub: PubSubSettings{Persist: true},
-		}
-		body, err = json.Marshal(payload)
-		if err != nil {
-			return fmt.Errorf("failed to marshal persistence payload: %w", err)
-		}
-	} else {
+	if settings == (PubSubSettings{}) {
 		// Use empty JSON object for default creation: {}
 		body = []byte("{}")
+	} else {
+		// Construct the settings payload: {"pubsub": {"persist": true, ...}}
+		body, err = json.Marshal(CreatePubSubPayload{PubSub: settings})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal settings payload: %w", err)
+		}
 	}
 
-	c.logger.Printf("Creation payload: %s\n", string(body))
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.apiToken)
@@ -169,23 +362,36 @@ func (c *sserClient) CreatePubSub(opts ...CreateOption) error {
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
+		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	c.logger.Printf("HTTP Status: %s\n", resp.Status)
 	responseBody, _ := io.ReadAll(resp.Body)
-	c.logger.Printf("Response Body: %s\n", string(responseBody))
-	c.logger.Println("\nCreation command finished. Check the response above for the new PubSub ID.")
-	return nil
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, decodeAPIError(resp.StatusCode, responseBody)
+	}
+
+	var result struct {
+		PubSub PubSub `json:"pubsub"`
+	}
+	if err := json.Unmarshal(responseBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse create response: %w", err)
+	}
+
+	c.logger.Printf("Created PubSub topic %s\n", result.PubSub.ID)
+	return &result.PubSub, nil
 }
 
 // DeletePubSub sends a DELETE request to remove a PubSub topic by ID.
 func (c *sserClient) DeletePubSub(id string) error {
+	return c.DeletePubSubCtx(context.Background(), id)
+}
+
+// DeletePubSubCtx is DeletePubSub with cancellation and deadline support.
+func (c *sserClient) DeletePubSubCtx(ctx context.Context, id string) error {
 	url := fmt.Sprintf("%s/api/v1/pubsubs/%s", c.baseURL, id)
-	c.logger.Printf("Attempting to delete PubSub topic ID: %s\n", id)
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -198,19 +404,48 @@ func (c *sserClient) DeletePubSub(id string) error {
 	}
 	defer resp.Body.Close()
 
-	c.logger.Printf("HTTP Status: %s\n", resp.Status)
-	// Log the response body
 	responseBody, _ := io.ReadAll(resp.Body)
-	c.logger.Printf("Response Body: %s\n", string(responseBody))
-	c.logger.Println("\nDeletion command finished.")
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return decodeAPIError(resp.StatusCode, responseBody)
+	}
+
+	c.logger.Printf("Deleted PubSub topic %s\n", id)
 	return nil
 }
 
 // PublishEvent sends a POST request to publish a message to a topic, including optional event ID and type.
-func (c *sserClient) PublishEvent(id string, message string, eventID string, eventType string) error {
-	url := fmt.Sprintf("%s/api/v1/pubsubs/%s/events", c.baseURL, id)
-	c.logger.Printf("Attempting to publish message to ID: %s\n", id)
+func (c *sserClient) PublishEvent(id string, message string, eventID string, eventType string) (*PublishResult, error) {
+	return c.PublishEventCtx(context.Background(), id, message, eventID, eventType)
+}
+
+// PublishEventCtx is PublishEvent with cancellation and deadline support.
+// Transient failures (network errors, 5xx responses) are retried with
+// exponential backoff; every attempt carries the same Idempotency-Key so the
+// server deduplicates the message if a retry races a delivered publish.
+func (c *sserClient) PublishEventCtx(ctx context.Context, id string, message string, eventID string, eventType string) (*PublishResult, error) {
+	return c.publishWithRetry(ctx, id, message, eventID, eventType, "")
+}
+
+// PublishEncryptedEvent is PublishEvent for end-to-end encrypted payloads.
+func (c *sserClient) PublishEncryptedEvent(id string, message string, eventID string, eventType string, cipher *PayloadCipher) (*PublishResult, error) {
+	return c.PublishEncryptedEventCtx(context.Background(), id, message, eventID, eventType, cipher)
+}
+
+// PublishEncryptedEventCtx encrypts the message with the cipher and publishes
+// the resulting ciphertext; the cipher's key id travels in the
+// X-Encryption-Key-Id header and surfaces as `key_id` in the event envelope.
+func (c *sserClient) PublishEncryptedEventCtx(ctx context.Context, id string, message string, eventID string, eventType string, cipher *PayloadCipher) (*PublishResult, error) {
+	if cipher == nil {
+		return nil, fmt.Errorf("payload cipher is required for encrypted publish")
+	}
+	encrypted, err := cipher.Encrypt([]byte(message))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt payload: %w", err)
+	}
+	return c.publishWithRetry(ctx, id, encrypted, eventID, eventType, cipher.KeyID)
+}
 
+func (c *sserClient) publishWithRetry(ctx context.Context, id string, message string, eventID string, eventType string, keyID string) (*PublishResult, error) {
 	payload := PublishPayload{
 		Event: EventPayload{
 			ID:      eventID,
@@ -220,69 +455,176 @@ func (c *sserClient) PublishEvent(id string, message string, eventID string, eve
 	}
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
 	}
-	c.logger.Printf("Payload: %s\n", string(body))
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	idemKey, err := newIdempotencyKey()
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to generate idempotency key: %w", err)
+	}
+
+	backoff := c.publishBackoff
+	var lastErr error
+	for attempt := 0; attempt <= c.publishRetries; attempt++ {
+		if attempt > 0 {
+			c.logger.Printf("Retrying publish to PubSub topic %s (attempt %d/%d)...\n", id, attempt, c.publishRetries)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		result, err := c.doPublish(ctx, id, body, idemKey, keyID)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+		if !isRetryablePublishError(err) || ctx.Err() != nil {
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+// doPublish performs a single publish attempt.
+func (c *sserClient) doPublish(ctx context.Context, id string, body []byte, idemKey string, keyID string) (*PublishResult, error) {
+	url := fmt.Sprintf("%s/api/v1/pubsubs/%s/events", c.baseURL, id)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.apiToken)
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", idemKey)
+	if keyID != "" {
+		req.Header.Set("X-Encryption-Key-Id", keyID)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
+		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	c.logger.Printf("HTTP Status: %s\n", resp.Status)
 	responseBody, _ := io.ReadAll(resp.Body)
-	c.logger.Printf("Response Body: %s\n", string(responseBody))
-	c.logger.Println("\nPublish command finished.")
-	return nil
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, decodeAPIError(resp.StatusCode, responseBody)
+	}
+
+	var result struct {
+		Event PublishResult `json:"event"`
+	}
+	if err := json.Unmarshal(responseBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse publish response: %w", err)
+	}
+
+	c.logger.Printf("Published event %s to PubSub topic %s\n", result.Event.ID, id)
+	return &result.Event, nil
 }
 
 // SubscribeToTopic establishes an SSE connection and streams events, calling the provided callback function for each line received.
-func (c *sserClient) SubscribeToTopic(id string, topicAccessToken string, callback EventCallback) error {
+// When the server announces a terminal `event: close` frame, its reason is returned alongside a nil error.
+func (c *sserClient) SubscribeToTopic(id string, topicAccessToken string, callback EventCallback) (CloseReason, error) {
+	return c.SubscribeToTopicCtx(context.Background(), id, topicAccessToken, callback)
+}
+
+// SubscribeToTopicCtx is SubscribeToTopic with cancellation support;
+// cancelling the context tears the stream down and returns the context's
+// error.
+func (c *sserClient) SubscribeToTopicCtx(ctx context.Context, id string, topicAccessToken string, callback EventCallback) (CloseReason, error) {
+	resp, err := c.openStream(ctx, id, topicAccessToken)
+	if err != nil {
+		return CloseReasonUnknown, err
+	}
+	defer resp.Body.Close()
+
+	closeReason, _, err := consumeSSE(resp.Body, callback)
+	if err != nil {
+		// a cancelled context surfaces as a read error; report it as such
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return closeReason, ctxErr
+		}
+		return closeReason, fmt.Errorf("error reading stream: %w", err)
+	}
+
+	c.logger.Printf("Subscription closed by server (reason: %q).\n", closeReason)
+	return closeReason, nil
+}
+
+// Subscribe opens the stream and delivers assembled events over a channel;
+// the channel is closed when the server ends the stream or the context is
+// cancelled.
+func (c *sserClient) Subscribe(ctx context.Context, id string, topicAccessToken string) (<-chan Event, error) {
+	resp, err := c.openStream(ctx, id, topicAccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan Event)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+		_, _, err := consumeSSE(resp.Body, func(ev Event) {
+			select {
+			case ch <- ev:
+			case <-ctx.Done():
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			c.logger.Printf("Error reading stream: %v\n", err)
+		}
+	}()
+	return ch, nil
+}
+
+// openStream performs the SSE subscription request and verifies the response
+// is a live stream.
+func (c *sserClient) openStream(ctx context.Context, id string, topicAccessToken string) (*http.Response, error) {
+	return c.openStreamResume(ctx, id, topicAccessToken, "")
+}
+
+// openStreamResume is openStream with a Last-Event-ID header so the server
+// can replay the events missed since the previous connection.
+func (c *sserClient) openStreamResume(ctx context.Context, id string, topicAccessToken string, lastEventID string) (*http.Response, error) {
 	url := fmt.Sprintf("%s/api/v1/pubsubs/%s/events", c.baseURL, id)
-	c.logger.Println("--------------------------------------------------------")
-	c.logger.Printf("Subscribing to %s. Listening for Server-Sent Events (SSE). Press Ctrl+C to stop.\n", id)
-	c.logger.Println("--------------------------------------------------------")
 
 	streamingClient := *c.httpClient
 	streamingClient.Timeout = 0
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+topicAccessToken)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
 
 	resp, err := streamingClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
+		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		c.logger.Printf("Subscription failed. HTTP Status: %s\n", resp.Status)
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("server returned error: %s", string(body))
+		resp.Body.Close()
+		return nil, decodeAPIError(resp.StatusCode, body)
 	}
+	return resp, nil
+}
 
-	scanner := bufio.NewScanner(resp.Body)
-	for scanner.Scan() {
-		callback(scanner.Text())
+// parseCloseReason extracts the reason field from a close event's data payload.
+func parseCloseReason(data string) CloseReason {
+	var payload struct {
+		Reason string `json:"reason"`
 	}
-
-	if err := scanner.Err(); err != nil && err != io.EOF {
-		return fmt.Errorf("error reading stream: %w", err)
+	if err := json.Unmarshal([]byte(data), &payload); err != nil {
+		return CloseReasonUnknown
 	}
-
-	c.logger.Println("\nSubscription closed by server.")
-	return nil
+	return CloseReason(payload.Reason)
 }
@@ -0,0 +1,65 @@
+package ssergo
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// PayloadCipher seals event payloads with a shared AES-GCM key before they
+// leave the publisher, so sensitive events never exist in plaintext on the
+// broker. Subscribers construct a cipher with the same key id and secret and
+// call Decrypt on events whose envelope carries a matching `key_id`.
+type PayloadCipher struct {
+	// KeyID identifies the key on the wire so consumers can rotate keys
+	// without guessing which one an event was sealed with.
+	KeyID string
+	aead  cipher.AEAD
+}
+
+// NewPayloadCipher builds a cipher from a 16, 24 or 32 byte AES key.
+func NewPayloadCipher(keyID string, key []byte) (*PayloadCipher, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("key id is required")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init cipher: %w", err)
+	}
+	return &PayloadCipher{KeyID: keyID, aead: aead}, nil
+}
+
+// Encrypt seals the plaintext and returns base64(nonce || ciphertext), ready
+// to be used as an event message.
+func (p *PayloadCipher) Encrypt(plaintext []byte) (string, error) {
+	nonce := make([]byte, p.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := p.aead.Seal(nonce, nonce, plaintext, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt; it accepts the base64 payload as delivered in the
+// event envelope's data field.
+func (p *PayloadCipher) Decrypt(encoded string) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("payload is not valid base64: %w", err)
+	}
+	if len(sealed) < p.aead.NonceSize() {
+		return nil, fmt.Errorf("payload is too short to contain a nonce")
+	}
+	nonce := sealed[:p.aead.NonceSize()]
+	plaintext, err := p.aead.Open(nil, nonce, sealed[p.aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt payload: %w", err)
+	}
+	return plaintext, nil
+}
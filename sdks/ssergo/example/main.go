@@ -51,10 +51,15 @@ func main() {
 			fmt.Println("Creating non-persistent PubSub topic...")
 		}
 
-		if err := client.CreatePubSub(opts...); err != nil {
+		topic, err := client.CreatePubSub(opts...)
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error creating pubsub: %v\n", err)
 			os.Exit(1)
 		}
+		fmt.Printf("Created topic %s\nAccess token: %s\n", topic.ID, topic.Token)
+		if topic.TokenExpiresAt != "" {
+			fmt.Printf("Token expires at: %s\n", topic.TokenExpiresAt)
+		}
 
 	case "delete":
 		if len(args) < 1 {
@@ -76,10 +81,12 @@ func main() {
 		}
 		id := args[0]
 		message := args[1]
-		if err := client.PublishEvent(id, message, "", ""); err != nil {
+		result, err := client.PublishEvent(id, message, "", "")
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error publishing event: %v\n", err)
 			os.Exit(1)
 		}
+		fmt.Printf("Published event %s\n", result.ID)
 
 	case "subscribe":
 		if len(args) < 1 {
@@ -96,15 +103,25 @@ func main() {
 			os.Exit(1)
 		}
 
-		// Define the callback function to handle each received line (keeping the original CLI behavior)
-		printEventLine := func(line string) {
-			fmt.Println(line)
+		// Define the callback function printing each assembled event
+		printEvent := func(ev ssergocli.Event) {
+			if ev.Type != "" {
+				fmt.Printf("[%s] ", ev.Type)
+			}
+			if ev.ID != "" {
+				fmt.Printf("(%s) ", ev.ID)
+			}
+			fmt.Println(ev.Data)
 		}
 
-		if err := client.SubscribeToTopic(id, topicAccessToken, printEventLine); err != nil {
+		reason, err := client.SubscribeToTopic(id, topicAccessToken, printEvent)
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error subscribing to topic: %v\n", err)
 			os.Exit(1)
 		}
+		if reason != ssergocli.CloseReasonUnknown {
+			fmt.Printf("Stream closed by server with reason: %s\n", reason)
+		}
 
 	case "help":
 		printUsage()
@@ -0,0 +1,36 @@
+package ssergo
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// publish retry defaults; see Params.PublishMaxRetries and
+// Params.PublishRetryBackoff.
+const (
+	defaultPublishMaxRetries   = 3
+	defaultPublishRetryBackoff = 250 * time.Millisecond
+)
+
+// newIdempotencyKey returns a random key identifying one logical message
+// across publish retries.
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// isRetryablePublishError reports whether a publish attempt may be retried:
+// network errors and 5xx responses are transient, 4xx responses are not.
+func isRetryablePublishError(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	// anything that isn't an API error is a transport failure
+	return true
+}
@@ -0,0 +1,126 @@
+package ssergo
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// EventIterator pulls assembled SSE events one at a time, making streams
+// natural to consume with plain for-loops or errgroup workers instead of
+// callbacks.
+type EventIterator interface {
+	// Next blocks until the next event arrives, the stream ends, or ctx is
+	// done. It returns io.EOF once the stream has ended; after that
+	// CloseReason reports the server's terminal close frame (if any).
+	Next(ctx context.Context) (Event, error)
+	// CloseReason reports the close reason announced by the server; it is
+	// CloseReasonUnknown until Next has returned io.EOF.
+	CloseReason() CloseReason
+	// Close tears the stream down early; pending and later Next calls
+	// return io.EOF.
+	Close() error
+}
+
+// Stream opens a subscription and returns an iterator over its events.
+// Cancelling ctx tears the stream down. WithTransport(TransportWebSocket)
+// negotiates a WebSocket upgrade first and silently falls back to SSE when
+// the server declines. Callers should Close the iterator once done
+// consuming.
+func (c *sserClient) Stream(ctx context.Context, id string, topicAccessToken string, opts ...StreamOption) (EventIterator, error) {
+	cfg := streamConfig{transport: TransportSSE}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.transport == TransportWebSocket {
+		it, err := c.openWSIterator(ctx, id, topicAccessToken)
+		if err == nil {
+			return it, nil
+		}
+		c.logger.Printf("WebSocket negotiation failed (%v); falling back to SSE", err)
+	}
+
+	resp, err := c.openStream(ctx, id, topicAccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	it := &eventIterator{
+		events: make(chan Event),
+		done:   make(chan struct{}),
+		body:   resp.Body,
+		reason: CloseReasonUnknown,
+	}
+	go it.consume(ctx)
+	return it, nil
+}
+
+type eventIterator struct {
+	events chan Event
+	done   chan struct{}
+	body   io.ReadCloser
+
+	mutex     sync.Mutex
+	closed    bool
+	reason    CloseReason
+	streamErr error
+}
+
+// consume reads the wire stream, handing each event to Next callers until
+// the stream ends or the iterator is closed.
+func (it *eventIterator) consume(ctx context.Context) {
+	reason, _, err := consumeSSE(it.body, func(ev Event) {
+		select {
+		case it.events <- ev:
+		case <-it.done:
+		case <-ctx.Done():
+		}
+	})
+
+	it.mutex.Lock()
+	it.reason = reason
+	// read errors caused by cancellation or an early Close are the expected
+	// teardown path, not stream failures
+	if err != nil && ctx.Err() == nil && !it.closed {
+		it.streamErr = err
+	}
+	it.mutex.Unlock()
+	close(it.events)
+}
+
+func (it *eventIterator) Next(ctx context.Context) (Event, error) {
+	select {
+	case ev, ok := <-it.events:
+		if !ok {
+			it.mutex.Lock()
+			err := it.streamErr
+			it.mutex.Unlock()
+			if err != nil {
+				return Event{}, err
+			}
+			return Event{}, io.EOF
+		}
+		return ev, nil
+	case <-ctx.Done():
+		return Event{}, ctx.Err()
+	}
+}
+
+func (it *eventIterator) CloseReason() CloseReason {
+	it.mutex.Lock()
+	defer it.mutex.Unlock()
+	return it.reason
+}
+
+func (it *eventIterator) Close() error {
+	it.mutex.Lock()
+	if it.closed {
+		it.mutex.Unlock()
+		return nil
+	}
+	it.closed = true
+	close(it.done)
+	it.mutex.Unlock()
+	return it.body.Close()
+}
@@ -0,0 +1,98 @@
+package ssergo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// MetricSchema identifies metric snapshot events on the reserved metrics
+// topic; events with another schema (e.g. alerts) are filtered out.
+const MetricSchema = "sser.metrics.v1"
+
+// Metric is one named counter as reported by the metrics endpoint.
+type Metric struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// MetricsReport is the typed result of GetMetrics. When Since is set the
+// counters are deltas measured from that snapshot time rather than absolute
+// values.
+type MetricsReport struct {
+	Metrics []Metric `json:"metrics"`
+	Since   string   `json:"since,omitempty"`
+}
+
+// MetricEvent is one decoded snapshot from the reserved metrics topic.
+type MetricEvent struct {
+	Schema  string           `json:"schema"`
+	Metrics map[string]int64 `json:"metrics"`
+}
+
+// GetMetrics fetches the server's counters through the metrics endpoint,
+// authenticating with the configured MetricsAccessToken.
+func (c *sserClient) GetMetrics(ctx context.Context) (*MetricsReport, error) {
+	if c.metricsToken == "" {
+		return nil, errors.New("MetricsAccessToken is not configured on the client")
+	}
+
+	url := fmt.Sprintf("%s/api/v1/metrics", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.metricsToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, decodeAPIError(resp.StatusCode, responseBody)
+	}
+
+	var report MetricsReport
+	if err := json.Unmarshal(responseBody, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse metrics response: %w", err)
+	}
+	return &report, nil
+}
+
+// SubscribeToMetrics subscribes to the reserved metrics topic (topicID is
+// its id, "0" unless the server moved it) and returns a typed stream of
+// metric snapshots. Events with a different schema are skipped. The channel
+// is closed when the stream ends or ctx is cancelled.
+func (c *sserClient) SubscribeToMetrics(ctx context.Context, topicID string) (<-chan MetricEvent, error) {
+	if c.metricsToken == "" {
+		return nil, errors.New("MetricsAccessToken is not configured on the client")
+	}
+
+	events, err := c.Subscribe(ctx, topicID, c.metricsToken)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan MetricEvent)
+	go func() {
+		defer close(ch)
+		for ev := range events {
+			var me MetricEvent
+			if json.Unmarshal([]byte(ev.Data), &me) != nil || me.Schema != MetricSchema {
+				continue
+			}
+			select {
+			case ch <- me:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
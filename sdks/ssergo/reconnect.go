@@ -0,0 +1,95 @@
+package ssergo
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// reconnect backoff defaults; the server's `retry:` hint overrides the
+// computed backoff when present.
+const (
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+)
+
+// SubscribeWithReconnect is SubscribeWithReconnectCtx with
+// context.Background().
+func (c *sserClient) SubscribeWithReconnect(id string, topicAccessToken string, callback EventCallback) (CloseReason, error) {
+	return c.SubscribeWithReconnectCtx(context.Background(), id, topicAccessToken, callback)
+}
+
+// SubscribeWithReconnectCtx keeps a subscription alive across connection
+// drops: it reconnects with exponential backoff plus jitter, honors the
+// server's `retry:` hint, and resends the last received event ID so the
+// server replays the events missed while disconnected. It returns when the
+// context is cancelled, the server announces a terminal close reason, or an
+// unrecoverable API error (4xx) occurs.
+func (c *sserClient) SubscribeWithReconnectCtx(ctx context.Context, id string, topicAccessToken string, callback EventCallback) (CloseReason, error) {
+	var (
+		lastEventID string
+		retryHint   time.Duration
+		backoff     = defaultInitialBackoff
+	)
+
+	// track the last delivered event id for resumes
+	wrapped := func(ev Event) {
+		if ev.ID != "" {
+			lastEventID = ev.ID
+		}
+		callback(ev)
+	}
+
+	for {
+		resp, err := c.openStreamResume(ctx, id, topicAccessToken, lastEventID)
+		if err != nil {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 {
+				// auth/validation failures won't heal by retrying
+				return CloseReasonUnknown, err
+			}
+			if ctx.Err() != nil {
+				return CloseReasonUnknown, ctx.Err()
+			}
+		} else {
+			var reason CloseReason
+			reason, retryHint, err = consumeSSE(resp.Body, wrapped)
+			resp.Body.Close()
+			if ctx.Err() != nil {
+				return reason, ctx.Err()
+			}
+			switch reason {
+			case CloseReasonUnknown, CloseReasonServerShutdown, CloseReasonIdleTimeout:
+				// transient; fall through to reconnect
+			default:
+				// deleted, expired, revoked, superseded: the topic or token
+				// is gone for good
+				return reason, nil
+			}
+			// a clean read means the connection dropped or the server asked
+			// for a re-subscribe; start over from a small backoff
+			if err == nil {
+				backoff = defaultInitialBackoff
+			}
+		}
+
+		wait := backoff
+		if retryHint > 0 {
+			wait = retryHint
+		}
+		// full jitter avoids reconnect stampedes after a server restart
+		wait = time.Duration(float64(wait) * (0.5 + rand.Float64()/2))
+
+		select {
+		case <-ctx.Done():
+			return CloseReasonUnknown, ctx.Err()
+		case <-time.After(wait):
+		}
+
+		backoff *= 2
+		if backoff > defaultMaxBackoff {
+			backoff = defaultMaxBackoff
+		}
+	}
+}
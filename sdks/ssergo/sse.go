@@ -0,0 +1,103 @@
+package ssergo
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is a fully assembled SSE event: multi-line data is joined with
+// newlines, and the event name, id, and retry hint are exposed as typed
+// fields.
+type Event struct {
+	// ID is the value of the last `id:` field seen for this event.
+	ID string
+	// Type is the event name from the `event:` field; empty means the
+	// default "message" event.
+	Type string
+	// Data is the event payload with multi-line `data:` fields joined by
+	// newlines.
+	Data string
+	// Retry carries the server's reconnection hint when a `retry:` field was
+	// seen on the stream; zero otherwise.
+	Retry time.Duration
+}
+
+// EventHandler processes a single assembled SSE event.
+type EventHandler func(ev Event)
+
+// consumeSSE parses the SSE wire format from the stream, dispatching each
+// assembled event to the handler. It returns the close reason announced via
+// a terminal `event: close` frame (if any) and the last `retry:` hint.
+func consumeSSE(body io.Reader, handler EventHandler) (CloseReason, time.Duration, error) {
+	var (
+		ev          Event
+		dataLines   []string
+		closeReason = CloseReasonUnknown
+		retryHint   time.Duration
+	)
+
+	dispatch := func() {
+		if len(dataLines) == 0 && ev.ID == "" && ev.Type == "" {
+			return
+		}
+		ev.Data = strings.Join(dataLines, "\n")
+		if ev.Type == eventTypeClose {
+			closeReason = parseCloseReason(ev.Data)
+		}
+		if handler != nil {
+			handler(ev)
+		}
+		ev = Event{Retry: retryHint}
+		dataLines = nil
+	}
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			dispatch()
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			// comment lines are used as keepalives; nothing to deliver
+			continue
+		}
+
+		field, value := splitSSEField(line)
+		switch field {
+		case "data":
+			dataLines = append(dataLines, value)
+		case "event":
+			ev.Type = value
+		case "id":
+			ev.ID = value
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+				retryHint = time.Duration(ms) * time.Millisecond
+				ev.Retry = retryHint
+			}
+		}
+	}
+	dispatch()
+
+	err := scanner.Err()
+	if err == io.EOF {
+		err = nil
+	}
+	return closeReason, retryHint, err
+}
+
+// splitSSEField splits a "field: value" line, stripping the single optional
+// leading space the SSE spec allows in the value.
+func splitSSEField(line string) (string, string) {
+	field, value, found := strings.Cut(line, ":")
+	if !found {
+		return line, ""
+	}
+	return field, strings.TrimPrefix(value, " ")
+}
+
+const eventTypeClose = "close"
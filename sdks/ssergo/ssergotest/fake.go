@@ -0,0 +1,126 @@
+// Package ssergotest provides test doubles for the ssergo SDK: a scriptable
+// FakeClient implementing SSERClient, and an httptest-backed SSE server that
+// emits scripted events so subscription handling can be unit tested without
+// a real broker.
+package ssergotest
+
+import (
+	"context"
+	"errors"
+
+	ssergo "github.com/hasmcp/sser/sdks/ssergo"
+)
+
+// compile-time proof the fake keeps tracking the real interface
+var _ ssergo.SSERClient = (*FakeClient)(nil)
+
+// FakeClient is a scriptable fake of SSERClient: each method delegates to
+// the matching function field when it is set and otherwise fails with an
+// error naming the method, so tests exercising an unscripted path fail
+// loudly. The plain and Ctx variants share one function field.
+type FakeClient struct {
+	CreatePubSubFunc          func(ctx context.Context, opts ...ssergo.CreateOption) (*ssergo.PubSub, error)
+	DeletePubSubFunc          func(ctx context.Context, id string) error
+	PublishEventFunc          func(ctx context.Context, id, message, eventID, eventType string) (*ssergo.PublishResult, error)
+	PublishEncryptedEventFunc func(ctx context.Context, id, message, eventID, eventType string, cipher *ssergo.PayloadCipher) (*ssergo.PublishResult, error)
+	SubscribeToTopicFunc      func(ctx context.Context, id, topicAccessToken string, callback ssergo.EventCallback) (ssergo.CloseReason, error)
+	SubscribeFunc             func(ctx context.Context, id, topicAccessToken string) (<-chan ssergo.Event, error)
+	StreamFunc                func(ctx context.Context, id, topicAccessToken string, opts ...ssergo.StreamOption) (ssergo.EventIterator, error)
+	GetMetricsFunc            func(ctx context.Context) (*ssergo.MetricsReport, error)
+	SubscribeToMetricsFunc    func(ctx context.Context, topicID string) (<-chan ssergo.MetricEvent, error)
+}
+
+func notScripted(method string) error {
+	return errors.New("ssergotest: " + method + " is not scripted on the fake client")
+}
+
+func (f *FakeClient) CreatePubSub(opts ...ssergo.CreateOption) (*ssergo.PubSub, error) {
+	return f.CreatePubSubCtx(context.Background(), opts...)
+}
+
+func (f *FakeClient) CreatePubSubCtx(ctx context.Context, opts ...ssergo.CreateOption) (*ssergo.PubSub, error) {
+	if f.CreatePubSubFunc == nil {
+		return nil, notScripted("CreatePubSub")
+	}
+	return f.CreatePubSubFunc(ctx, opts...)
+}
+
+func (f *FakeClient) DeletePubSub(id string) error {
+	return f.DeletePubSubCtx(context.Background(), id)
+}
+
+func (f *FakeClient) DeletePubSubCtx(ctx context.Context, id string) error {
+	if f.DeletePubSubFunc == nil {
+		return notScripted("DeletePubSub")
+	}
+	return f.DeletePubSubFunc(ctx, id)
+}
+
+func (f *FakeClient) PublishEvent(id string, message string, eventID string, eventType string) (*ssergo.PublishResult, error) {
+	return f.PublishEventCtx(context.Background(), id, message, eventID, eventType)
+}
+
+func (f *FakeClient) PublishEventCtx(ctx context.Context, id string, message string, eventID string, eventType string) (*ssergo.PublishResult, error) {
+	if f.PublishEventFunc == nil {
+		return nil, notScripted("PublishEvent")
+	}
+	return f.PublishEventFunc(ctx, id, message, eventID, eventType)
+}
+
+func (f *FakeClient) PublishEncryptedEvent(id string, message string, eventID string, eventType string, cipher *ssergo.PayloadCipher) (*ssergo.PublishResult, error) {
+	return f.PublishEncryptedEventCtx(context.Background(), id, message, eventID, eventType, cipher)
+}
+
+func (f *FakeClient) PublishEncryptedEventCtx(ctx context.Context, id string, message string, eventID string, eventType string, cipher *ssergo.PayloadCipher) (*ssergo.PublishResult, error) {
+	if f.PublishEncryptedEventFunc == nil {
+		return nil, notScripted("PublishEncryptedEvent")
+	}
+	return f.PublishEncryptedEventFunc(ctx, id, message, eventID, eventType, cipher)
+}
+
+func (f *FakeClient) SubscribeToTopic(id string, topicAccessToken string, callback ssergo.EventCallback) (ssergo.CloseReason, error) {
+	return f.SubscribeToTopicCtx(context.Background(), id, topicAccessToken, callback)
+}
+
+func (f *FakeClient) SubscribeToTopicCtx(ctx context.Context, id string, topicAccessToken string, callback ssergo.EventCallback) (ssergo.CloseReason, error) {
+	if f.SubscribeToTopicFunc == nil {
+		return ssergo.CloseReasonUnknown, notScripted("SubscribeToTopic")
+	}
+	return f.SubscribeToTopicFunc(ctx, id, topicAccessToken, callback)
+}
+
+func (f *FakeClient) Subscribe(ctx context.Context, id string, topicAccessToken string) (<-chan ssergo.Event, error) {
+	if f.SubscribeFunc == nil {
+		return nil, notScripted("Subscribe")
+	}
+	return f.SubscribeFunc(ctx, id, topicAccessToken)
+}
+
+func (f *FakeClient) Stream(ctx context.Context, id string, topicAccessToken string, opts ...ssergo.StreamOption) (ssergo.EventIterator, error) {
+	if f.StreamFunc == nil {
+		return nil, notScripted("Stream")
+	}
+	return f.StreamFunc(ctx, id, topicAccessToken, opts...)
+}
+
+func (f *FakeClient) GetMetrics(ctx context.Context) (*ssergo.MetricsReport, error) {
+	if f.GetMetricsFunc == nil {
+		return nil, notScripted("GetMetrics")
+	}
+	return f.GetMetricsFunc(ctx)
+}
+
+func (f *FakeClient) SubscribeToMetrics(ctx context.Context, topicID string) (<-chan ssergo.MetricEvent, error) {
+	if f.SubscribeToMetricsFunc == nil {
+		return nil, notScripted("SubscribeToMetrics")
+	}
+	return f.SubscribeToMetricsFunc(ctx, topicID)
+}
+
+func (f *FakeClient) SubscribeWithReconnect(id string, topicAccessToken string, callback ssergo.EventCallback) (ssergo.CloseReason, error) {
+	return f.SubscribeWithReconnectCtx(context.Background(), id, topicAccessToken, callback)
+}
+
+func (f *FakeClient) SubscribeWithReconnectCtx(ctx context.Context, id string, topicAccessToken string, callback ssergo.EventCallback) (ssergo.CloseReason, error) {
+	return f.SubscribeToTopicCtx(ctx, id, topicAccessToken, callback)
+}
@@ -0,0 +1,98 @@
+package ssergotest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	ssergo "github.com/hasmcp/sser/sdks/ssergo"
+)
+
+// StreamServer is an httptest-backed SSE endpoint that replies to every GET
+// with a scripted sequence of events followed by an optional terminal close
+// frame. Point a real ssergo client at URL() to unit test subscription
+// handling end to end, wire format included.
+type StreamServer struct {
+	srv *httptest.Server
+
+	mutex  sync.Mutex
+	events []ssergo.Event
+	close  ssergo.CloseReason
+
+	// lastEventIDs records the Last-Event-ID header of each stream request,
+	// oldest first, so reconnect behavior can be asserted
+	lastEventIDs []string
+}
+
+// NewStreamServer starts a server scripted with the given events; closeReason
+// (when non-empty) is announced as a terminal `event: close` frame after the
+// scripted events, ending the stream the way a real broker would.
+func NewStreamServer(events []ssergo.Event, closeReason ssergo.CloseReason) *StreamServer {
+	s := &StreamServer{events: events, close: closeReason}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.stream))
+	return s
+}
+
+// URL is the base address to hand to the ssergo client constructor.
+func (s *StreamServer) URL() string {
+	return s.srv.URL
+}
+
+// LastEventIDs returns the Last-Event-ID header of every stream request
+// served so far, oldest first.
+func (s *StreamServer) LastEventIDs() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.lastEventIDs...)
+}
+
+// Close shuts the server down.
+func (s *StreamServer) Close() {
+	s.srv.Close()
+}
+
+func (s *StreamServer) stream(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	s.lastEventIDs = append(s.lastEventIDs, r.Header.Get("Last-Event-ID"))
+	events := append([]ssergo.Event(nil), s.events...)
+	closeReason := s.close
+	s.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	for _, ev := range events {
+		writeEvent(w, ev)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	if closeReason != "" {
+		fmt.Fprintf(w, "event: close\ndata: {\"reason\": \"%s\"}\n\n", closeReason)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent renders one event in the SSE wire format, splitting multi-line
+// data across data: fields like a real server.
+func writeEvent(w http.ResponseWriter, ev ssergo.Event) {
+	if ev.Retry > 0 {
+		fmt.Fprintf(w, "retry: %d\n", ev.Retry.Milliseconds())
+	}
+	if ev.ID != "" {
+		fmt.Fprintf(w, "id: %s\n", ev.ID)
+	}
+	if ev.Type != "" {
+		fmt.Fprintf(w, "event: %s\n", ev.Type)
+	}
+	for _, line := range strings.Split(ev.Data, "\n") {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	fmt.Fprint(w, "\n")
+}
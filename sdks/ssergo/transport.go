@@ -0,0 +1,66 @@
+package ssergo
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportParams tunes the HTTP connection pool for high-rate publishers.
+// Zero values pick defaults suited to a steady stream of small requests
+// against a single host.
+type TransportParams struct {
+	// MaxIdleConnsPerHost caps the keep-alive connections kept open to the
+	// server; it should be at least the number of concurrent publishers.
+	// 0 picks 16.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout closes keep-alive connections idle for this long.
+	// 0 picks 90s.
+	IdleConnTimeout time.Duration
+	// KeepAlive is the TCP keep-alive probe interval; a negative value
+	// disables probes. 0 picks 30s.
+	KeepAlive time.Duration
+	// DisableHTTP2 forces HTTP/1.1; by default HTTP/2 is attempted when the
+	// server supports it.
+	DisableHTTP2 bool
+	// RequestTimeout bounds each request end to end. 0 picks 10s. Do not
+	// reuse a client built with this for subscriptions — streams need no
+	// timeout and the SDK handles that itself.
+	RequestTimeout time.Duration
+}
+
+// NewHTTPClient builds an *http.Client with a tuned connection pool to hand
+// to Params.HTTPClient, so high-frequency producers reuse warm connections
+// instead of serializing on a cold default transport.
+func NewHTTPClient(p TransportParams) *http.Client {
+	if p.MaxIdleConnsPerHost == 0 {
+		p.MaxIdleConnsPerHost = 16
+	}
+	if p.IdleConnTimeout == 0 {
+		p.IdleConnTimeout = 90 * time.Second
+	}
+	if p.KeepAlive == 0 {
+		p.KeepAlive = 30 * time.Second
+	}
+	if p.RequestTimeout == 0 {
+		p.RequestTimeout = 10 * time.Second
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: p.KeepAlive,
+	}
+
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		MaxIdleConns:        p.MaxIdleConnsPerHost,
+		MaxIdleConnsPerHost: p.MaxIdleConnsPerHost,
+		IdleConnTimeout:     p.IdleConnTimeout,
+		ForceAttemptHTTP2:   !p.DisableHTTP2,
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   p.RequestTimeout,
+	}
+}
@@ -0,0 +1,197 @@
+package ssergo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Transport selects the wire protocol for Stream subscriptions.
+type Transport string
+
+const (
+	// TransportSSE streams over plain HTTP server-sent events (the default).
+	TransportSSE Transport = "sse"
+	// TransportWebSocket negotiates a WebSocket upgrade and falls back to
+	// SSE when the server doesn't support it yet.
+	TransportWebSocket Transport = "websocket"
+)
+
+// StreamOption configures a Stream subscription.
+type StreamOption func(*streamConfig)
+
+type streamConfig struct {
+	transport Transport
+}
+
+// WithTransport selects the subscription transport; unknown values keep the
+// SSE default.
+func WithTransport(t Transport) StreamOption {
+	return func(cfg *streamConfig) {
+		cfg.transport = t
+	}
+}
+
+// wsEvent is the wire format of one event on the WebSocket transport: the
+// same fields SSE spells out as id/event/data/retry lines, as one JSON
+// message.
+type wsEvent struct {
+	ID      string `json:"id,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Data    string `json:"data"`
+	RetryMS int64  `json:"retry_ms,omitempty"`
+}
+
+// openWSIterator upgrades the events endpoint to a WebSocket and wraps the
+// connection in the shared iterator shape.
+func (c *sserClient) openWSIterator(ctx context.Context, id string, topicAccessToken string) (EventIterator, error) {
+	// http -> ws, https -> wss
+	wsURL := "ws" + strings.TrimPrefix(c.baseURL, "http")
+	url := fmt.Sprintf("%s/api/v1/pubsubs/%s/events", wsURL, id)
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+topicAccessToken)
+
+	dialer := websocket.Dialer{HandshakeTimeout: 10 * time.Second}
+	conn, resp, err := dialer.DialContext(ctx, url, header)
+	if err != nil {
+		if resp != nil {
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			return nil, decodeAPIError(resp.StatusCode, body)
+		}
+		return nil, fmt.Errorf("failed to negotiate websocket: %w", err)
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+
+	it := &wsIterator{
+		events: make(chan Event),
+		done:   make(chan struct{}),
+		conn:   conn,
+		reason: CloseReasonUnknown,
+	}
+	go it.consume(ctx)
+	return it, nil
+}
+
+// wsIterator adapts a WebSocket connection to the EventIterator contract so
+// callers consume both transports the same way.
+type wsIterator struct {
+	events chan Event
+	done   chan struct{}
+	conn   *websocket.Conn
+
+	mutex     sync.Mutex
+	closed    bool
+	reason    CloseReason
+	streamErr error
+}
+
+func (it *wsIterator) consume(ctx context.Context) {
+	// unblock the reader when the caller cancels or closes early
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-it.done:
+		}
+		_ = it.conn.Close()
+	}()
+
+	var (
+		reason    = CloseReasonUnknown
+		streamErr error
+	)
+	for {
+		_, msg, err := it.conn.ReadMessage()
+		if err != nil {
+			// a normal close carries the server's terminal reason as the
+			// close frame text, mirroring the SSE close event
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) {
+				if closeErr.Text != "" {
+					reason = CloseReason(closeErr.Text)
+				}
+			} else if ctx.Err() == nil && !it.isClosed() {
+				streamErr = err
+			}
+			break
+		}
+
+		var wev wsEvent
+		if err := json.Unmarshal(msg, &wev); err != nil {
+			continue
+		}
+		ev := Event{
+			ID:    wev.ID,
+			Type:  wev.Type,
+			Data:  wev.Data,
+			Retry: time.Duration(wev.RetryMS) * time.Millisecond,
+		}
+		if ev.Type == eventTypeClose {
+			reason = parseCloseReason(ev.Data)
+		}
+		select {
+		case it.events <- ev:
+		case <-it.done:
+		case <-ctx.Done():
+		}
+	}
+
+	it.mutex.Lock()
+	it.reason = reason
+	it.streamErr = streamErr
+	it.mutex.Unlock()
+	close(it.events)
+}
+
+func (it *wsIterator) isClosed() bool {
+	it.mutex.Lock()
+	defer it.mutex.Unlock()
+	return it.closed
+}
+
+func (it *wsIterator) Next(ctx context.Context) (Event, error) {
+	select {
+	case ev, ok := <-it.events:
+		if !ok {
+			it.mutex.Lock()
+			err := it.streamErr
+			it.mutex.Unlock()
+			if err != nil {
+				return Event{}, err
+			}
+			return Event{}, io.EOF
+		}
+		return ev, nil
+	case <-ctx.Done():
+		return Event{}, ctx.Err()
+	}
+}
+
+func (it *wsIterator) CloseReason() CloseReason {
+	it.mutex.Lock()
+	defer it.mutex.Unlock()
+	return it.reason
+}
+
+func (it *wsIterator) Close() error {
+	it.mutex.Lock()
+	if it.closed {
+		it.mutex.Unlock()
+		return nil
+	}
+	it.closed = true
+	close(it.done)
+	it.mutex.Unlock()
+	return it.conn.Close()
+}
@@ -0,0 +1,211 @@
+"""Python SDK for the SSER PubSub API, mirroring the surface of the Go SDK
+(ssergo): create/delete topics, publish events, and subscribe to SSE streams.
+
+The client uses only the standard library (urllib) so it can be dropped into
+any environment without pinning dependencies; the SSE parser is hand-rolled
+against the spec (id/event/data/retry fields, blank-line dispatch, comment
+keepalives).
+"""
+
+import json
+import logging
+import time
+import urllib.error
+import urllib.request
+from dataclasses import dataclass
+
+
+class SSERError(Exception):
+    """Raised when the server answers with a non-2xx status."""
+
+    def __init__(self, message, status=None, code=None):
+        super().__init__(message)
+        self.status = status
+        self.code = code
+
+
+@dataclass
+class PubSub:
+    """Typed result of create_pubsub."""
+
+    id: str
+    token: str
+    token_expires_at: str = ""
+
+
+@dataclass
+class PublishResult:
+    """Typed result of publish_event."""
+
+    id: str
+
+
+@dataclass
+class Event:
+    """One assembled SSE event."""
+
+    id: str = ""
+    type: str = ""
+    data: str = ""
+
+
+# Close reasons that won't heal by reconnecting.
+TERMINAL_CLOSE_REASONS = {"deleted", "expired", "revoked", "superseded"}
+
+
+class SSERClient:
+    """Client for the SSER PubSub API."""
+
+    def __init__(self, base_url, api_access_token, logger=None, timeout=10):
+        if not base_url:
+            raise SSERError("base_url cannot be empty")
+        if not api_access_token:
+            raise SSERError("api_access_token cannot be empty")
+        self.base_url = base_url.rstrip("/")
+        self.api_access_token = api_access_token
+        self.logger = logger or logging.getLogger("sser")
+        self.timeout = timeout
+
+    # -- management calls ---------------------------------------------------
+
+    def create_pubsub(self, persist=False):
+        """Creates a topic and returns its id and access token."""
+        body = {"pubsub": {"persist": True}} if persist else {}
+        payload = self._request("POST", "pubsubs", body)
+        pubsub = payload.get("pubsub", {})
+        return PubSub(
+            id=pubsub.get("id", ""),
+            token=pubsub.get("token", ""),
+            token_expires_at=pubsub.get("token_expires_at", ""),
+        )
+
+    def delete_pubsub(self, pubsub_id):
+        """Deletes a topic by id."""
+        self._request("DELETE", f"pubsubs/{pubsub_id}")
+
+    def publish_event(self, pubsub_id, message, event_id="", event_type=""):
+        """Publishes a message and returns the server-assigned event id."""
+        event = {"message": message}
+        if event_id:
+            event["id"] = event_id
+        if event_type:
+            event["type"] = event_type
+        payload = self._request("POST", f"pubsubs/{pubsub_id}/events", {"event": event})
+        return PublishResult(id=payload.get("event", {}).get("id", ""))
+
+    # -- subscriptions ------------------------------------------------------
+
+    def subscribe(self, pubsub_id, topic_access_token, last_event_id=""):
+        """Yields events from a topic's SSE stream until the server closes it.
+
+        Returns a generator of Event; a terminal `close` event stops the
+        iteration after being yielded so callers can inspect its reason.
+        """
+        url = f"{self.base_url}/api/v1/pubsubs/{pubsub_id}/events"
+        headers = {"Authorization": f"Bearer {topic_access_token}"}
+        if last_event_id:
+            headers["Last-Event-ID"] = last_event_id
+        req = urllib.request.Request(url, headers=headers)
+        try:
+            resp = urllib.request.urlopen(req)
+        except urllib.error.HTTPError as err:
+            raise self._api_error(err.code, err.read()) from err
+        return self._consume_sse(resp)
+
+    def subscribe_with_reconnect(self, pubsub_id, topic_access_token,
+                                 callback, initial_backoff=0.5, max_backoff=30.0):
+        """Calls callback for every event, reconnecting with exponential
+        backoff and resuming from the last received event id. Returns the
+        terminal close reason, or raises on unrecoverable (4xx) errors.
+        """
+        last_event_id = ""
+        backoff = initial_backoff
+        while True:
+            try:
+                clean = False
+                for event in self.subscribe(pubsub_id, topic_access_token, last_event_id):
+                    if event.id:
+                        last_event_id = event.id
+                    if event.type == "close":
+                        reason = self._close_reason(event.data)
+                        if reason in TERMINAL_CLOSE_REASONS:
+                            return reason
+                        clean = True
+                        break
+                    callback(event)
+                else:
+                    clean = True
+                if clean:
+                    backoff = initial_backoff
+            except SSERError as err:
+                if err.status is not None and 400 <= err.status < 500:
+                    raise
+            except OSError:
+                pass
+            time.sleep(backoff)
+            backoff = min(backoff * 2, max_backoff)
+
+    # -- internals ----------------------------------------------------------
+
+    def _consume_sse(self, resp):
+        event = Event()
+        with resp:
+            for raw in resp:
+                line = raw.decode("utf-8").rstrip("\r\n")
+                if line == "":
+                    if event.data or event.type or event.id:
+                        # trailing newline is part of the frame, not the data
+                        event.data = event.data.rstrip("\n")
+                        yield event
+                        if event.type == "close":
+                            return
+                    event = Event()
+                    continue
+                if line.startswith(":"):
+                    continue  # comment keepalive
+                name, _, value = line.partition(":")
+                value = value[1:] if value.startswith(" ") else value
+                if name == "id":
+                    event.id = value
+                elif name == "event":
+                    event.type = value
+                elif name == "data":
+                    event.data += value + "\n"
+        if event.data or event.type or event.id:
+            event.data = event.data.rstrip("\n")
+            yield event
+
+    @staticmethod
+    def _close_reason(data):
+        try:
+            return json.loads(data).get("reason", "")
+        except (ValueError, AttributeError):
+            return ""
+
+    def _request(self, method, path, body=None):
+        url = f"{self.base_url}/api/v1/{path}"
+        data = json.dumps(body).encode("utf-8") if body is not None else None
+        headers = {"Authorization": f"Bearer {self.api_access_token}"}
+        if data is not None:
+            headers["Content-Type"] = "application/json"
+        req = urllib.request.Request(url, data=data, headers=headers, method=method)
+        self.logger.debug("[%s] %s", method, url)
+        try:
+            with urllib.request.urlopen(req, timeout=self.timeout) as resp:
+                text = resp.read()
+        except urllib.error.HTTPError as err:
+            raise self._api_error(err.code, err.read()) from err
+        return json.loads(text) if text else {}
+
+    @staticmethod
+    def _api_error(status, body):
+        code = None
+        message = body.decode("utf-8", errors="replace")
+        try:
+            payload = json.loads(message)
+            code = payload.get("error", {}).get("code")
+            message = payload.get("error", {}).get("message", message)
+        except ValueError:
+            pass
+        return SSERError(f"API request failed with status {status}: {message}",
+                         status=status, code=code)
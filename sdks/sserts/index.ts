@@ -0,0 +1,204 @@
+/**
+ * Minimal TypeScript client for the SSER PubSub API, mirroring the surface of
+ * the Go SDK (ssergo). It targets browser dashboards first: management calls
+ * use fetch, subscriptions use the native EventSource (which carries the topic
+ * token via the `access_token` query parameter, since EventSource cannot set
+ * an Authorization header) and reconnect automatically until the server
+ * announces a terminal close reason.
+ */
+
+/** Error thrown for non-2xx API responses. */
+export class SSERError extends Error {
+  constructor(
+    message: string,
+    public readonly status: number | null = null,
+    public readonly code: number | null = null,
+  ) {
+    super(message);
+    this.name = 'SSERError';
+  }
+}
+
+/** Typed result of createPubSub. */
+export interface PubSub {
+  id: string;
+  token: string;
+  token_expires_at?: string;
+}
+
+/** Typed result of publishEvent. */
+export interface PublishResult {
+  id: string;
+}
+
+/** One assembled SSE event. */
+export interface SSEREvent {
+  id: string;
+  type: string;
+  data: string;
+}
+
+/** Reasons the server may announce before terminating a stream. */
+export type CloseReason =
+  | 'deleted'
+  | 'expired'
+  | 'revoked'
+  | 'server-shutdown'
+  | 'idle-timeout'
+  | 'superseded';
+
+/** Close reasons that won't heal by reconnecting; the subscription stops. */
+const TERMINAL_CLOSE_REASONS: CloseReason[] = ['deleted', 'expired', 'revoked', 'superseded'];
+
+export interface SSERClientParams {
+  baseURL: string;
+  apiAccessToken: string;
+  /** Overridable for Node or test environments; defaults to global fetch. */
+  fetchClient?: typeof fetch;
+  /** Overridable for Node polyfills; defaults to global EventSource. */
+  eventSourceClass?: typeof EventSource;
+}
+
+export interface CreateOptions {
+  persist?: boolean;
+}
+
+export interface SubscribeOptions {
+  /** Called for every non-close event. */
+  onEvent: (event: SSEREvent) => void;
+  /** Called when the server announces a terminal close reason. */
+  onClose?: (reason: CloseReason) => void;
+  /** Called on connection errors; EventSource reconnects on its own. */
+  onError?: (err: Event) => void;
+  /** Extra event types to listen for beyond unnamed `message` events. */
+  eventTypes?: string[];
+}
+
+/** Handle returned by subscribe; close() tears the stream down. */
+export interface Subscription {
+  close(): void;
+}
+
+export class SSERClient {
+  private readonly baseURL: string;
+  private readonly apiAccessToken: string;
+  private readonly fetchClient: typeof fetch;
+  private readonly eventSourceClass: typeof EventSource;
+
+  constructor(params: SSERClientParams) {
+    if (!params.baseURL) {
+      throw new SSERError('baseURL cannot be empty');
+    }
+    if (!params.apiAccessToken) {
+      throw new SSERError('apiAccessToken cannot be empty');
+    }
+    this.baseURL = params.baseURL.replace(/\/$/, '');
+    this.apiAccessToken = params.apiAccessToken;
+    this.fetchClient = params.fetchClient ?? fetch.bind(globalThis);
+    this.eventSourceClass = params.eventSourceClass ?? EventSource;
+  }
+
+  /** Creates a PubSub topic and returns its id and access token. */
+  async createPubSub(opts: CreateOptions = {}): Promise<PubSub> {
+    const body = opts.persist ? { pubsub: { persist: true } } : {};
+    const result = await this.request<{ pubsub: PubSub }>('pubsubs', {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify(body),
+    });
+    return result.pubsub;
+  }
+
+  /** Deletes a PubSub topic by id. */
+  async deletePubSub(id: string): Promise<void> {
+    await this.request(`pubsubs/${id}`, { method: 'DELETE' });
+  }
+
+  /** Publishes a message to a topic and returns the server-assigned event id. */
+  async publishEvent(
+    id: string,
+    message: string,
+    eventID?: string,
+    eventType?: string,
+  ): Promise<PublishResult> {
+    const event: Record<string, string> = { message };
+    if (eventID) {
+      event.id = eventID;
+    }
+    if (eventType) {
+      event.type = eventType;
+    }
+    const result = await this.request<{ event: PublishResult }>(`pubsubs/${id}/events`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ event }),
+    });
+    return result.event;
+  }
+
+  /**
+   * Subscribes to a topic via EventSource. The topic token travels in the
+   * `access_token` query parameter. EventSource reconnects automatically on
+   * connection loss (resending Last-Event-ID so the server replays missed
+   * events); the stream is only torn down on a terminal close reason or an
+   * explicit close() on the returned subscription.
+   */
+  subscribe(id: string, topicAccessToken: string, opts: SubscribeOptions): Subscription {
+    const url = `${this.baseURL}/api/v1/pubsubs/${id}/events?access_token=${encodeURIComponent(topicAccessToken)}`;
+    const source = new this.eventSourceClass(url);
+
+    const deliver = (ev: MessageEvent, type: string) => {
+      opts.onEvent({ id: ev.lastEventId ?? '', type, data: ev.data });
+    };
+
+    source.onmessage = (ev) => deliver(ev, '');
+    for (const type of opts.eventTypes ?? []) {
+      source.addEventListener(type, (ev) => deliver(ev as MessageEvent, type));
+    }
+
+    source.addEventListener('close', (ev) => {
+      let reason: CloseReason | '' = '';
+      try {
+        reason = JSON.parse((ev as MessageEvent).data).reason ?? '';
+      } catch {
+        // fall through; an unparsable close payload is treated as transient
+      }
+      if (reason && TERMINAL_CLOSE_REASONS.includes(reason)) {
+        source.close();
+        opts.onClose?.(reason);
+      }
+    });
+
+    source.onerror = (err) => {
+      opts.onError?.(err);
+    };
+
+    return { close: () => source.close() };
+  }
+
+  private async request<T>(path: string, init: RequestInit): Promise<T> {
+    const url = `${this.baseURL}/api/v1/${path}`;
+    const response = await this.fetchClient(url, {
+      ...init,
+      headers: {
+        Authorization: `Bearer ${this.apiAccessToken}`,
+        ...init.headers,
+      },
+    });
+
+    const text = await response.text();
+    if (!response.ok) {
+      let code: number | null = null;
+      let message = text;
+      try {
+        const payload = JSON.parse(text);
+        code = payload.error?.code ?? null;
+        message = payload.error?.message ?? text;
+      } catch {
+        // keep the raw body as the message
+      }
+      throw new SSERError(`API request failed with status ${response.status}: ${message}`, response.status, code);
+    }
+    return (text ? JSON.parse(text) : {}) as T;
+  }
+}
@@ -0,0 +1,217 @@
+// Package sser exposes the pubsub server as an embeddable library: it accepts
+// programmatic configuration (no yaml files required), hands out a fasthttp
+// request handler that can be mounted into an existing app, and offers
+// programmatic topic management.
+package sser
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	"github.com/hasmcp/sser/internal/controller/pubsub"
+	httphandler "github.com/hasmcp/sser/internal/handler/http"
+	"github.com/hasmcp/sser/internal/recorder/kv"
+	"github.com/hasmcp/sser/internal/servicer/config"
+	"github.com/hasmcp/sser/internal/servicer/idgen"
+	"github.com/mustafaturan/monoflake"
+	"github.com/valyala/fasthttp"
+)
+
+type (
+	// Config carries the programmatic equivalent of the yaml sections needed
+	// to run an embedded server.
+	Config struct {
+		AppName    string
+		AppVersion string
+		Env        string
+
+		ApiAccessToken     string
+		MetricsAccessToken string
+
+		TickFrequency                     time.Duration
+		MaxDurationForSubscriberToReceive time.Duration
+		MaxStreamLifetime                 time.Duration
+		ReconnectRetryHint                time.Duration
+
+		// IDGenNode is the monoflake node id; 0 picks one at random.
+		IDGenNode uint16
+		// IDGenEpochTimeInSeconds anchors generated ids; 0 uses the unix epoch.
+		IDGenEpochTimeInSeconds int64
+		// IDGenNodeBits configures the monoflake node bit width.
+		IDGenNodeBits int
+
+		// KVPath enables bbolt persistence when non-empty.
+		KVPath string
+
+		StaticPubSubs []StaticPubSub
+	}
+
+	// StaticPubSub mirrors the static pubsub yaml entries.
+	StaticPubSub struct {
+		ID    int64
+		Name  string
+		Token string
+	}
+
+	// Topic is the public view of a created pubsub.
+	Topic struct {
+		ID             string
+		Token          string
+		TokenExpiresAt time.Time
+	}
+
+	// CreateTopicOptions configures programmatic topic creation.
+	CreateTopicOptions struct {
+		Persist  bool
+		TokenTTL time.Duration
+	}
+
+	// Server is an embedded sser instance.
+	Server struct {
+		cfg     Config
+		pubsub  pubsub.Controller
+		handler httphandler.Handler
+		kv      kv.Recorder
+	}
+)
+
+// New builds an embedded server from programmatic config.
+func New(cfg Config) (*Server, error) {
+	if cfg.Env == "" {
+		cfg.Env = "embedded"
+	}
+	if cfg.TickFrequency <= 0 {
+		cfg.TickFrequency = 15 * time.Second
+	}
+	if cfg.MaxDurationForSubscriberToReceive <= 0 {
+		cfg.MaxDurationForSubscriberToReceive = time.Second
+	}
+
+	staticPubSubs := make([]map[string]interface{}, 0, len(cfg.StaticPubSubs))
+	for _, ps := range cfg.StaticPubSubs {
+		staticPubSubs = append(staticPubSubs, map[string]interface{}{
+			"id":    ps.ID,
+			"name":  ps.Name,
+			"token": ps.Token,
+		})
+	}
+
+	sections := map[string]interface{}{
+		"pubsub": map[string]interface{}{
+			"apiAccessToken":                    cfg.ApiAccessToken,
+			"metricsAccessToken":                cfg.MetricsAccessToken,
+			"tickFrequency":                     cfg.TickFrequency,
+			"maxDurationForSubscriberToReceive": cfg.MaxDurationForSubscriberToReceive,
+			"maxStreamLifetime":                 cfg.MaxStreamLifetime,
+			"reconnectRetryHint":                cfg.ReconnectRetryHint,
+			"staticPubSubs":                     staticPubSubs,
+		},
+		"idgen": map[string]interface{}{
+			"node":               cfg.IDGenNode,
+			"epochTimeInSeconds": cfg.IDGenEpochTimeInSeconds,
+			"nodeBits":           cfg.IDGenNodeBits,
+		},
+		"kv": map[string]interface{}{
+			"enabled": cfg.KVPath != "",
+			"dsn":     cfg.KVPath,
+		},
+	}
+
+	cfgsrv, err := config.NewStatic(cfg.AppName, cfg.AppVersion, cfg.Env, sections)
+	if err != nil {
+		return nil, err
+	}
+
+	idgensrv, err := idgen.New(idgen.Params{Config: cfgsrv})
+	if err != nil {
+		return nil, err
+	}
+
+	kvrecorder, err := kv.New(kv.Params{Config: cfgsrv})
+	if err != nil && !errors.Is(err, kv.ErrNotEnabled) {
+		return nil, err
+	}
+
+	controller, err := pubsub.New(pubsub.Params{
+		Config: cfgsrv,
+		IDGen:  idgensrv,
+		KV:     kvrecorder,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	handler, err := httphandler.New(httphandler.Params{
+		Config: cfgsrv,
+		PubSub: controller,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		cfg:     cfg,
+		pubsub:  controller,
+		handler: handler,
+		kv:      kvrecorder,
+	}, nil
+}
+
+// RequestHandler returns the fasthttp handler serving the full API so it can
+// be mounted into an existing fasthttp server.
+func (s *Server) RequestHandler() fasthttp.RequestHandler {
+	return s.handler.Handle
+}
+
+// Use registers a middleware on the underlying http handler; it must be
+// called before serving requests.
+func (s *Server) Use(m httphandler.Middleware) {
+	s.handler.Use(m)
+}
+
+// CreateTopic creates a pubsub programmatically, bypassing HTTP.
+func (s *Server) CreateTopic(ctx context.Context, opts CreateTopicOptions) (*Topic, error) {
+	res, err := s.pubsub.Create(ctx, entity.CreatePubSubRequest{
+		ApiAccessToken: s.cfg.ApiAccessToken,
+		Persist:        opts.Persist,
+		TokenTTL:       opts.TokenTTL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Topic{
+		ID:             monoflake.ID(res.ID).String(),
+		Token:          string(res.Token),
+		TokenExpiresAt: res.TokenExpiresAt,
+	}, nil
+}
+
+// DeleteTopic removes a pubsub programmatically.
+func (s *Server) DeleteTopic(ctx context.Context, id string) error {
+	return s.pubsub.Delete(ctx, entity.DeletePubSubRequest{
+		ApiAccessToken: s.cfg.ApiAccessToken,
+		ID:             monoflake.IDFromBase62(id).Int64(),
+	})
+}
+
+// Publish sends an event to a topic programmatically.
+func (s *Server) Publish(ctx context.Context, id string, eventID, eventType string, message []byte) error {
+	_, err := s.pubsub.Publish(ctx, entity.PublishRequest{
+		ApiAccessToken: s.cfg.ApiAccessToken,
+		PubSubID:       monoflake.IDFromBase62(id).Int64(),
+		EventID:        eventID,
+		EventType:      eventType,
+		Message:        message,
+	})
+	return err
+}
+
+// Close releases resources held by the embedded server.
+func (s *Server) Close() error {
+	if s.kv != nil {
+		return s.kv.Close()
+	}
+	return nil
+}
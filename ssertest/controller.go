@@ -0,0 +1,251 @@
+package ssertest
+
+import (
+	"context"
+
+	"github.com/hasmcp/sser/internal/_data/entity"
+	"github.com/hasmcp/sser/internal/controller/pubsub"
+)
+
+// compile-time proof the fake keeps tracking the real contract
+var _ pubsub.Controller = (*Controller)(nil)
+
+// Controller is a scriptable fake of the pubsub controller: each method
+// delegates to the matching function field when it is set and otherwise
+// fails with a 501 error naming the method, so a test that exercises an
+// unscripted path fails loudly instead of silently succeeding.
+type Controller struct {
+	CreateFunc             func(ctx context.Context, req entity.CreatePubSubRequest) (*entity.CreatePubSubResponse, error)
+	DeleteFunc             func(ctx context.Context, req entity.DeletePubSubRequest) error
+	PublishFunc            func(ctx context.Context, req entity.PublishRequest) (*entity.PublishResponse, error)
+	SubscribeFunc          func(ctx context.Context, req entity.SubscribeRequest) (*entity.SubscribeResponse, error)
+	PollFunc               func(ctx context.Context, req entity.PollRequest) (*entity.PollResponse, error)
+	GetSubscribersFunc     func(ctx context.Context, req entity.GetSubscribersRequest) (*entity.GetSubscribersResponse, error)
+	UnsubscribeFunc        func(ctx context.Context, req entity.UnsubscribeRequest) error
+	GetMetricsFunc         func(ctx context.Context, req entity.GetMetricsRequest) (*entity.GetMetricsResponse, error)
+	GetTopicRatesFunc      func(ctx context.Context, req entity.GetTopicRatesRequest) (*entity.GetTopicRatesResponse, error)
+	UpsertStaticPubSubFunc func(ctx context.Context, req entity.UpsertStaticPubSubRequest) error
+	DeleteStaticPubSubFunc func(ctx context.Context, req entity.DeleteStaticPubSubRequest) error
+	QueryArchiveFunc       func(ctx context.Context, req entity.QueryArchiveRequest) (*entity.QueryArchiveResponse, error)
+	ResolveNameFunc        func(ctx context.Context, name string) (int64, error)
+	UpdateFunc             func(ctx context.Context, req entity.UpdatePubSubRequest) error
+	PauseFunc              func(ctx context.Context, req entity.PausePubSubRequest) error
+	ResumeFunc             func(ctx context.Context, req entity.ResumePubSubRequest) error
+	ListPubSubsFunc        func(ctx context.Context, req entity.ListPubSubsRequest) (*entity.ListPubSubsResponse, error)
+	SetSchemaFunc          func(ctx context.Context, req entity.SetSchemaRequest) error
+	GetSchemaFunc          func(ctx context.Context, req entity.GetSchemaRequest) (*entity.GetSchemaResponse, error)
+	DeleteSchemaFunc       func(ctx context.Context, req entity.DeleteSchemaRequest) error
+	SetScriptFunc          func(ctx context.Context, req entity.SetScriptRequest) error
+	GetScriptFunc          func(ctx context.Context, req entity.GetScriptRequest) (*entity.GetScriptResponse, error)
+	DeleteScriptFunc       func(ctx context.Context, req entity.DeleteScriptRequest) error
+	ExportTopicsFunc       func(ctx context.Context, req entity.ExportTopicsRequest) (*entity.ExportTopicsResponse, error)
+	ImportTopicsFunc       func(ctx context.Context, req entity.ImportTopicsRequest) (*entity.ImportTopicsResponse, error)
+	BackupStoreFunc        func(ctx context.Context, req entity.BackupStoreRequest) (*entity.BackupStoreResponse, error)
+	GetUsageFunc           func(ctx context.Context, req entity.GetUsageRequest) (*entity.GetUsageResponse, error)
+	ReadyFunc              func(ctx context.Context) error
+}
+
+// notScripted is the failure returned for methods without a scripted func.
+func notScripted(method string) error {
+	return entity.Err{
+		Code:    501,
+		Message: "ssertest: " + method + " is not scripted on the fake controller",
+	}
+}
+
+func (c *Controller) Create(ctx context.Context, req entity.CreatePubSubRequest) (*entity.CreatePubSubResponse, error) {
+	if c.CreateFunc == nil {
+		return nil, notScripted("Create")
+	}
+	return c.CreateFunc(ctx, req)
+}
+
+func (c *Controller) Delete(ctx context.Context, req entity.DeletePubSubRequest) error {
+	if c.DeleteFunc == nil {
+		return notScripted("Delete")
+	}
+	return c.DeleteFunc(ctx, req)
+}
+
+func (c *Controller) Publish(ctx context.Context, req entity.PublishRequest) (*entity.PublishResponse, error) {
+	if c.PublishFunc == nil {
+		return nil, notScripted("Publish")
+	}
+	return c.PublishFunc(ctx, req)
+}
+
+func (c *Controller) Subscribe(ctx context.Context, req entity.SubscribeRequest) (*entity.SubscribeResponse, error) {
+	if c.SubscribeFunc == nil {
+		return nil, notScripted("Subscribe")
+	}
+	return c.SubscribeFunc(ctx, req)
+}
+
+func (c *Controller) Poll(ctx context.Context, req entity.PollRequest) (*entity.PollResponse, error) {
+	if c.PollFunc == nil {
+		return nil, notScripted("Poll")
+	}
+	return c.PollFunc(ctx, req)
+}
+
+func (c *Controller) GetSubscribers(ctx context.Context, req entity.GetSubscribersRequest) (*entity.GetSubscribersResponse, error) {
+	if c.GetSubscribersFunc == nil {
+		return nil, notScripted("GetSubscribers")
+	}
+	return c.GetSubscribersFunc(ctx, req)
+}
+
+func (c *Controller) Unsubscribe(ctx context.Context, req entity.UnsubscribeRequest) error {
+	if c.UnsubscribeFunc == nil {
+		return notScripted("Unsubscribe")
+	}
+	return c.UnsubscribeFunc(ctx, req)
+}
+
+func (c *Controller) GetMetrics(ctx context.Context, req entity.GetMetricsRequest) (*entity.GetMetricsResponse, error) {
+	if c.GetMetricsFunc == nil {
+		return nil, notScripted("GetMetrics")
+	}
+	return c.GetMetricsFunc(ctx, req)
+}
+
+func (c *Controller) GetTopicRates(ctx context.Context, req entity.GetTopicRatesRequest) (*entity.GetTopicRatesResponse, error) {
+	if c.GetTopicRatesFunc == nil {
+		return nil, notScripted("GetTopicRates")
+	}
+	return c.GetTopicRatesFunc(ctx, req)
+}
+
+func (c *Controller) UpsertStaticPubSub(ctx context.Context, req entity.UpsertStaticPubSubRequest) error {
+	if c.UpsertStaticPubSubFunc == nil {
+		return notScripted("UpsertStaticPubSub")
+	}
+	return c.UpsertStaticPubSubFunc(ctx, req)
+}
+
+func (c *Controller) DeleteStaticPubSub(ctx context.Context, req entity.DeleteStaticPubSubRequest) error {
+	if c.DeleteStaticPubSubFunc == nil {
+		return notScripted("DeleteStaticPubSub")
+	}
+	return c.DeleteStaticPubSubFunc(ctx, req)
+}
+
+func (c *Controller) QueryArchive(ctx context.Context, req entity.QueryArchiveRequest) (*entity.QueryArchiveResponse, error) {
+	if c.QueryArchiveFunc == nil {
+		return nil, notScripted("QueryArchive")
+	}
+	return c.QueryArchiveFunc(ctx, req)
+}
+
+func (c *Controller) ResolveName(ctx context.Context, name string) (int64, error) {
+	if c.ResolveNameFunc == nil {
+		return 0, notScripted("ResolveName")
+	}
+	return c.ResolveNameFunc(ctx, name)
+}
+
+func (c *Controller) Update(ctx context.Context, req entity.UpdatePubSubRequest) error {
+	if c.UpdateFunc == nil {
+		return notScripted("Update")
+	}
+	return c.UpdateFunc(ctx, req)
+}
+
+func (c *Controller) Pause(ctx context.Context, req entity.PausePubSubRequest) error {
+	if c.PauseFunc == nil {
+		return notScripted("Pause")
+	}
+	return c.PauseFunc(ctx, req)
+}
+
+func (c *Controller) Resume(ctx context.Context, req entity.ResumePubSubRequest) error {
+	if c.ResumeFunc == nil {
+		return notScripted("Resume")
+	}
+	return c.ResumeFunc(ctx, req)
+}
+
+func (c *Controller) ListPubSubs(ctx context.Context, req entity.ListPubSubsRequest) (*entity.ListPubSubsResponse, error) {
+	if c.ListPubSubsFunc == nil {
+		return nil, notScripted("ListPubSubs")
+	}
+	return c.ListPubSubsFunc(ctx, req)
+}
+
+func (c *Controller) SetSchema(ctx context.Context, req entity.SetSchemaRequest) error {
+	if c.SetSchemaFunc == nil {
+		return notScripted("SetSchema")
+	}
+	return c.SetSchemaFunc(ctx, req)
+}
+
+func (c *Controller) GetSchema(ctx context.Context, req entity.GetSchemaRequest) (*entity.GetSchemaResponse, error) {
+	if c.GetSchemaFunc == nil {
+		return nil, notScripted("GetSchema")
+	}
+	return c.GetSchemaFunc(ctx, req)
+}
+
+func (c *Controller) DeleteSchema(ctx context.Context, req entity.DeleteSchemaRequest) error {
+	if c.DeleteSchemaFunc == nil {
+		return notScripted("DeleteSchema")
+	}
+	return c.DeleteSchemaFunc(ctx, req)
+}
+
+func (c *Controller) SetScript(ctx context.Context, req entity.SetScriptRequest) error {
+	if c.SetScriptFunc == nil {
+		return notScripted("SetScript")
+	}
+	return c.SetScriptFunc(ctx, req)
+}
+
+func (c *Controller) GetScript(ctx context.Context, req entity.GetScriptRequest) (*entity.GetScriptResponse, error) {
+	if c.GetScriptFunc == nil {
+		return nil, notScripted("GetScript")
+	}
+	return c.GetScriptFunc(ctx, req)
+}
+
+func (c *Controller) DeleteScript(ctx context.Context, req entity.DeleteScriptRequest) error {
+	if c.DeleteScriptFunc == nil {
+		return notScripted("DeleteScript")
+	}
+	return c.DeleteScriptFunc(ctx, req)
+}
+
+func (c *Controller) ExportTopics(ctx context.Context, req entity.ExportTopicsRequest) (*entity.ExportTopicsResponse, error) {
+	if c.ExportTopicsFunc == nil {
+		return nil, notScripted("ExportTopics")
+	}
+	return c.ExportTopicsFunc(ctx, req)
+}
+
+func (c *Controller) ImportTopics(ctx context.Context, req entity.ImportTopicsRequest) (*entity.ImportTopicsResponse, error) {
+	if c.ImportTopicsFunc == nil {
+		return nil, notScripted("ImportTopics")
+	}
+	return c.ImportTopicsFunc(ctx, req)
+}
+
+func (c *Controller) BackupStore(ctx context.Context, req entity.BackupStoreRequest) (*entity.BackupStoreResponse, error) {
+	if c.BackupStoreFunc == nil {
+		return nil, notScripted("BackupStore")
+	}
+	return c.BackupStoreFunc(ctx, req)
+}
+
+func (c *Controller) GetUsage(ctx context.Context, req entity.GetUsageRequest) (*entity.GetUsageResponse, error) {
+	if c.GetUsageFunc == nil {
+		return nil, notScripted("GetUsage")
+	}
+	return c.GetUsageFunc(ctx, req)
+}
+
+// Ready defaults to reporting ready so handler tests don't have to script it.
+func (c *Controller) Ready(ctx context.Context) error {
+	if c.ReadyFunc == nil {
+		return nil
+	}
+	return c.ReadyFunc(ctx)
+}
@@ -0,0 +1,109 @@
+package ssertest
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/hasmcp/sser/internal/recorder/kv"
+)
+
+// compile-time proof the double keeps tracking the real contract
+var _ kv.Recorder = (*Recorder)(nil)
+
+// ErrNotFound is returned by Get/GetIn for missing keys, mirroring the real
+// recorder backends.
+var ErrNotFound = errors.New("[ssertest] record not found")
+
+// Recorder is an in-memory implementation of the kv recorder contract. The
+// zero value is not usable; construct it with NewRecorder. It is safe for
+// concurrent use.
+type Recorder struct {
+	mutex   sync.RWMutex
+	buckets map[string]map[string][]byte
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{buckets: make(map[string]map[string][]byte)}
+}
+
+func (r *Recorder) ListKeys(ctx context.Context) ([][]byte, error) {
+	return r.ListKeysIn(ctx, "")
+}
+
+func (r *Recorder) Get(ctx context.Context, key []byte) ([]byte, error) {
+	return r.GetIn(ctx, "", key)
+}
+
+func (r *Recorder) Set(ctx context.Context, key, val []byte) error {
+	return r.SetIn(ctx, "", key, val)
+}
+
+func (r *Recorder) Delete(ctx context.Context, key []byte) error {
+	return r.DeleteIn(ctx, "", key)
+}
+
+func (r *Recorder) ListKeysIn(_ context.Context, bucket string) ([][]byte, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	b := r.buckets[bucket]
+	keys := make([][]byte, 0, len(b))
+	for k := range b {
+		keys = append(keys, []byte(k))
+	}
+	return keys, nil
+}
+
+func (r *Recorder) GetIn(_ context.Context, bucket string, key []byte) ([]byte, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	val, ok := r.buckets[bucket][string(key)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return val, nil
+}
+
+func (r *Recorder) SetIn(_ context.Context, bucket string, key, val []byte) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	b, ok := r.buckets[bucket]
+	if !ok {
+		b = make(map[string][]byte)
+		r.buckets[bucket] = b
+	}
+	b[string(key)] = append([]byte(nil), val...)
+	return nil
+}
+
+func (r *Recorder) DeleteIn(_ context.Context, bucket string, key []byte) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.buckets[bucket], string(key))
+	return nil
+}
+
+func (r *Recorder) ListBuckets(_ context.Context) ([]string, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	buckets := make([]string, 0, len(r.buckets))
+	for name := range r.buckets {
+		buckets = append(buckets, name)
+	}
+	return buckets, nil
+}
+
+func (r *Recorder) Ping(_ context.Context) error {
+	return nil
+}
+
+func (r *Recorder) Close() error {
+	return nil
+}
+
+// Len reports how many records a bucket holds; handy for assertions.
+func (r *Recorder) Len(bucket string) int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return len(r.buckets[bucket])
+}
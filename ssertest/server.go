@@ -0,0 +1,90 @@
+package ssertest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+
+	"github.com/hasmcp/sser"
+	"github.com/valyala/fasthttp"
+)
+
+// Server runs a real embedded sser instance on a random loopback port so SDK
+// and integration tests can talk to a live API without external processes.
+type Server struct {
+	// URL is the base address (http://127.0.0.1:<port>) to point clients at.
+	URL string
+	// APIAccessToken and MetricsAccessToken authenticate against the
+	// instance; they are generated when the config left them empty.
+	APIAccessToken     string
+	MetricsAccessToken string
+
+	inner *sser.Server
+	srv   *fasthttp.Server
+	ln    net.Listener
+}
+
+// StartServer boots an embedded server with the given config. A zero Config
+// works: app name, version and access tokens are filled with test defaults.
+// Callers must Close the server when done.
+func StartServer(cfg sser.Config) (*Server, error) {
+	if cfg.AppName == "" {
+		cfg.AppName = "ssertest"
+	}
+	if cfg.AppVersion == "" {
+		cfg.AppVersion = "0.0.0"
+	}
+	if cfg.ApiAccessToken == "" {
+		cfg.ApiAccessToken = randomToken()
+	}
+	if cfg.MetricsAccessToken == "" {
+		cfg.MetricsAccessToken = randomToken()
+	}
+
+	inner, err := sser.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		_ = inner.Close()
+		return nil, err
+	}
+
+	srv := &fasthttp.Server{
+		Handler: inner.RequestHandler(),
+		Name:    cfg.AppName,
+	}
+	go func() { _ = srv.Serve(ln) }()
+
+	return &Server{
+		URL:                "http://" + ln.Addr().String(),
+		APIAccessToken:     cfg.ApiAccessToken,
+		MetricsAccessToken: cfg.MetricsAccessToken,
+		inner:              inner,
+		srv:                srv,
+		ln:                 ln,
+	}, nil
+}
+
+// Embedded exposes the underlying embedded server for programmatic topic
+// management (creating topics, publishing without HTTP).
+func (s *Server) Embedded() *sser.Server {
+	return s.inner
+}
+
+// Close shuts the listener down and releases the embedded server.
+func (s *Server) Close() error {
+	err := s.srv.Shutdown()
+	if cerr := s.inner.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func randomToken() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,6 @@
+// Package ssertest provides hermetic test doubles for applications that embed
+// sser or talk to it through the ssergo SDK: an in-memory recorder, a
+// scriptable pubsub controller fake, and a real embedded server bound to a
+// random local port. Nothing in here touches the network beyond the loopback
+// interface, so tests stay fast and isolated.
+package ssertest